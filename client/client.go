@@ -0,0 +1,206 @@
+// Package client is a typed Go client for the REST API of the task service.
+//
+//	tasks := client.NewTasksClient("http://localhost:8080/service/v1", client.WithUser("alice"))
+//	id, err := tasks.Create(ctx, &client.TaskInput{Title: "Write the report"})
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// UserHeader carries the ID of the calling user.
+	UserHeader = "X-User-ID"
+
+	DefaultMaxAttempts    = 3
+	DefaultInitialBackoff = 200 * time.Millisecond
+	DefaultMaxBackoff     = 5 * time.Second
+)
+
+// RetryPolicy controls how requests that failed with a network error, 429 or
+// a 502, 503 or 504 are retried. Requests creating resources are not
+// retried, since the first attempt may have succeeded.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts including the first one. One
+	// disables retries.
+	MaxAttempts int
+	// InitialBackoff is the wait before the second attempt; it doubles on
+	// every further attempt up to MaxBackoff. A Retry-After header sent by
+	// the server takes precedence.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetryPolicy is used unless WithRetryPolicy is given.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    DefaultMaxAttempts,
+	InitialBackoff: DefaultInitialBackoff,
+	MaxBackoff:     DefaultMaxBackoff,
+}
+
+// Option configures a TasksClient.
+type Option func(*TasksClient)
+
+// WithHTTPClient sends the requests with httpClient instead of
+// http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *TasksClient) {
+		c.http = httpClient
+	}
+}
+
+// WithUser sends requests on behalf of user.
+func WithUser(user string) Option {
+	return func(c *TasksClient) {
+		c.user = user
+	}
+}
+
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *TasksClient) {
+		c.retry = policy
+	}
+}
+
+// TasksClient calls the task endpoints of the REST API. It is safe for
+// concurrent use.
+type TasksClient struct {
+	baseURL string
+	http    *http.Client
+	user    string
+	retry   RetryPolicy
+}
+
+// NewTasksClient returns a client for the API served at baseURL, such as
+// http://localhost:8080/service/v1.
+func NewTasksClient(baseURL string, opts ...Option) *TasksClient {
+	c := &TasksClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		http:    http.DefaultClient,
+		retry:   DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.retry.MaxAttempts < 1 {
+		c.retry.MaxAttempts = 1
+	}
+	if c.retry.InitialBackoff <= 0 {
+		c.retry.InitialBackoff = DefaultInitialBackoff
+	}
+	if c.retry.MaxBackoff <= 0 {
+		c.retry.MaxBackoff = DefaultMaxBackoff
+	}
+	return c
+}
+
+// do sends a request with in as its JSON body and decodes the response into
+// out, retrying as the retry policy allows. Error responses are returned as
+// *Error.
+func (c *TasksClient) do(ctx context.Context, method, path string, query url.Values, in, out interface{}) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	attempts := c.retry.MaxAttempts
+	if method == http.MethodPost {
+		attempts = 1
+	}
+	for attempt := 1; ; attempt++ {
+		wait, err := c.send(ctx, method, target, body, out)
+		if err == nil || attempt >= attempts || wait < 0 {
+			return err
+		}
+		if wait == 0 {
+			wait = c.backoff(attempt)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// send makes one attempt. On failure it also returns how long to wait before
+// retrying: zero for the policy's backoff, or negative when the request must
+// not be retried.
+func (c *TasksClient) send(ctx context.Context, method, target string, body []byte, out interface{}) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return -1, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.user != "" {
+		req.Header.Set(UserHeader, c.user)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return -1, ctx.Err()
+		}
+		return 0, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := newError(resp.StatusCode, data)
+		if !apiErr.Temporary() {
+			return -1, apiErr
+		}
+		return retryAfter(resp.Header.Get("Retry-After")), apiErr
+	}
+	if out == nil {
+		return 0, nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return -1, fmt.Errorf("client: decoding %s %s: %w", method, req.URL.Path, err)
+	}
+	return 0, nil
+}
+
+// backoff returns the wait after the given number of failed attempts.
+func (c *TasksClient) backoff(attempts int) time.Duration {
+	wait := c.retry.InitialBackoff
+	for i := 1; i < attempts && wait < c.retry.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, c.retry.MaxBackoff)
+}
+
+// retryAfter reads a Retry-After header given in seconds, returning zero
+// when it is missing or is an HTTP date.
+func retryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Error is an error response of the API, which wraps its message in
+// {"error": "..."}. Some responses add fields to the envelope, such as the
+// dependency cycle of a rejected dependency; they are kept in Details.
+type Error struct {
+	StatusCode int
+	Message    string
+	Details    map[string]json.RawMessage
+}
+
+func newError(statusCode int, body []byte) *Error {
+	e := &Error{StatusCode: statusCode}
+	if json.Unmarshal(body, &e.Details) == nil {
+		// Most messages are strings; gRPC statuses are passed through as
+		// objects and carry no message.
+		_ = json.Unmarshal(e.Details["error"], &e.Message)
+		delete(e.Details, "error")
+	}
+	if e.Message == "" {
+		e.Message = http.StatusText(statusCode)
+	}
+	return e
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("client: %d %s", e.StatusCode, e.Message)
+}
+
+// Temporary tells whether the request may succeed when retried.
+func (e *Error) Temporary() bool {
+	switch e.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// IsNotFound tells whether err is a 404 response.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsConflict tells whether err is a 409 response, such as a status
+// transition the workflow does not allow.
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+// IsInvalid tells whether err is a 400 response.
+func IsInvalid(err error) bool {
+	return hasStatus(err, http.StatusBadRequest)
+}
+
+// IsRateLimited tells whether err is a 429 response.
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+func hasStatus(err error, statusCode int) bool {
+	var e *Error
+	return errors.As(err, &e) && e.StatusCode == statusCode
+}
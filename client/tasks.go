@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const defaultPageSize = 50
+
+// Create creates a task and returns its ID.
+func (c *TasksClient) Create(ctx context.Context, in *TaskInput) (string, error) {
+	var resp struct {
+		ID string `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/task", nil, in, &resp); err != nil {
+		return "", err
+	}
+	return resp.ID, nil
+}
+
+func (c *TasksClient) Get(ctx context.Context, id string) (*Task, error) {
+	var task Task
+	if err := c.do(ctx, http.MethodGet, "/task/"+url.PathEscape(id), nil, nil, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// Update writes the title and description of in, clearing them when empty,
+// and the other fields of in that are set. Use Patch to leave the title and
+// description as they are.
+func (c *TasksClient) Update(ctx context.Context, id string, in *TaskInput) error {
+	return c.do(ctx, http.MethodPut, "/task/"+url.PathEscape(id), nil, in, nil)
+}
+
+// Patch changes the fields set in patch and leaves the others as they are.
+func (c *TasksClient) Patch(ctx context.Context, id string, patch *TaskPatch) error {
+	return c.do(ctx, http.MethodPatch, "/task/"+url.PathEscape(id), nil, patch, nil)
+}
+
+func (c *TasksClient) Delete(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/task/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// List returns an iterator over the tasks matching opts, which may be nil.
+// Pages are fetched as the iterator advances.
+//
+//	it := tasks.List(ctx, &client.ListOptions{Assignee: "alice"})
+//	for it.Next() {
+//		fmt.Println(it.Task().Title)
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+func (c *TasksClient) List(ctx context.Context, opts *ListOptions) *TaskIterator {
+	if opts == nil {
+		opts = &ListOptions{}
+	}
+	query := opts.query()
+	pageSize := opts.PageSize
+	if pageSize <= 0 || pageSize > defaultPageSize {
+		pageSize = defaultPageSize
+	}
+	query.Set("pageSize", strconv.Itoa(pageSize))
+	return &TaskIterator{ctx: ctx, client: c, query: query, pageSize: pageSize}
+}
+
+// TaskIterator walks the pages of a List.
+type TaskIterator struct {
+	ctx      context.Context
+	client   *TasksClient
+	query    url.Values
+	pageSize int
+
+	page  int
+	tasks []*Task
+	task  *Task
+	done  bool
+	err   error
+}
+
+// Next advances to the next task, fetching the next page when needed. It
+// returns false when the tasks are exhausted or a request failed.
+func (it *TaskIterator) Next() bool {
+	for len(it.tasks) == 0 {
+		if it.done || it.err != nil {
+			it.task = nil
+			return false
+		}
+		it.fetch()
+	}
+	it.task, it.tasks = it.tasks[0], it.tasks[1:]
+	return true
+}
+
+func (it *TaskIterator) fetch() {
+	it.query.Set("page", strconv.Itoa(it.page))
+	var resp struct {
+		Results []*Task `json:"results"`
+	}
+	if err := it.client.do(it.ctx, http.MethodGet, "/tasks", it.query, nil, &resp); err != nil {
+		it.err = err
+		return
+	}
+	it.page++
+	it.tasks = resp.Results
+	// A short page is the last one.
+	it.done = len(resp.Results) < it.pageSize
+}
+
+// Task returns the current task.
+func (it *TaskIterator) Task() *Task {
+	return it.task
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *TaskIterator) Err() error {
+	return it.err
+}
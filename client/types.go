@@ -0,0 +1,192 @@
+package client
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Task is a task as returned by the API.
+type Task struct {
+	ID          string        `json:"id"`
+	ParentID    string        `json:"parent_id,omitempty"`
+	Title       string        `json:"title"`
+	Description string        `json:"description,omitempty"`
+	Status      string        `json:"status,omitempty"`
+	Priority    string        `json:"priority,omitempty"`
+	DueAt       time.Time     `json:"due_at,omitempty"`
+	Assignee    string        `json:"assignee,omitempty"`
+	Reporter    string        `json:"reporter,omitempty"`
+	Labels      []string      `json:"labels,omitempty"`
+	Attachments []*Attachment `json:"attachments,omitempty"`
+	BlockedBy   []string      `json:"blocked_by,omitempty"`
+	// Blocked is set when a task in BlockedBy is not finished yet.
+	Blocked        bool      `json:"blocked"`
+	RRule          string    `json:"rrule,omitempty"`
+	Timezone       string    `json:"timezone,omitempty"`
+	RecurrenceMode string    `json:"recurrence_mode,omitempty"`
+	SeriesID       string    `json:"series_id,omitempty"`
+	ExternalID     string    `json:"external_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at,omitempty"`
+	UpdatedAt      time.Time `json:"updated_at,omitempty"`
+}
+
+type Attachment struct {
+	ID          string    `json:"id"`
+	TaskID      string    `json:"task_id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	SHA256      string    `json:"sha256"`
+	UploadedBy  string    `json:"uploaded_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at,omitempty"`
+}
+
+type Comment struct {
+	ID         string    `json:"id"`
+	TaskID     string    `json:"task_id"`
+	ParentID   string    `json:"parent_id,omitempty"`
+	Author     string    `json:"author"`
+	Body       string    `json:"body"`
+	ReplyCount int32     `json:"reply_count"`
+	CreatedAt  time.Time `json:"created_at,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at,omitempty"`
+}
+
+type Reminder struct {
+	ID        string    `json:"id"`
+	TaskID    string    `json:"task_id"`
+	RemindAt  time.Time `json:"remind_at,omitempty"`
+	BeforeDue string    `json:"before_due,omitempty"`
+	Channels  []string  `json:"channels"`
+	Recipient string    `json:"recipient"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	FireAt    time.Time `json:"fire_at,omitempty"`
+	FiredAt   time.Time `json:"fired_at,omitempty"`
+}
+
+// TaskInput holds the writable fields of a task. DueAt is RFC 3339 or a
+// date-only value in Timezone, which is also the time zone RRule is expanded
+// in. ParentID and ExternalID are only read on creation.
+type TaskInput struct {
+	Title          string `json:"title"`
+	Description    string `json:"description"`
+	Status         string `json:"status,omitempty"`
+	DueAt          string `json:"due_at,omitempty"`
+	Timezone       string `json:"timezone,omitempty"`
+	Priority       string `json:"priority,omitempty"`
+	Assignee       string `json:"assignee,omitempty"`
+	Reporter       string `json:"reporter,omitempty"`
+	RRule          string `json:"rrule,omitempty"`
+	RecurrenceMode string `json:"recurrence_mode,omitempty"`
+	ParentID       string `json:"parent_id,omitempty"`
+	ExternalID     string `json:"external_id,omitempty"`
+}
+
+// TaskPatch holds the task fields to change; nil fields are left as they
+// are. Use String to fill them in.
+type TaskPatch struct {
+	Title          *string `json:"title,omitempty"`
+	Description    *string `json:"description,omitempty"`
+	Status         *string `json:"status,omitempty"`
+	DueAt          *string `json:"due_at,omitempty"`
+	Timezone       *string `json:"timezone,omitempty"`
+	Priority       *string `json:"priority,omitempty"`
+	Assignee       *string `json:"assignee,omitempty"`
+	Reporter       *string `json:"reporter,omitempty"`
+	RRule          *string `json:"rrule,omitempty"`
+	RecurrenceMode *string `json:"recurrence_mode,omitempty"`
+}
+
+// String returns a pointer to s, for the fields of TaskPatch.
+func String(s string) *string {
+	return &s
+}
+
+// ListOptions filters and orders the tasks returned by List. The zero value
+// lists every task.
+type ListOptions struct {
+	Status   []string
+	Assignee string
+	Reporter string
+	ParentID string
+	Labels   []string
+	// LabelMode is "any" (the default) or "all" of Labels.
+	LabelMode string
+	// StartTime and EndTime bound the creation time of the tasks.
+	StartTime time.Time
+	EndTime   time.Time
+	// DueAfter and DueBefore bound the due time of the tasks.
+	DueAfter  time.Time
+	DueBefore time.Time
+	// Sort is "priority" or "due".
+	Sort string
+	// Timezone is the IANA time zone timestamps are rendered in.
+	Timezone string
+	// PageSize is the number of tasks fetched per request, at most 50.
+	PageSize int
+}
+
+func (o *ListOptions) query() url.Values {
+	q := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+	set("status", strings.Join(o.Status, ","))
+	set("assignee", o.Assignee)
+	set("reporter", o.Reporter)
+	set("parentId", o.ParentID)
+	set("labels", strings.Join(o.Labels, ","))
+	set("labelMode", o.LabelMode)
+	set("sort", o.Sort)
+	set("tz", o.Timezone)
+	if !o.StartTime.IsZero() {
+		q.Set("startTime", o.StartTime.Format(time.RFC3339))
+	}
+	if !o.EndTime.IsZero() {
+		q.Set("endTime", o.EndTime.Format(time.RFC3339))
+	}
+	if !o.DueAfter.IsZero() {
+		q.Set("dueAfter", o.DueAfter.Format(time.RFC3339))
+	}
+	if !o.DueBefore.IsZero() {
+		q.Set("dueBefore", o.DueBefore.Format(time.RFC3339))
+	}
+	if o.PageSize > 0 {
+		q.Set("pageSize", strconv.Itoa(o.PageSize))
+	}
+	return q
+}
+
+// EventType is the type of an Event.
+type EventType string
+
+const (
+	TaskCreated    EventType = "task.created"
+	TaskUpdated    EventType = "task.updated"
+	TaskDeleted    EventType = "task.deleted"
+	TaskAssigned   EventType = "task.assigned"
+	TaskUnassigned EventType = "task.unassigned"
+
+	CommentCreated EventType = "comment.created"
+	CommentUpdated EventType = "comment.updated"
+	CommentDeleted EventType = "comment.deleted"
+
+	ReminderFired EventType = "reminder.fired"
+)
+
+// Event is a change streamed by Watch. Previous is the task before an update.
+type Event struct {
+	Type     EventType `json:"type"`
+	TaskID   string    `json:"task_id"`
+	Actor    string    `json:"actor,omitempty"`
+	Time     time.Time `json:"time"`
+	Task     *Task     `json:"task,omitempty"`
+	Previous *Task     `json:"previous,omitempty"`
+	Comment  *Comment  `json:"comment,omitempty"`
+	Reminder *Reminder `json:"reminder,omitempty"`
+}
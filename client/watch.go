@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// Watcher streams the task events of a WebSocket opened by Watch.
+type Watcher struct {
+	conn      *websocket.Conn
+	closeOnce sync.Once
+	stop      func() bool
+}
+
+// Watch streams the events of the given topics, such as "tasks",
+// "task:<id>" or "assignee:me", over a WebSocket. Without topics every task
+// event is streamed. The stream ends when ctx is done or Close is called.
+// Watch is not retried.
+func (c *TasksClient) Watch(ctx context.Context, topics ...string) (*Watcher, error) {
+	u, err := url.Parse(c.baseURL + "/task/ws")
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	default:
+		u.Scheme = "ws"
+	}
+	if len(topics) > 0 {
+		u.RawQuery = url.Values{"topics": {strings.Join(topics, ",")}}.Encode()
+	}
+
+	header := http.Header{}
+	if c.user != "" {
+		header.Set(UserHeader, c.user)
+	}
+	dialer := *websocket.DefaultDialer
+	if transport, ok := c.http.Transport.(*http.Transport); ok {
+		dialer.Proxy = transport.Proxy
+		dialer.TLSClientConfig = transport.TLSClientConfig
+	}
+	conn, resp, err := dialer.DialContext(ctx, u.String(), header)
+	if err != nil {
+		if resp != nil {
+			defer resp.Body.Close()
+			data, _ := io.ReadAll(resp.Body)
+			return nil, newError(resp.StatusCode, data)
+		}
+		return nil, err
+	}
+
+	w := &Watcher{conn: conn}
+	w.stop = context.AfterFunc(ctx, w.close)
+	return w, nil
+}
+
+// Recv returns the next event. It returns io.EOF once the stream has been
+// closed by Close, its context or the server.
+func (w *Watcher) Recv() (*Event, error) {
+	_, data, err := w.conn.ReadMessage()
+	if err != nil {
+		if errors.Is(err, net.ErrClosed) || websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+	var e Event
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// Close ends the stream.
+func (w *Watcher) Close() error {
+	w.stop()
+	w.close()
+	return nil
+}
+
+func (w *Watcher) close() {
+	w.closeOnce.Do(func() {
+		_ = w.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+		_ = w.conn.Close()
+	})
+}
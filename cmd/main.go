@@ -8,6 +8,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"log"
+	_ "time/tzdata"
 )
 
 func main() {
@@ -24,7 +25,13 @@ func main() {
 	defer conn.Close()
 	grpcClient := grpcpkg.NewTaskServiceClient(conn)
 
-	httpServer := server.NewHTTPServer(grpcClient)
+	httpServer, grpcServer, err := server.NewServers(grpcClient, conf)
+	if err != nil {
+		log.Fatalf("failed to create server: %v", err)
+	}
 
+	if grpcServer != nil {
+		go server.StartGRPC(grpcServer, conf.GatewayGrpc)
+	}
 	server.Start(httpServer)
 }
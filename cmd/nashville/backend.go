@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/bhupeshpandey/task-manager-nashville/client"
+)
+
+// backend is the API the commands run against. Both backends describe tasks
+// with the types of the client package.
+type backend interface {
+	create(ctx context.Context, in *client.TaskInput) (*client.Task, error)
+	get(ctx context.Context, id string) (*client.Task, error)
+	patch(ctx context.Context, id string, patch *client.TaskPatch) (*client.Task, error)
+	remove(ctx context.Context, id string) error
+	// list returns every task matching opts.
+	list(ctx context.Context, opts *client.ListOptions) ([]*client.Task, error)
+	// watch calls fn with the events of topics until ctx is done or fn
+	// fails.
+	watch(ctx context.Context, topics []string, fn func(*client.Event) error) error
+}
+
+// restBackend goes through the REST API of the gateway.
+type restBackend struct {
+	tasks *client.TasksClient
+}
+
+func (b *restBackend) create(ctx context.Context, in *client.TaskInput) (*client.Task, error) {
+	id, err := b.tasks.Create(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return b.tasks.Get(ctx, id)
+}
+
+func (b *restBackend) get(ctx context.Context, id string) (*client.Task, error) {
+	return b.tasks.Get(ctx, id)
+}
+
+func (b *restBackend) patch(ctx context.Context, id string, patch *client.TaskPatch) (*client.Task, error) {
+	if err := b.tasks.Patch(ctx, id, patch); err != nil {
+		return nil, err
+	}
+	return b.tasks.Get(ctx, id)
+}
+
+func (b *restBackend) remove(ctx context.Context, id string) error {
+	return b.tasks.Delete(ctx, id)
+}
+
+func (b *restBackend) list(ctx context.Context, opts *client.ListOptions) ([]*client.Task, error) {
+	var tasks []*client.Task
+	it := b.tasks.List(ctx, opts)
+	for it.Next() {
+		tasks = append(tasks, it.Task())
+	}
+	return tasks, it.Err()
+}
+
+func (b *restBackend) watch(ctx context.Context, topics []string, fn func(*client.Event) error) error {
+	w, err := b.tasks.Watch(ctx, topics...)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	for {
+		e, err := w.Recv()
+		if errors.Is(err, io.EOF) {
+			return ctx.Err()
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+}
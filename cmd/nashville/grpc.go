@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bhupeshpandey/task-manager-nashville/client"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/auth"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/models"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/taskinput"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/workflow"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+const grpcPageSize = 50
+
+// grpcBackend calls TaskService over gRPC. When the configuration has a
+// gatewayGrpc server it goes through the gateway, which checks the calls and
+// publishes their events. Otherwise it calls the gRPC backend directly: it
+// then applies the defaults and the workflow checks of the gateway itself,
+// but publishes no events.
+type grpcBackend struct {
+	client   proto.TaskServiceClient
+	user     string
+	workflow *workflow.StateMachine
+}
+
+func dialGRPC(conf *models.Config, user string) (backend, func(), error) {
+	server := conf.GatewayGrpc
+	if server == nil {
+		server = conf.GrpcServer
+	}
+	if server == nil {
+		return nil, nil, errors.New("the configuration has neither gatewayGrpc nor grpcServer")
+	}
+	stateMachine, err := workflow.New(conf.Workflow)
+	if err != nil {
+		return nil, nil, err
+	}
+	host := server.Host
+	if host == "" {
+		host = "localhost"
+	}
+	conn, err := grpc.NewClient(fmt.Sprintf("%s:%s", host, server.Port), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, nil, err
+	}
+	b := &grpcBackend{client: proto.NewTaskServiceClient(conn), user: user, workflow: stateMachine}
+	return b, func() { conn.Close() }, nil
+}
+
+// context names the user in the metadata of the calls made with ctx.
+func (b *grpcBackend) context(ctx context.Context) context.Context {
+	if b.user == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, auth.UserMetadata, b.user)
+}
+
+func (b *grpcBackend) create(ctx context.Context, in *client.TaskInput) (*client.Task, error) {
+	req := &proto.CreateTaskRequest{
+		Title:       in.Title,
+		Description: in.Description,
+		ParentId:    in.ParentID,
+		Status:      in.Status,
+		Assignee:    in.Assignee,
+		Reporter:    in.Reporter,
+		ExternalId:  in.ExternalID,
+	}
+	if req.Reporter == "" {
+		req.Reporter = b.user
+	}
+	if req.Status == "" {
+		req.Status = b.workflow.Initial()
+	} else if !b.workflow.Valid(req.Status) {
+		return nil, fmt.Errorf("%w %q", workflow.ErrUnknownStatus, req.Status)
+	}
+	var err error
+	// Due dates are read like the gateway reads them, so that both
+	// backends store the same instant.
+	if req.DueAt, err = taskinput.ParseDueAt(in.DueAt, in.Timezone); err != nil {
+		return nil, err
+	}
+	if req.Priority, err = taskinput.ParsePriority(in.Priority); err != nil {
+		return nil, err
+	}
+
+	resp, err := b.client.CreateTask(b.context(ctx), req)
+	if err != nil {
+		return nil, err
+	}
+	return b.get(ctx, resp.Id)
+}
+
+func (b *grpcBackend) get(ctx context.Context, id string) (*client.Task, error) {
+	task, err := b.client.GetTask(b.context(ctx), &proto.GetTaskRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	return fromProto(task), nil
+}
+
+func (b *grpcBackend) patch(ctx context.Context, id string, patch *client.TaskPatch) (*client.Task, error) {
+	req := &proto.UpdateTaskRequest{Id: id, UpdateMask: &fieldmaskpb.FieldMask{}}
+	set := func(path string, value *string, field *string) {
+		if value != nil {
+			*field = *value
+			req.UpdateMask.Paths = append(req.UpdateMask.Paths, path)
+		}
+	}
+	set("title", patch.Title, &req.Title)
+	set("description", patch.Description, &req.Description)
+	set("status", patch.Status, &req.Status)
+	set("assignee", patch.Assignee, &req.Assignee)
+	set("reporter", patch.Reporter, &req.Reporter)
+	var err error
+	if patch.DueAt != nil {
+		timezone := ""
+		if patch.Timezone != nil {
+			timezone = *patch.Timezone
+		}
+		if req.DueAt, err = taskinput.ParseDueAt(*patch.DueAt, timezone); err != nil {
+			return nil, err
+		}
+		req.UpdateMask.Paths = append(req.UpdateMask.Paths, "due_at")
+	}
+	if patch.Priority != nil {
+		if req.Priority, err = taskinput.ParsePriority(*patch.Priority); err != nil {
+			return nil, err
+		}
+		req.UpdateMask.Paths = append(req.UpdateMask.Paths, "priority")
+	}
+	if len(req.UpdateMask.Paths) == 0 {
+		return nil, errors.New("no fields to update")
+	}
+	if patch.Status != nil {
+		task, err := b.get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if task.Status != *patch.Status {
+			if err := b.workflow.Transition(task.Status, *patch.Status); err != nil {
+				return nil, fmt.Errorf("%w; allowed: %s", err, strings.Join(b.workflow.Next(task.Status), ", "))
+			}
+		}
+	}
+
+	if _, err := b.client.UpdateTask(b.context(ctx), req); err != nil {
+		return nil, err
+	}
+	return b.get(ctx, id)
+}
+
+func (b *grpcBackend) remove(ctx context.Context, id string) error {
+	_, err := b.client.DeleteTask(b.context(ctx), &proto.DeleteTaskRequest{Id: id})
+	return err
+}
+
+func (b *grpcBackend) list(ctx context.Context, opts *client.ListOptions) ([]*client.Task, error) {
+	req := &proto.ListTasksRequest{
+		PageSize:  grpcPageSize,
+		Statuses:  opts.Status,
+		Assignee:  opts.Assignee,
+		Reporter:  opts.Reporter,
+		ParentId:  opts.ParentID,
+		Labels:    opts.Labels,
+		LabelMode: opts.LabelMode,
+		SortBy:    opts.Sort,
+	}
+	var tasks []*client.Task
+	for {
+		resp, err := b.client.ListTasks(b.context(ctx), req)
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range resp.Tasks {
+			tasks = append(tasks, fromProto(task))
+		}
+		if len(resp.Tasks) < grpcPageSize {
+			return tasks, nil
+		}
+		req.Page++
+	}
+}
+
+func (b *grpcBackend) watch(context.Context, []string, func(*client.Event) error) error {
+	return errors.New("task events are streamed by the REST API of the gateway; watch with --backend rest")
+}
+
+func fromProto(task *proto.Task) *client.Task {
+	t := &client.Task{
+		ID:             task.Id,
+		ParentID:       task.ParentId,
+		Title:          task.Title,
+		Description:    task.Description,
+		Status:         task.Status,
+		Assignee:       task.Assignee,
+		Reporter:       task.Reporter,
+		Labels:         task.Labels,
+		BlockedBy:      task.BlockedBy,
+		RRule:          task.Rrule,
+		Timezone:       task.Timezone,
+		RecurrenceMode: task.RecurrenceMode,
+		SeriesID:       task.SeriesId,
+		ExternalID:     task.ExternalId,
+		Priority:       taskinput.PriorityName(task.Priority),
+	}
+	if task.DueAt != nil {
+		t.DueAt = task.DueAt.AsTime()
+	}
+	if task.CreatedAt != nil {
+		t.CreatedAt = task.CreatedAt.AsTime()
+	}
+	if task.UpdatedAt != nil {
+		t.UpdatedAt = task.UpdatedAt.AsTime()
+	}
+	return t
+}
@@ -0,0 +1,97 @@
+// Command nashville manages tasks from the command line, through the REST
+// or gRPC API of the gateway, or directly through the gRPC backend, as named
+// in the server's configuration file.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/bhupeshpandey/task-manager-nashville/client"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/config"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/models"
+	"github.com/spf13/cobra"
+)
+
+const (
+	backendREST = "rest"
+	backendGRPC = "grpc"
+
+	// userEnv names the user when --user is not given.
+	userEnv = "NASHVILLE_USER"
+)
+
+// options are the flags shared by every command.
+type options struct {
+	configFile string
+	backend    string
+	server     string
+	user       string
+	output     string
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := newRootCommand().ExecuteContext(ctx); err != nil {
+		os.Exit(1)
+	}
+}
+
+func newRootCommand() *cobra.Command {
+	opts := &options{}
+	root := &cobra.Command{
+		Use:          "nashville",
+		Short:        "Manage tasks of the Nashville task service",
+		SilenceUsage: true,
+	}
+	flags := root.PersistentFlags()
+	flags.StringVar(&opts.configFile, "config", "./config.yaml", "configuration file of the server")
+	flags.StringVar(&opts.backend, "backend", backendREST, "API to use: rest, or grpc (through the gateway when gatewayGrpc is configured)")
+	flags.StringVar(&opts.server, "server", "", "base URL of the REST API (default from the configuration)")
+	flags.StringVar(&opts.user, "user", os.Getenv(userEnv), "user to act as (default $"+userEnv+")")
+	flags.StringVarP(&opts.output, "output", "o", formatTable, "output format: table, json or yaml")
+	_ = root.RegisterFlagCompletionFunc("backend", cobra.FixedCompletions([]string{backendREST, backendGRPC}, cobra.ShellCompDirectiveNoFileComp))
+	_ = root.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(formats, cobra.ShellCompDirectiveNoFileComp))
+
+	root.AddCommand(newTaskCommand(opts), newTUICommand(opts))
+	return root
+}
+
+// connect returns the backend selected by opts, and a function releasing
+// it.
+func (opts *options) connect() (backend, func(), error) {
+	if !isFormat(opts.output) {
+		return nil, nil, fmt.Errorf("unknown output format %q", opts.output)
+	}
+	conf, err := config.LoadConfig(opts.configFile)
+	if err != nil && (opts.backend != backendREST || opts.server == "") {
+		return nil, nil, fmt.Errorf("loading the configuration: %w", err)
+	}
+
+	switch opts.backend {
+	case backendREST:
+		baseURL := opts.server
+		if baseURL == "" {
+			baseURL = restURL(conf.HttpServer)
+		}
+		return &restBackend{tasks: client.NewTasksClient(baseURL, client.WithUser(opts.user))}, func() {}, nil
+	case backendGRPC:
+		return dialGRPC(conf, opts.user)
+	}
+	return nil, nil, fmt.Errorf("unknown backend %q", opts.backend)
+}
+
+// restURL is the base URL of the REST API served as configured in conf.
+func restURL(conf *models.HTTPServer) string {
+	host, port := "localhost", models.DefaultHTTPPort
+	if conf != nil && conf.Host != "" {
+		host = conf.Host
+	}
+	if conf != nil && conf.Port != "" {
+		port = conf.Port
+	}
+	return fmt.Sprintf("http://%s:%s/service/v1", host, port)
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/client"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/taskinput"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+)
+
+var formats = []string{formatTable, formatJSON, formatYAML}
+
+func isFormat(format string) bool {
+	return slices.Contains(formats, format)
+}
+
+// taskRecord is the JSON and YAML output of a task. Unlike client.Task it
+// leaves out unset timestamps.
+type taskRecord struct {
+	ID             string   `json:"id" yaml:"id"`
+	ParentID       string   `json:"parent_id,omitempty" yaml:"parent_id,omitempty"`
+	Title          string   `json:"title" yaml:"title"`
+	Description    string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Status         string   `json:"status,omitempty" yaml:"status,omitempty"`
+	Priority       string   `json:"priority,omitempty" yaml:"priority,omitempty"`
+	DueAt          string   `json:"due_at,omitempty" yaml:"due_at,omitempty"`
+	Assignee       string   `json:"assignee,omitempty" yaml:"assignee,omitempty"`
+	Reporter       string   `json:"reporter,omitempty" yaml:"reporter,omitempty"`
+	Labels         []string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	BlockedBy      []string `json:"blocked_by,omitempty" yaml:"blocked_by,omitempty"`
+	Blocked        bool     `json:"blocked,omitempty" yaml:"blocked,omitempty"`
+	RRule          string   `json:"rrule,omitempty" yaml:"rrule,omitempty"`
+	Timezone       string   `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+	RecurrenceMode string   `json:"recurrence_mode,omitempty" yaml:"recurrence_mode,omitempty"`
+	SeriesID       string   `json:"series_id,omitempty" yaml:"series_id,omitempty"`
+	ExternalID     string   `json:"external_id,omitempty" yaml:"external_id,omitempty"`
+	CreatedAt      string   `json:"created_at,omitempty" yaml:"created_at,omitempty"`
+	UpdatedAt      string   `json:"updated_at,omitempty" yaml:"updated_at,omitempty"`
+	// Children is only filled in by task tree.
+	Children []*taskRecord `json:"children,omitempty" yaml:"children,omitempty"`
+}
+
+func newTaskRecord(t *client.Task) *taskRecord {
+	return &taskRecord{
+		ID:             t.ID,
+		ParentID:       t.ParentID,
+		Title:          t.Title,
+		Description:    t.Description,
+		Status:         t.Status,
+		Priority:       t.Priority,
+		DueAt:          formatTime(t.DueAt),
+		Assignee:       t.Assignee,
+		Reporter:       t.Reporter,
+		Labels:         t.Labels,
+		BlockedBy:      t.BlockedBy,
+		Blocked:        t.Blocked,
+		RRule:          t.RRule,
+		Timezone:       t.Timezone,
+		RecurrenceMode: t.RecurrenceMode,
+		SeriesID:       t.SeriesID,
+		ExternalID:     t.ExternalID,
+		CreatedAt:      formatTime(t.CreatedAt),
+		UpdatedAt:      formatTime(t.UpdatedAt),
+	}
+}
+
+type eventRecord struct {
+	Type   string      `json:"type" yaml:"type"`
+	TaskID string      `json:"task_id" yaml:"task_id"`
+	Actor  string      `json:"actor,omitempty" yaml:"actor,omitempty"`
+	Time   string      `json:"time" yaml:"time"`
+	Task   *taskRecord `json:"task,omitempty" yaml:"task,omitempty"`
+}
+
+func newEventRecord(e *client.Event) *eventRecord {
+	record := &eventRecord{
+		Type:   string(e.Type),
+		TaskID: e.TaskID,
+		Actor:  e.Actor,
+		Time:   formatTime(e.Time),
+	}
+	if e.Task != nil {
+		record.Task = newTaskRecord(e.Task)
+	}
+	return record
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// encode writes v as JSON or YAML.
+func encode(w io.Writer, format string, v interface{}) error {
+	if format == formatYAML {
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// printTasks writes tasks as a table, or as a JSON or YAML list.
+func printTasks(w io.Writer, format string, tasks []*client.Task) error {
+	if format != formatTable {
+		records := make([]*taskRecord, 0, len(tasks))
+		for _, t := range tasks {
+			records = append(records, newTaskRecord(t))
+		}
+		return encode(w, format, records)
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTITLE\tSTATUS\tPRIORITY\tDUE\tASSIGNEE\tLABELS")
+	for _, t := range tasks {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", t.ID, t.Title, t.Status, t.Priority,
+			formatDate(t.DueAt), t.Assignee, strings.Join(t.Labels, ","))
+	}
+	return tw.Flush()
+}
+
+// printTask writes the fields of a task one per line, or as a JSON or YAML
+// object.
+func printTask(w io.Writer, format string, t *client.Task) error {
+	if format != formatTable {
+		return encode(w, format, newTaskRecord(t))
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	row := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(tw, "%s:\t%s\n", name, value)
+		}
+	}
+	row("ID", t.ID)
+	row("Title", t.Title)
+	row("Status", t.Status)
+	row("Priority", t.Priority)
+	row("Due", formatTime(t.DueAt))
+	row("Assignee", t.Assignee)
+	row("Reporter", t.Reporter)
+	row("Parent", t.ParentID)
+	row("Labels", strings.Join(t.Labels, ", "))
+	row("Blocked by", strings.Join(t.BlockedBy, ", "))
+	row("Recurrence", t.RRule)
+	row("Created", formatTime(t.CreatedAt))
+	row("Updated", formatTime(t.UpdatedAt))
+	if t.Description != "" {
+		fmt.Fprintf(tw, "\n%s\n", t.Description)
+	}
+	return tw.Flush()
+}
+
+// formatDate shortens the due dates of tables to the day.
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format(taskinput.DateLayout)
+}
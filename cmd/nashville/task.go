@@ -0,0 +1,332 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bhupeshpandey/task-manager-nashville/client"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/taskinput"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func newTaskCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "task",
+		Short: "Create, list, edit and watch tasks",
+	}
+	cmd.AddCommand(
+		newAddCommand(opts),
+		newListCommand(opts),
+		newShowCommand(opts),
+		newEditCommand(opts),
+		newRemoveCommand(opts),
+		newTreeCommand(opts),
+		newWatchCommand(opts),
+	)
+	return cmd
+}
+
+// run connects to the backend of opts before calling fn.
+func (opts *options) run(fn func(cmd *cobra.Command, b backend, args []string) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		b, release, err := opts.connect()
+		if err != nil {
+			return err
+		}
+		defer release()
+		return fn(cmd, b, args)
+	}
+}
+
+// completeTaskIDs completes task IDs, described by their titles.
+func (opts *options) completeTaskIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	b, release, err := opts.connect()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	defer release()
+	tasks, err := b.list(cmd.Context(), &client.ListOptions{})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	var ids []string
+	for _, t := range tasks {
+		if strings.HasPrefix(t.ID, toComplete) {
+			ids = append(ids, t.ID+"\t"+t.Title)
+		}
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
+
+func newAddCommand(opts *options) *cobra.Command {
+	in := &client.TaskInput{}
+	cmd := &cobra.Command{
+		Use:   "add TITLE...",
+		Short: "Create a task",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: opts.run(func(cmd *cobra.Command, b backend, args []string) error {
+			in.Title = strings.Join(args, " ")
+			task, err := b.create(cmd.Context(), in)
+			if err != nil {
+				return err
+			}
+			return printTask(cmd.OutOrStdout(), opts.output, task)
+		}),
+	}
+	flags := cmd.Flags()
+	flags.StringVarP(&in.Description, "description", "d", "", "description")
+	flags.StringVar(&in.Status, "status", "", "status (default the initial status of the workflow)")
+	flags.StringVar(&in.DueAt, "due", "", "due date, RFC 3339 or YYYY-MM-DD for the end of that day in UTC")
+	flags.StringVarP(&in.Priority, "priority", "p", "", "low, medium, high or urgent")
+	flags.StringVarP(&in.Assignee, "assignee", "a", "", "user the task is assigned to")
+	flags.StringVar(&in.ParentID, "parent", "", "ID of the parent task")
+	_ = cmd.RegisterFlagCompletionFunc("parent", opts.completeTaskIDs)
+	_ = cmd.RegisterFlagCompletionFunc("priority", completePriorities)
+	return cmd
+}
+
+func newListCommand(opts *options) *cobra.Command {
+	list := &client.ListOptions{}
+	cmd := &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "List tasks",
+		Args:    cobra.NoArgs,
+		RunE: opts.run(func(cmd *cobra.Command, b backend, args []string) error {
+			tasks, err := b.list(cmd.Context(), list)
+			if err != nil {
+				return err
+			}
+			return printTasks(cmd.OutOrStdout(), opts.output, tasks)
+		}),
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&list.ParentID, "parent", "", "only the children of this task")
+	flags.StringSliceVar(&list.Status, "status", nil, "only tasks in these statuses")
+	flags.StringVarP(&list.Assignee, "assignee", "a", "", "only tasks assigned to this user")
+	flags.StringVar(&list.Reporter, "reporter", "", "only tasks reported by this user")
+	flags.StringSliceVarP(&list.Labels, "label", "l", nil, "only tasks with any of these labels")
+	flags.StringVar(&list.Sort, "sort", "", "priority or due")
+	_ = cmd.RegisterFlagCompletionFunc("parent", opts.completeTaskIDs)
+	_ = cmd.RegisterFlagCompletionFunc("sort", cobra.FixedCompletions([]string{"priority", "due"}, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+func newShowCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:               "show ID",
+		Short:             "Show a task",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: opts.completeTaskIDs,
+		RunE: opts.run(func(cmd *cobra.Command, b backend, args []string) error {
+			task, err := b.get(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			return printTask(cmd.OutOrStdout(), opts.output, task)
+		}),
+	}
+}
+
+func newEditCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "edit ID",
+		Short:             "Change the fields of a task given as flags",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: opts.completeTaskIDs,
+		RunE: opts.run(func(cmd *cobra.Command, b backend, args []string) error {
+			patch := &client.TaskPatch{}
+			flags := cmd.Flags()
+			for name, field := range map[string]**string{
+				"title":       &patch.Title,
+				"description": &patch.Description,
+				"status":      &patch.Status,
+				"due":         &patch.DueAt,
+				"priority":    &patch.Priority,
+				"assignee":    &patch.Assignee,
+			} {
+				if flags.Changed(name) {
+					value, _ := flags.GetString(name)
+					*field = client.String(value)
+				}
+			}
+			task, err := b.patch(cmd.Context(), args[0], patch)
+			if err != nil {
+				return err
+			}
+			return printTask(cmd.OutOrStdout(), opts.output, task)
+		}),
+	}
+	flags := cmd.Flags()
+	flags.String("title", "", "new title")
+	flags.StringP("description", "d", "", "new description")
+	flags.String("status", "", "new status")
+	flags.String("due", "", "new due date, RFC 3339 or YYYY-MM-DD for the end of that day in UTC")
+	flags.StringP("priority", "p", "", "new priority")
+	flags.StringP("assignee", "a", "", "new assignee, empty to unassign")
+	_ = cmd.RegisterFlagCompletionFunc("priority", completePriorities)
+	return cmd
+}
+
+func newRemoveCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:               "rm ID...",
+		Aliases:           []string{"delete"},
+		Short:             "Delete tasks",
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: opts.completeTaskIDs,
+		RunE: opts.run(func(cmd *cobra.Command, b backend, args []string) error {
+			for _, id := range args {
+				if err := b.remove(cmd.Context(), id); err != nil {
+					return fmt.Errorf("deleting %s: %w", id, err)
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "deleted %s\n", id)
+			}
+			return nil
+		}),
+	}
+}
+
+func newTreeCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:               "tree [ID]",
+		Short:             "Show tasks nested under their parents",
+		Long:              "Show the subtasks of ID, or every task, nested under their parents.",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: opts.completeTaskIDs,
+		RunE: opts.run(func(cmd *cobra.Command, b backend, args []string) error {
+			tasks, err := b.list(cmd.Context(), &client.ListOptions{})
+			if err != nil {
+				return err
+			}
+			roots := buildTree(tasks)
+			if len(args) == 1 {
+				root := findRecord(roots, args[0])
+				if root == nil {
+					return fmt.Errorf("task %s not found", args[0])
+				}
+				roots = []*taskRecord{root}
+			}
+			if opts.output != formatTable {
+				return encode(cmd.OutOrStdout(), opts.output, roots)
+			}
+			printTree(cmd.OutOrStdout(), roots)
+			return nil
+		}),
+	}
+}
+
+// buildTree nests tasks under their parents. Tasks whose parent is not in
+// tasks are roots.
+func buildTree(tasks []*client.Task) []*taskRecord {
+	records := make(map[string]*taskRecord, len(tasks))
+	for _, t := range tasks {
+		records[t.ID] = newTaskRecord(t)
+	}
+	var roots []*taskRecord
+	for _, t := range tasks {
+		record := records[t.ID]
+		if parent, ok := records[t.ParentID]; ok && t.ParentID != t.ID {
+			parent.Children = append(parent.Children, record)
+		} else {
+			roots = append(roots, record)
+		}
+	}
+	return roots
+}
+
+func findRecord(records []*taskRecord, id string) *taskRecord {
+	for _, r := range records {
+		if r.ID == id {
+			return r
+		}
+		if found := findRecord(r.Children, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// printTree draws records and their subtasks like tree(1).
+func printTree(w io.Writer, roots []*taskRecord) {
+	for _, r := range roots {
+		fmt.Fprintln(w, treeLine(r))
+		printBranches(w, r.Children, "")
+	}
+}
+
+func printBranches(w io.Writer, records []*taskRecord, indent string) {
+	for i, r := range records {
+		branch, next := "├── ", "│   "
+		if i == len(records)-1 {
+			branch, next = "└── ", "    "
+		}
+		fmt.Fprintln(w, indent+branch+treeLine(r))
+		printBranches(w, r.Children, indent+next)
+	}
+}
+
+func treeLine(r *taskRecord) string {
+	line := r.Title
+	if r.Status != "" {
+		line += " [" + r.Status + "]"
+	}
+	return line + "  " + r.ID
+}
+
+func newWatchCommand(opts *options) *cobra.Command {
+	var topics []string
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Print task events as they happen",
+		Long: `Print task events as they happen, until interrupted. Topics select the
+events: "tasks" for every task, "task:<id>", "assignee:<user>" or
+"assignee:me".`,
+		Args: cobra.NoArgs,
+		RunE: opts.run(func(cmd *cobra.Command, b backend, args []string) error {
+			err := b.watch(cmd.Context(), topics, watchPrinter(cmd.OutOrStdout(), opts.output))
+			if cmd.Context().Err() != nil {
+				// Interrupted.
+				return nil
+			}
+			return err
+		}),
+	}
+	cmd.Flags().StringSliceVarP(&topics, "topic", "t", nil, "topics to watch (default tasks)")
+	return cmd
+}
+
+// watchPrinter prints events as table rows, JSON lines or YAML documents.
+func watchPrinter(w io.Writer, format string) func(*client.Event) error {
+	switch format {
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		return func(e *client.Event) error {
+			return enc.Encode(newEventRecord(e))
+		}
+	case formatYAML:
+		return func(e *client.Event) error {
+			data, err := yaml.Marshal(newEventRecord(e))
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(w, "---\n%s", data)
+			return err
+		}
+	}
+	return func(e *client.Event) error {
+		title := ""
+		if e.Task != nil {
+			title = e.Task.Title
+		}
+		_, err := fmt.Fprintf(w, "%s  %-16s %s  %s  %s\n", formatTime(e.Time.Local()), e.Type, e.TaskID, title, e.Actor)
+		return err
+	}
+}
+
+func completePriorities(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return taskinput.PriorityNames(), cobra.ShellCompDirectiveNoFileComp
+}
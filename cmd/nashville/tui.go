@@ -0,0 +1,514 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bhupeshpandey/task-manager-nashville/client"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+// detailHeight is the number of lines below the tree given to the selected
+// task, and to the description editor.
+const detailHeight = 7
+
+const tuiHelp = "↑/↓ move  ←/→ fold  enter toggle  e title  d description  r reload  q quit"
+
+var (
+	selectedStyle = lipgloss.NewStyle().Reverse(true)
+	faintStyle    = lipgloss.NewStyle().Faint(true)
+	errorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	headerStyle   = lipgloss.NewStyle().Bold(true)
+)
+
+func newTUICommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "tui",
+		Short: "Browse and edit the task tree interactively",
+		Long: `Browse and edit the task tree interactively. The tree follows the task
+events of the gateway as they happen, which needs the REST backend.`,
+		Args: cobra.NoArgs,
+		RunE: opts.run(func(cmd *cobra.Command, b backend, args []string) error {
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+			p := tea.NewProgram(newTUIModel(ctx, b),
+				tea.WithContext(ctx),
+				tea.WithInput(cmd.InOrStdin()),
+				tea.WithOutput(cmd.OutOrStdout()),
+				tea.WithAltScreen(),
+			)
+			go func() {
+				err := b.watch(ctx, nil, func(e *client.Event) error {
+					p.Send(eventMsg{e})
+					return nil
+				})
+				p.Send(watchDoneMsg{err})
+			}()
+			_, err := p.Run()
+			if cmd.Context().Err() != nil {
+				// Interrupted.
+				return nil
+			}
+			return err
+		}),
+	}
+}
+
+type editMode int
+
+const (
+	browsing editMode = iota
+	editingTitle
+	editingDescription
+)
+
+// tasksMsg carries the tasks of a (re)load.
+type tasksMsg struct {
+	tasks []*client.Task
+	err   error
+}
+
+// savedMsg carries a task after an edit.
+type savedMsg struct {
+	task *client.Task
+	err  error
+}
+
+type eventMsg struct {
+	event *client.Event
+}
+
+// watchDoneMsg reports that the events stopped, with the reason.
+type watchDoneMsg struct {
+	err error
+}
+
+// treeRow is a visible line of the tree.
+type treeRow struct {
+	record *taskRecord
+	depth  int
+}
+
+// tuiModel is the state of the terminal UI. tasks keeps the order of the
+// listing, with created tasks appended; the tree is rebuilt from it.
+type tuiModel struct {
+	ctx context.Context
+	b   backend
+
+	tasks     []*client.Task
+	collapsed map[string]bool
+	rows      []treeRow
+	cursor    int
+	offset    int
+
+	mode        editMode
+	editing     string
+	title       textinput.Model
+	description textarea.Model
+
+	width, height int
+	loading       bool
+	message       string
+	err           error
+	live          bool
+}
+
+func newTUIModel(ctx context.Context, b backend) *tuiModel {
+	title := textinput.New()
+	title.Prompt = ""
+	description := textarea.New()
+	description.ShowLineNumbers = false
+	description.SetHeight(detailHeight)
+	return &tuiModel{
+		ctx:         ctx,
+		b:           b,
+		collapsed:   make(map[string]bool),
+		title:       title,
+		description: description,
+		loading:     true,
+		live:        true,
+	}
+}
+
+func (m *tuiModel) Init() tea.Cmd {
+	return m.load
+}
+
+func (m *tuiModel) load() tea.Msg {
+	tasks, err := m.b.list(m.ctx, &client.ListOptions{})
+	return tasksMsg{tasks: tasks, err: err}
+}
+
+func (m *tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.title.Width = msg.Width - 4
+		m.description.SetWidth(msg.Width)
+		m.scroll()
+		return m, nil
+	case tasksMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.tasks, m.err = msg.tasks, nil
+		m.rebuild()
+		return m, nil
+	case savedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.upsert(msg.task)
+		m.message, m.err = "saved "+msg.task.ID, nil
+		return m, nil
+	case eventMsg:
+		m.apply(msg.event)
+		return m, nil
+	case watchDoneMsg:
+		if m.ctx.Err() == nil {
+			m.live = false
+			m.err = fmt.Errorf("live updates stopped: %w", msg.err)
+		}
+		return m, nil
+	case tea.KeyMsg:
+		switch m.mode {
+		case editingTitle:
+			return m.updateTitle(msg)
+		case editingDescription:
+			return m.updateDescription(msg)
+		}
+		return m.browse(msg)
+	}
+
+	var cmd tea.Cmd
+	switch m.mode {
+	case editingTitle:
+		m.title, cmd = m.title.Update(msg)
+	case editingDescription:
+		m.description, cmd = m.description.Update(msg)
+	}
+	return m, cmd
+}
+
+// browse handles the keys of the tree.
+func (m *tuiModel) browse(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	row := m.selected()
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "up", "k":
+		m.move(-1)
+	case "down", "j":
+		m.move(1)
+	case "pgup":
+		m.move(-m.treeHeight())
+	case "pgdown":
+		m.move(m.treeHeight())
+	case "home", "g":
+		m.move(-len(m.rows))
+	case "end", "G":
+		m.move(len(m.rows))
+	case "right", "l":
+		if row != nil && len(row.record.Children) > 0 {
+			m.collapsed[row.record.ID] = false
+			m.rebuild()
+		}
+	case "left", "h":
+		if row == nil {
+			break
+		}
+		if len(row.record.Children) > 0 && !m.collapsed[row.record.ID] {
+			m.collapsed[row.record.ID] = true
+			m.rebuild()
+		} else {
+			m.selectParent()
+		}
+	case "enter", " ":
+		if row != nil && len(row.record.Children) > 0 {
+			m.collapsed[row.record.ID] = !m.collapsed[row.record.ID]
+			m.rebuild()
+		}
+	case "e":
+		if row != nil {
+			m.mode, m.editing = editingTitle, row.record.ID
+			m.title.SetValue(row.record.Title)
+			m.title.CursorEnd()
+			return m, m.title.Focus()
+		}
+	case "d":
+		if row != nil {
+			m.mode, m.editing = editingDescription, row.record.ID
+			m.description.SetValue(row.record.Description)
+			return m, m.description.Focus()
+		}
+	case "r":
+		m.loading, m.message = true, ""
+		return m, m.load
+	}
+	return m, nil
+}
+
+func (m *tuiModel) updateTitle(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.stopEditing()
+		return m, nil
+	case "enter":
+		title := m.title.Value()
+		m.stopEditing()
+		return m, m.save(&client.TaskPatch{Title: client.String(title)})
+	}
+	var cmd tea.Cmd
+	m.title, cmd = m.title.Update(msg)
+	return m, cmd
+}
+
+func (m *tuiModel) updateDescription(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.stopEditing()
+		return m, nil
+	case "ctrl+s":
+		description := m.description.Value()
+		m.stopEditing()
+		return m, m.save(&client.TaskPatch{Description: client.String(description)})
+	}
+	var cmd tea.Cmd
+	m.description, cmd = m.description.Update(msg)
+	return m, cmd
+}
+
+func (m *tuiModel) stopEditing() {
+	m.mode = browsing
+	m.title.Blur()
+	m.description.Blur()
+}
+
+// save patches the task being edited.
+func (m *tuiModel) save(patch *client.TaskPatch) tea.Cmd {
+	id := m.editing
+	m.message = "saving " + id
+	return func() tea.Msg {
+		task, err := m.b.patch(m.ctx, id, patch)
+		return savedMsg{task: task, err: err}
+	}
+}
+
+// apply updates the tree with a task event.
+func (m *tuiModel) apply(e *client.Event) {
+	switch {
+	case e.Type == client.TaskDeleted:
+		for i, t := range m.tasks {
+			if t.ID == e.TaskID {
+				m.tasks = append(m.tasks[:i], m.tasks[i+1:]...)
+				break
+			}
+		}
+		m.rebuild()
+	case e.Task != nil:
+		m.upsert(e.Task)
+	default:
+		return
+	}
+	m.message = fmt.Sprintf("%s %s", e.Type, e.TaskID)
+	if e.Actor != "" {
+		m.message += " by " + e.Actor
+	}
+}
+
+func (m *tuiModel) upsert(task *client.Task) {
+	for i, t := range m.tasks {
+		if t.ID == task.ID {
+			m.tasks[i] = task
+			m.rebuild()
+			return
+		}
+	}
+	m.tasks = append(m.tasks, task)
+	m.rebuild()
+}
+
+// rebuild lays out the visible rows again, keeping the selected task
+// selected while it is visible.
+func (m *tuiModel) rebuild() {
+	var selected string
+	if row := m.selected(); row != nil {
+		selected = row.record.ID
+	}
+	m.rows = m.rows[:0]
+	m.addRows(buildTree(m.tasks), 0)
+	for i, row := range m.rows {
+		if row.record.ID == selected {
+			m.cursor = i
+			break
+		}
+	}
+	m.move(0)
+}
+
+func (m *tuiModel) addRows(records []*taskRecord, depth int) {
+	for _, r := range records {
+		m.rows = append(m.rows, treeRow{record: r, depth: depth})
+		if !m.collapsed[r.ID] {
+			m.addRows(r.Children, depth+1)
+		}
+	}
+}
+
+func (m *tuiModel) selected() *treeRow {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return nil
+	}
+	return &m.rows[m.cursor]
+}
+
+func (m *tuiModel) selectParent() {
+	depth := m.rows[m.cursor].depth
+	for i := m.cursor - 1; i >= 0; i-- {
+		if m.rows[i].depth < depth {
+			m.cursor = i
+			m.scroll()
+			return
+		}
+	}
+}
+
+// move moves the cursor by n rows, within the tree.
+func (m *tuiModel) move(n int) {
+	m.cursor = min(max(m.cursor+n, 0), max(len(m.rows)-1, 0))
+	m.scroll()
+}
+
+// scroll keeps the cursor on screen.
+func (m *tuiModel) scroll() {
+	height := m.treeHeight()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	} else if m.cursor >= m.offset+height {
+		m.offset = m.cursor - height + 1
+	}
+	m.offset = max(min(m.offset, len(m.rows)-height), 0)
+}
+
+// treeHeight is the number of rows of the tree that fit on screen, below the
+// header and above the details and the status line.
+func (m *tuiModel) treeHeight() int {
+	return max(m.height-detailHeight-4, 1)
+}
+
+func (m *tuiModel) View() string {
+	var s strings.Builder
+	header := "Tasks"
+	if !m.live {
+		header += " (not live)"
+	}
+	s.WriteString(headerStyle.Render(header) + "\n")
+
+	height := m.treeHeight()
+	switch {
+	case m.loading && len(m.rows) == 0:
+		s.WriteString("loading…\n")
+		height--
+	case len(m.rows) == 0:
+		s.WriteString(faintStyle.Render("no tasks") + "\n")
+		height--
+	}
+	for i := m.offset; i < len(m.rows) && i < m.offset+height; i++ {
+		s.WriteString(m.rowView(i) + "\n")
+	}
+	for i := len(m.rows) - m.offset; i < height; i++ {
+		s.WriteString("\n")
+	}
+
+	s.WriteString(faintStyle.Render(strings.Repeat("─", max(m.width, 1))) + "\n")
+	s.WriteString(m.detailView())
+	s.WriteString("\n")
+	switch {
+	case m.err != nil:
+		s.WriteString(errorStyle.Render(m.err.Error()))
+	case m.message != "":
+		s.WriteString(m.message)
+	}
+	s.WriteString("\n" + faintStyle.Render(m.help()))
+	if m.width > 0 {
+		// Cut long titles and descriptions rather than wrapping them.
+		return lipgloss.NewStyle().MaxWidth(m.width).Render(s.String())
+	}
+	return s.String()
+}
+
+func (m *tuiModel) rowView(i int) string {
+	row := m.rows[i]
+	marker := "  "
+	if len(row.record.Children) > 0 {
+		marker = "▾ "
+		if m.collapsed[row.record.ID] {
+			marker = "▸ "
+		}
+	}
+	indent := strings.Repeat("  ", row.depth) + marker
+	if i == m.cursor && m.mode == editingTitle {
+		return indent + m.title.View()
+	}
+	line := indent + row.record.Title
+	if i == m.cursor {
+		line = indent + selectedStyle.Render(row.record.Title)
+	}
+	if row.record.Status != "" {
+		line += " " + faintStyle.Render("["+row.record.Status+"]")
+	}
+	return line
+}
+
+// detailView shows the selected task, or the description being edited, in
+// detailHeight lines.
+func (m *tuiModel) detailView() string {
+	if m.mode == editingDescription {
+		return m.description.View()
+	}
+	row := m.selected()
+	if row == nil {
+		return strings.Repeat("\n", detailHeight-1)
+	}
+	r := row.record
+	var fields []string
+	for _, field := range [][2]string{
+		{"status", r.Status},
+		{"priority", r.Priority},
+		{"due", r.DueAt},
+		{"assignee", r.Assignee},
+		{"labels", strings.Join(r.Labels, ",")},
+	} {
+		if field[1] != "" {
+			fields = append(fields, field[0]+": "+field[1])
+		}
+	}
+	lines := []string{headerStyle.Render(r.Title) + "  " + faintStyle.Render(r.ID), strings.Join(fields, "  ")}
+	if r.Description == "" {
+		lines = append(lines, faintStyle.Render("no description"))
+	} else {
+		lines = append(lines, strings.Split(r.Description, "\n")...)
+	}
+	for len(lines) < detailHeight {
+		lines = append(lines, "")
+	}
+	return strings.Join(lines[:detailHeight], "\n")
+}
+
+func (m *tuiModel) help() string {
+	switch m.mode {
+	case editingTitle:
+		return "enter save  esc cancel"
+	case editingDescription:
+		return "ctrl+s save  esc cancel"
+	}
+	return tuiHelp
+}
@@ -0,0 +1,90 @@
+package activity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ignoredFields are bookkeeping fields left out of diffs.
+var ignoredFields = map[protoreflect.Name]bool{
+	"id":         true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+// Diff returns the task fields that differ between before and after, in
+// field number order. A nil task has every field unset.
+func Diff(before, after *proto.Task) []*proto.FieldChange {
+	var changes []*proto.FieldChange
+	b, a := before.ProtoReflect(), after.ProtoReflect()
+	fields := b.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if ignoredFields[fd.Name()] {
+			continue
+		}
+		from, to := formatField(b, fd), formatField(a, fd)
+		if from != to {
+			changes = append(changes, &proto.FieldChange{Field: string(fd.Name()), Before: from, After: to})
+		}
+	}
+	return changes
+}
+
+// formatField renders a field as text. Lists are comma separated and unset
+// fields are empty.
+func formatField(m protoreflect.Message, fd protoreflect.FieldDescriptor) string {
+	if !m.Has(fd) {
+		return ""
+	}
+	v := m.Get(fd)
+	if !fd.IsList() {
+		return formatValue(fd, v)
+	}
+	list := v.List()
+	parts := make([]string, list.Len())
+	for i := range parts {
+		parts[i] = formatValue(fd, list.Get(i))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatValue renders timestamps as RFC 3339, enum values by their name
+// without the type prefix and other messages by their id.
+func formatValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		value := fd.Enum().Values().ByNumber(v.Enum())
+		if value == nil {
+			return fmt.Sprint(v.Enum())
+		}
+		name := string(value.Name())
+		if i := strings.IndexByte(name, '_'); i >= 0 {
+			name = name[i+1:]
+		}
+		return strings.ToLower(name)
+	case protoreflect.MessageKind:
+		msg := v.Message()
+		if ts, ok := msg.Interface().(*timestamppb.Timestamp); ok {
+			return ts.AsTime().UTC().Format(time.RFC3339)
+		}
+		if id := msg.Descriptor().Fields().ByName("id"); id != nil {
+			return msg.Get(id).String()
+		}
+		return fmt.Sprint(msg.Interface())
+	default:
+		return v.String()
+	}
+}
+
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,111 @@
+// Package activity records the changes made to tasks as an append-only
+// history.
+package activity
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/events"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+)
+
+// Actions of activity entries.
+const (
+	ActionCreated = "created"
+	ActionUpdated = "updated"
+	ActionMoved   = "moved"
+	ActionDeleted = "deleted"
+)
+
+const (
+	recordTimeout = 5 * time.Second
+	// queueSize bounds the events waiting to be recorded.
+	queueSize = 1024
+)
+
+// Recorder stores an activity entry for every task event on the bus.
+// Events are queued as they are published and recorded by Run, so that
+// publishers never wait for the backend.
+type Recorder struct {
+	client proto.TaskServiceClient
+	queue  chan events.Event
+}
+
+// NewRecorder stores activity through client.
+func NewRecorder(client proto.TaskServiceClient) *Recorder {
+	return &Recorder{client: client, queue: make(chan events.Event, queueSize)}
+}
+
+// Observe queues the events published on bus from now on. Events are
+// dropped, and logged, while the queue is full.
+func (r *Recorder) Observe(bus *events.Bus) {
+	bus.Observe(r.enqueue)
+}
+
+func (r *Recorder) enqueue(e events.Event) {
+	select {
+	case r.queue <- e:
+	default:
+		log.Printf("Dropping %s activity of task %s: the activity queue is full", e.Type, e.TaskID)
+	}
+}
+
+// Run records the queued events in the order they were published until ctx
+// is done.
+func (r *Recorder) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-r.queue:
+			r.record(ctx, e)
+		}
+	}
+}
+
+func (r *Recorder) record(ctx context.Context, e events.Event) {
+	activity := NewActivity(&e)
+	if activity == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, recordTimeout)
+	defer cancel()
+	if _, err := r.client.RecordActivity(ctx, &proto.RecordActivityRequest{Activity: activity}); err != nil {
+		log.Printf("Failed to record %s activity of task %s: %v", activity.Action, activity.TaskId, err)
+	}
+}
+
+// NewActivity converts a task event into an activity entry. It returns nil
+// for events that do not change a task, including the assignment events
+// that accompany an update, and for updates that changed nothing.
+func NewActivity(e *events.Event) *proto.Activity {
+	var action string
+	var before, after *proto.Task
+	switch e.Type {
+	case events.TaskCreated:
+		action, after = ActionCreated, e.Task
+	case events.TaskUpdated:
+		action, before, after = ActionUpdated, e.Previous, e.Task
+		if before.GetParentId() != after.GetParentId() {
+			action = ActionMoved
+		}
+	case events.TaskDeleted:
+		action, before = ActionDeleted, e.Task
+	default:
+		return nil
+	}
+
+	changes := Diff(before, after)
+	if action == ActionUpdated && len(changes) == 0 {
+		return nil
+	}
+	return &proto.Activity{
+		TaskId:  e.TaskID,
+		Action:  action,
+		Actor:   e.Actor,
+		Time:    timestamp(e.Time),
+		Changes: changes,
+	}
+}
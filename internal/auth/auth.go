@@ -0,0 +1,24 @@
+package auth
+
+import "context"
+
+// UserHeader carries the id of the authenticated user. It is set by the
+// authenticating proxy in front of the gateway.
+const UserHeader = "X-User-ID"
+
+// UserMetadata carries the id of the authenticated user in gRPC requests.
+const UserMetadata = "x-user-id"
+
+type userKey struct{}
+
+// WithUser returns a copy of ctx carrying the acting user.
+func WithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// UserFromContext returns the acting user, or an empty string when the
+// request is anonymous.
+func UserFromContext(ctx context.Context) string {
+	user, _ := ctx.Value(userKey{}).(string)
+	return user
+}
@@ -0,0 +1,50 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/models"
+)
+
+// ErrNotFound is returned when no object is stored under a key.
+var ErrNotFound = errors.New("blob: object not found")
+
+// Store keeps binary objects under opaque keys. Implementations must be
+// safe for concurrent use. The method set maps onto S3-compatible object
+// stores: Put is a streaming upload and Open is served with ranged reads.
+type Store interface {
+	// Put stores the content of r under key, replacing any previous object.
+	// A failed Put leaves no partial object behind.
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Open returns the object stored under key.
+	Open(ctx context.Context, key string) (Object, error)
+	// Delete removes the object stored under key. Deleting a missing object
+	// is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// Object is the content of a stored object.
+type Object interface {
+	io.ReadSeekCloser
+	Size() int64
+}
+
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+// New creates the Store configured by conf.
+func New(conf *models.BlobStore) (Store, error) {
+	switch conf.Driver {
+	case "", DriverLocal:
+		return NewLocalStore(conf.Path)
+	case DriverS3:
+		return nil, errors.New("blob: the s3 driver is not available yet")
+	default:
+		return nil, fmt.Errorf("blob: unknown driver %q", conf.Driver)
+	}
+}
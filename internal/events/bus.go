@@ -0,0 +1,136 @@
+package events
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+)
+
+type Type string
+
+const (
+	TaskCreated    Type = "task.created"
+	TaskUpdated    Type = "task.updated"
+	TaskDeleted    Type = "task.deleted"
+	TaskAssigned   Type = "task.assigned"
+	TaskUnassigned Type = "task.unassigned"
+
+	CommentCreated Type = "comment.created"
+	CommentUpdated Type = "comment.updated"
+	CommentDeleted Type = "comment.deleted"
+
+	ReminderFired Type = "reminder.fired"
+)
+
+// Types lists every event type.
+var Types = []Type{
+	TaskCreated, TaskUpdated, TaskDeleted, TaskAssigned, TaskUnassigned,
+	CommentCreated, CommentUpdated, CommentDeleted,
+	ReminderFired,
+}
+
+// TopicAll receives every event.
+const TopicAll = "tasks"
+
+// TaskTopic receives the events of a single task.
+func TaskTopic(id string) string {
+	return "task:" + id
+}
+
+// AssigneeTopic receives the events of the tasks assigned to user.
+func AssigneeTopic(user string) string {
+	return "assignee:" + user
+}
+
+// UserTopic receives the notifications addressed to user, such as reminders.
+func UserTopic(user string) string {
+	return "user:" + user
+}
+
+// Event describes a change to a task. Task is the state after the change,
+// or the last known state for deletes; Previous is the state before an
+// update. Comment events carry the comment instead of the task and reminder
+// events carry the reminder along with the task.
+type Event struct {
+	Type     Type
+	TaskID   string
+	Actor    string
+	Time     time.Time
+	Task     *proto.Task
+	Previous *proto.Task
+	Comment  *proto.Comment
+	Reminder *proto.Reminder
+	Topics   []string
+}
+
+// HasTopic reports whether the event is published on topic.
+func (e *Event) HasTopic(topic string) bool {
+	for _, t := range e.Topics {
+		if t == topic {
+			return true
+		}
+	}
+	return false
+}
+
+// Bus fans events out to its subscribers. Slow subscribers lose events
+// instead of blocking publishers. Observers are called by the publisher
+// instead and see every event.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[chan Event]struct{}
+	observers   []func(Event)
+}
+
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[chan Event]struct{})}
+}
+
+// Subscribe returns a channel receiving every published event and a function
+// that cancels the subscription and closes the channel.
+func (b *Bus) Subscribe(buffer int) (<-chan Event, func()) {
+	ch := make(chan Event, buffer)
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Observe calls fn with every published event after it is handed to the
+// subscribers. fn runs on the publishing goroutine, outside the lock of the
+// bus, and must not block: observers doing I/O queue the event instead.
+func (b *Bus) Observe(fn func(Event)) {
+	b.mu.Lock()
+	b.observers = append(b.observers, fn)
+	b.mu.Unlock()
+}
+
+func (b *Bus) Publish(e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	b.mu.RLock()
+	observers := append([]func(Event){}, b.observers...)
+	for ch := range b.subscribers {
+		select {
+		case ch <- e:
+		default:
+			log.Printf("Dropping %s event of task %s for a slow subscriber", e.Type, e.TaskID)
+		}
+	}
+	b.mu.RUnlock()
+	for _, fn := range observers {
+		fn(e)
+	}
+}
@@ -0,0 +1,187 @@
+package events
+
+import (
+	"context"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/auth"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"google.golang.org/grpc"
+)
+
+// publishingClient publishes an event for every successful mutation made
+// through the wrapped client. It reads the task around each change so that
+// subscribers receive its full state.
+type publishingClient struct {
+	proto.TaskServiceClient
+	bus *Bus
+}
+
+// NewClient wraps grpcClient so that task mutations are published on bus.
+func NewClient(grpcClient proto.TaskServiceClient, bus *Bus) proto.TaskServiceClient {
+	return &publishingClient{TaskServiceClient: grpcClient, bus: bus}
+}
+
+func (c *publishingClient) CreateTask(ctx context.Context, in *proto.CreateTaskRequest, opts ...grpc.CallOption) (*proto.CreateTaskResponse, error) {
+	resp, err := c.TaskServiceClient.CreateTask(ctx, in, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	task := c.lookup(ctx, resp.Id)
+	c.publish(ctx, TaskCreated, resp.Id, task, nil)
+	if task.GetAssignee() != "" {
+		c.publish(ctx, TaskAssigned, resp.Id, task, nil)
+	}
+	return resp, nil
+}
+
+func (c *publishingClient) UpdateTask(ctx context.Context, in *proto.UpdateTaskRequest, opts ...grpc.CallOption) (*proto.UpdateTaskResponse, error) {
+	previous := c.lookup(ctx, in.Id)
+	resp, err := c.TaskServiceClient.UpdateTask(ctx, in, opts...)
+	if err != nil || !resp.Success {
+		return resp, err
+	}
+
+	task := c.lookup(ctx, in.Id)
+	c.publish(ctx, TaskUpdated, in.Id, task, previous)
+	if previous.GetAssignee() != task.GetAssignee() {
+		if previous.GetAssignee() != "" {
+			c.publish(ctx, TaskUnassigned, in.Id, task, previous)
+		}
+		if task.GetAssignee() != "" {
+			c.publish(ctx, TaskAssigned, in.Id, task, previous)
+		}
+	}
+	return resp, nil
+}
+
+func (c *publishingClient) DeleteTask(ctx context.Context, in *proto.DeleteTaskRequest, opts ...grpc.CallOption) (*proto.DeleteTaskResponse, error) {
+	previous := c.lookup(ctx, in.Id)
+	resp, err := c.TaskServiceClient.DeleteTask(ctx, in, opts...)
+	if err != nil || !resp.Success {
+		return resp, err
+	}
+
+	c.publish(ctx, TaskDeleted, in.Id, previous, nil)
+	return resp, nil
+}
+
+func (c *publishingClient) AttachLabels(ctx context.Context, in *proto.TaskLabelsRequest, opts ...grpc.CallOption) (*proto.TaskLabelsResponse, error) {
+	return c.relabel(ctx, in, func() (*proto.TaskLabelsResponse, error) {
+		return c.TaskServiceClient.AttachLabels(ctx, in, opts...)
+	})
+}
+
+func (c *publishingClient) DetachLabels(ctx context.Context, in *proto.TaskLabelsRequest, opts ...grpc.CallOption) (*proto.TaskLabelsResponse, error) {
+	return c.relabel(ctx, in, func() (*proto.TaskLabelsResponse, error) {
+		return c.TaskServiceClient.DetachLabels(ctx, in, opts...)
+	})
+}
+
+// relabel publishes a task update around a label change.
+func (c *publishingClient) relabel(ctx context.Context, in *proto.TaskLabelsRequest, call func() (*proto.TaskLabelsResponse, error)) (*proto.TaskLabelsResponse, error) {
+	previous := c.lookup(ctx, in.TaskId)
+	resp, err := call()
+	if err != nil {
+		return nil, err
+	}
+
+	c.publish(ctx, TaskUpdated, in.TaskId, c.lookup(ctx, in.TaskId), previous)
+	return resp, nil
+}
+
+func (c *publishingClient) AddDependency(ctx context.Context, in *proto.TaskDependencyRequest, opts ...grpc.CallOption) (*proto.TaskDependenciesResponse, error) {
+	return c.redepend(ctx, in, func() (*proto.TaskDependenciesResponse, error) {
+		return c.TaskServiceClient.AddDependency(ctx, in, opts...)
+	})
+}
+
+func (c *publishingClient) RemoveDependency(ctx context.Context, in *proto.TaskDependencyRequest, opts ...grpc.CallOption) (*proto.TaskDependenciesResponse, error) {
+	return c.redepend(ctx, in, func() (*proto.TaskDependenciesResponse, error) {
+		return c.TaskServiceClient.RemoveDependency(ctx, in, opts...)
+	})
+}
+
+// redepend publishes a task update around a dependency change.
+func (c *publishingClient) redepend(ctx context.Context, in *proto.TaskDependencyRequest, call func() (*proto.TaskDependenciesResponse, error)) (*proto.TaskDependenciesResponse, error) {
+	previous := c.lookup(ctx, in.TaskId)
+	resp, err := call()
+	if err != nil {
+		return nil, err
+	}
+
+	c.publish(ctx, TaskUpdated, in.TaskId, c.lookup(ctx, in.TaskId), previous)
+	return resp, nil
+}
+
+func (c *publishingClient) CreateComment(ctx context.Context, in *proto.CreateCommentRequest, opts ...grpc.CallOption) (*proto.Comment, error) {
+	comment, err := c.TaskServiceClient.CreateComment(ctx, in, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.publishComment(ctx, CommentCreated, comment)
+	return comment, nil
+}
+
+func (c *publishingClient) UpdateComment(ctx context.Context, in *proto.UpdateCommentRequest, opts ...grpc.CallOption) (*proto.Comment, error) {
+	comment, err := c.TaskServiceClient.UpdateComment(ctx, in, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.publishComment(ctx, CommentUpdated, comment)
+	return comment, nil
+}
+
+func (c *publishingClient) DeleteComment(ctx context.Context, in *proto.DeleteCommentRequest, opts ...grpc.CallOption) (*proto.DeleteCommentResponse, error) {
+	comment, err := c.TaskServiceClient.GetComment(ctx, &proto.GetCommentRequest{Id: in.Id})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.TaskServiceClient.DeleteComment(ctx, in, opts...)
+	if err != nil || !resp.Success {
+		return resp, err
+	}
+
+	c.publishComment(ctx, CommentDeleted, comment)
+	return resp, nil
+}
+
+func (c *publishingClient) publishComment(ctx context.Context, t Type, comment *proto.Comment) {
+	c.bus.Publish(Event{
+		Type:    t,
+		TaskID:  comment.TaskId,
+		Actor:   auth.UserFromContext(ctx),
+		Comment: comment,
+		Topics:  []string{TopicAll, TaskTopic(comment.TaskId)},
+	})
+}
+
+// lookup returns the current state of a task, or nil when it cannot be read.
+func (c *publishingClient) lookup(ctx context.Context, id string) *proto.Task {
+	task, err := c.TaskServiceClient.GetTask(ctx, &proto.GetTaskRequest{Id: id})
+	if err != nil {
+		return nil
+	}
+	return task
+}
+
+func (c *publishingClient) publish(ctx context.Context, t Type, id string, task, previous *proto.Task) {
+	topics := []string{TopicAll, TaskTopic(id)}
+	if assignee := task.GetAssignee(); assignee != "" {
+		topics = append(topics, AssigneeTopic(assignee))
+	}
+	if assignee := previous.GetAssignee(); assignee != "" && assignee != task.GetAssignee() {
+		topics = append(topics, AssigneeTopic(assignee))
+	}
+
+	c.bus.Publish(Event{
+		Type:     t,
+		TaskID:   id,
+		Actor:    auth.UserFromContext(ctx),
+		Task:     task,
+		Previous: previous,
+		Topics:   topics,
+	})
+}
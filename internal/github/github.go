@@ -0,0 +1,131 @@
+// Package github reads the issue and pull request events that GitHub
+// delivers to webhooks. Recorded payloads of the supported events are kept
+// in testdata.
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Headers of GitHub webhook requests.
+const (
+	SignatureHeader = "X-Hub-Signature-256"
+	EventHeader     = "X-GitHub-Event"
+	DeliveryHeader  = "X-GitHub-Delivery"
+)
+
+// Event types handled by the receiver.
+const (
+	EventPing        = "ping"
+	EventIssues      = "issues"
+	EventPullRequest = "pull_request"
+)
+
+// Actions of issue and pull request events that change tasks.
+const (
+	ActionOpened   = "opened"
+	ActionEdited   = "edited"
+	ActionClosed   = "closed"
+	ActionReopened = "reopened"
+)
+
+// Actor is recorded as the author of the changes made for GitHub events.
+const Actor = "system:github"
+
+var ErrInvalidSignature = errors.New("invalid " + SignatureHeader + " signature")
+
+// Verify checks that signature, the value of the X-Hub-Signature-256
+// header, is the HMAC-SHA256 of body under secret.
+func Verify(secret string, body []byte, signature string) error {
+	hexSum, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return ErrInvalidSignature
+	}
+	sum, err := hex.DecodeString(hexSum)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(sum, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Event is the part of an issues or pull_request payload the receiver
+// uses. Exactly one of Issue and PullRequest is set.
+type Event struct {
+	Action      string      `json:"action"`
+	Issue       *Item       `json:"issue"`
+	PullRequest *Item       `json:"pull_request"`
+	Repository  *Repository `json:"repository"`
+	Sender      *User       `json:"sender"`
+}
+
+// Item is an issue or a pull request.
+type Item struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	State   string `json:"state"`
+	HTMLURL string `json:"html_url"`
+	User    *User  `json:"user"`
+	Merged  bool   `json:"merged"`
+}
+
+type Repository struct {
+	FullName string `json:"full_name"`
+}
+
+type User struct {
+	Login string `json:"login"`
+}
+
+// Item returns the issue or pull request of the event.
+func (e *Event) Item() *Item {
+	if e.PullRequest != nil {
+		return e.PullRequest
+	}
+	return e.Issue
+}
+
+// Validate checks that the event names its repository and item.
+func (e *Event) Validate() error {
+	item := e.Item()
+	switch {
+	case e.Repository == nil || e.Repository.FullName == "":
+		return errors.New("the payload has no repository")
+	case item == nil || item.Number == 0:
+		return errors.New("the payload has no issue or pull request")
+	}
+	return nil
+}
+
+// ExternalID links the task of the event's issue or pull request to it.
+// Issues and pull requests share their numbers within a repository.
+func (e *Event) ExternalID() string {
+	return fmt.Sprintf("github:%s#%d", e.Repository.FullName, e.Item().Number)
+}
+
+// Description is the task description of the item: its body followed by
+// a link back to GitHub.
+func (i *Item) Description() string {
+	if i.Body == "" {
+		return i.HTMLURL
+	}
+	return strings.TrimSpace(i.Body) + "\n\n" + i.HTMLURL
+}
+
+// Reporter is the task reporter of the item, its author on GitHub.
+func (i *Item) Reporter() string {
+	if i.User == nil || i.User.Login == "" {
+		return ""
+	}
+	return "github:" + i.User.Login
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/auth"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+type assignRequest struct {
+	Assignee string `json:"assignee" binding:"required"`
+}
+
+func (h *TaskHandler) assignTask(c *gin.Context) {
+	var req assignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.setAssignee(c, req.Assignee)
+}
+
+func (h *TaskHandler) unassignTask(c *gin.Context) {
+	h.setAssignee(c, "")
+}
+
+// setAssignee writes only the assignee of the task; the publishing client
+// turns the change into task.assigned and task.unassigned events.
+func (h *TaskHandler) setAssignee(c *gin.Context, assignee string) {
+	req := &proto.UpdateTaskRequest{
+		Id:         c.Param("id"),
+		Assignee:   assignee,
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"assignee"}},
+	}
+	res, err := h.grpcClient.UpdateTask(requestContext(c), req)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *TaskHandler) listMyTasks(c *gin.Context) {
+	user := c.GetHeader(auth.UserHeader)
+	if user == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": auth.UserHeader + " header is required"})
+		return
+	}
+	h.respondUserTasks(c, user)
+}
+
+func (h *TaskHandler) listUserTasks(c *gin.Context) {
+	h.respondUserTasks(c, c.Param("id"))
+}
+
+// respondUserTasks lists the tasks assigned to user, or reported by user when
+// the role query parameter is "reporter". The /tasks filters also apply.
+func (h *TaskHandler) respondUserTasks(c *gin.Context, user string) {
+	req, err := h.parseListTasksRequest(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch role := c.Query("role"); role {
+	case "", "assignee":
+		req.Assignee = user
+	case "reporter":
+		req.Reporter = user
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "role must be assignee or reporter"})
+		return
+	}
+	h.respondTasks(c, req)
+}
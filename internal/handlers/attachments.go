@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/auth"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/blob"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/models"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	attachmentFormField = "file"
+	// sniffLength is the number of leading bytes used to detect the type.
+	sniffLength = 512
+	// attachmentTransferTimeout replaces the server timeouts while an
+	// attachment is uploaded or downloaded.
+	attachmentTransferTimeout = 10 * time.Minute
+)
+
+// DefaultAttachments is used when the configuration has no attachments
+// section.
+var DefaultAttachments = &models.Attachments{
+	MaxSize: 25 << 20,
+	AllowedTypes: []string{
+		"image/png", "image/jpeg", "image/gif", "image/webp",
+		"text/plain", "text/csv", "application/json", "application/pdf",
+		"application/zip", "application/x-gzip",
+	},
+	Store: models.BlobStore{Driver: blob.DriverLocal, Path: "./data/attachments"},
+}
+
+type attachmentView struct {
+	ID          string `json:"id"`
+	TaskID      string `json:"task_id"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	SHA256      string `json:"sha256"`
+	UploadedBy  string `json:"uploaded_by,omitempty"`
+	CreatedAt   string `json:"created_at,omitempty"`
+}
+
+func newAttachmentView(attachment *proto.Attachment, loc *time.Location) *attachmentView {
+	return &attachmentView{
+		ID:          attachment.Id,
+		TaskID:      attachment.TaskId,
+		Filename:    attachment.Filename,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		SHA256:      attachment.Sha256,
+		UploadedBy:  attachment.UploadedBy,
+		CreatedAt:   formatTimestamp(attachment.CreatedAt, loc),
+	}
+}
+
+func newAttachmentViews(attachments []*proto.Attachment, loc *time.Location) []*attachmentView {
+	if len(attachments) == 0 {
+		return nil
+	}
+	views := make([]*attachmentView, 0, len(attachments))
+	for _, attachment := range attachments {
+		views = append(views, newAttachmentView(attachment, loc))
+	}
+	return views
+}
+
+// uploadAttachment streams the "file" part of a multipart body into the blob
+// store, enforcing the size and type limits, and records its metadata.
+func (h *TaskHandler) uploadAttachment(c *gin.Context) {
+	taskID := c.Param("id")
+	if _, err := h.grpcClient.GetTask(context.Background(), &proto.GetTaskRequest{Id: taskID}); err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusNotFound, gin.H{"error": s})
+		return
+	}
+	extendDeadlines(c)
+
+	part, err := filePart(c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer part.Close()
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(part, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	head = head[:n]
+	contentType := detectContentType(head, part.Header.Get("Content-Type"))
+	if !h.attachmentTypeAllowed(contentType) {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": fmt.Sprintf("attachments of type %s are not allowed", contentType)})
+		return
+	}
+
+	key, err := newStorageKey(taskID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	hash := sha256.New()
+	body := &io.LimitedReader{R: io.MultiReader(bytes.NewReader(head), part), N: h.attachments.MaxSize + 1}
+	counter := &countingWriter{}
+	if err := h.blobs.Put(c.Request.Context(), key, io.TeeReader(body, io.MultiWriter(hash, counter))); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if counter.n > h.attachments.MaxSize {
+		h.deleteBlob(key)
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("attachments must not exceed %d bytes", h.attachments.MaxSize)})
+		return
+	}
+
+	attachment, err := h.grpcClient.AddAttachment(requestContext(c), &proto.AddAttachmentRequest{
+		Attachment: &proto.Attachment{
+			TaskId:      taskID,
+			Filename:    attachmentFilename(part.FileName()),
+			ContentType: contentType,
+			Size:        counter.n,
+			Sha256:      hex.EncodeToString(hash.Sum(nil)),
+			StorageKey:  key,
+			UploadedBy:  c.GetHeader(auth.UserHeader),
+		},
+	})
+	if err != nil {
+		h.deleteBlob(key)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, newAttachmentView(attachment, time.UTC))
+}
+
+func (h *TaskHandler) listAttachments(c *gin.Context) {
+	res, err := h.grpcClient.ListAttachments(context.Background(), &proto.ListAttachmentsRequest{TaskId: c.Param("id")})
+	if err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": s})
+		return
+	}
+	var resp struct {
+		Results interface{} `json:"results"`
+	}
+
+	views := newAttachmentViews(res.Attachments, time.UTC)
+	if views == nil {
+		views = []*attachmentView{}
+	}
+	resp.Results = views
+	c.JSON(http.StatusOK, resp)
+}
+
+// downloadAttachment serves the attachment content. Range and conditional
+// requests are answered by http.ServeContent using the checksum as ETag.
+func (h *TaskHandler) downloadAttachment(c *gin.Context) {
+	attachment, ok := h.lookupAttachment(c)
+	if !ok {
+		return
+	}
+	obj, err := h.blobs.Open(c.Request.Context(), attachment.StorageKey)
+	if errors.Is(err, blob.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment content is missing"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer obj.Close()
+	extendDeadlines(c)
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", attachment.ContentType)
+	header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	header.Set("ETag", `"`+attachment.Sha256+`"`)
+	header.Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(c.Writer, c.Request, attachment.Filename, attachment.CreatedAt.AsTime(), obj)
+}
+
+func (h *TaskHandler) deleteAttachment(c *gin.Context) {
+	attachment, ok := h.lookupAttachment(c)
+	if !ok {
+		return
+	}
+	resp, err := h.grpcClient.DeleteAttachment(requestContext(c), &proto.DeleteAttachmentRequest{Id: attachment.Id})
+	if err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": s})
+		return
+	}
+
+	h.deleteBlob(attachment.StorageKey)
+	c.JSON(http.StatusOK, resp)
+}
+
+// lookupAttachment fetches the attachment named in the path and checks that
+// it belongs to the task in the path, responding with 404 otherwise.
+func (h *TaskHandler) lookupAttachment(c *gin.Context) (*proto.Attachment, bool) {
+	attachment, err := h.grpcClient.GetAttachment(context.Background(), &proto.GetAttachmentRequest{Id: c.Param("attachmentId")})
+	if err != nil || attachment.TaskId != c.Param("id") {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+		return nil, false
+	}
+	return attachment, true
+}
+
+func (h *TaskHandler) deleteBlob(key string) {
+	if err := h.blobs.Delete(context.Background(), key); err != nil {
+		log.Printf("Failed to delete attachment content %s: %v", key, err)
+	}
+}
+
+func (h *TaskHandler) attachmentTypeAllowed(contentType string) bool {
+	for _, allowed := range h.attachments.AllowedTypes {
+		if allowed == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// filePart returns the file part of a multipart request without buffering
+// the body.
+func filePart(r *http.Request) (*multipart.Part, error) {
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("the %q form field is missing", attachmentFormField)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() == attachmentFormField {
+			return part, nil
+		}
+		part.Close()
+	}
+}
+
+// detectContentType sniffs the type from the leading bytes. The declared
+// type is only used when sniffing cannot tell more than generic text or
+// binary data.
+func detectContentType(head []byte, declared string) string {
+	sniffed, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	declared, _, _ = mime.ParseMediaType(declared)
+	if declared != "" && declared != "application/octet-stream" &&
+		(sniffed == "application/octet-stream" || sniffed == "text/plain") {
+		return declared
+	}
+	return sniffed
+}
+
+func attachmentFilename(name string) string {
+	name = filepath.Base(name)
+	if name == "." || name == string(filepath.Separator) {
+		return "attachment"
+	}
+	return name
+}
+
+func newStorageKey(taskID string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return taskID + "/" + hex.EncodeToString(b), nil
+}
+
+// extendDeadlines lifts the server read and write timeouts for long
+// transfers.
+func extendDeadlines(c *gin.Context) {
+	rc := http.NewResponseController(c.Writer)
+	deadline := time.Now().Add(attachmentTransferTimeout)
+	_ = rc.SetReadDeadline(deadline)
+	_ = rc.SetWriteDeadline(deadline)
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
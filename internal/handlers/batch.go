@@ -0,0 +1,449 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/auth"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/workflow"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+const (
+	maxBatchOperations = 500
+	// batchConcurrency bounds the number of in-flight backend calls of a
+	// best-effort batch.
+	batchConcurrency = 8
+
+	batchOpCreate = "create"
+	batchOpUpdate = "update"
+	batchOpDelete = "delete"
+
+	batchStatusOK         = "ok"
+	batchStatusFailed     = "failed"
+	batchStatusSkipped    = "skipped"
+	batchStatusRolledBack = "rolled_back"
+
+	tempRefPrefix = "$"
+)
+
+type batchRequest struct {
+	// Atomic applies the operations one by one and undoes the applied ones
+	// as soon as one of them fails. It allows a single delete, which runs
+	// last because a deleted task cannot be restored.
+	Atomic     bool             `json:"atomic"`
+	Operations []batchOperation `json:"operations"`
+}
+
+// batchOperation is a single create, update or delete. ID and ParentID can
+// reference a task created earlier in the same batch as "$<temp_id>".
+type batchOperation struct {
+	Op     string `json:"op"`
+	TempID string `json:"temp_id,omitempty"`
+	ID     string `json:"id,omitempty"`
+	createTaskRequest
+
+	// fields records the keys present in the operation so that updates
+	// only write the fields they name.
+	fields map[string]json.RawMessage
+}
+
+func (op *batchOperation) UnmarshalJSON(data []byte) error {
+	type plain batchOperation
+	if err := json.Unmarshal(data, (*plain)(op)); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &op.fields)
+}
+
+type batchResult struct {
+	Index  int    `json:"index"`
+	Op     string `json:"op"`
+	TempID string `json:"temp_id,omitempty"`
+	ID     string `json:"id,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type batchResponse struct {
+	Results []batchResult `json:"results"`
+}
+
+func (h *TaskHandler) tasksAction(c *gin.Context) {
+	switch c.Param("action") {
+	case ":batch":
+		h.batchTasks(c)
+	default:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown action"})
+	}
+}
+
+func (h *TaskHandler) batchTasks(c *gin.Context) {
+	var req batchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	deps, err := planBatch(req.Operations, req.Atomic)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	b := newBatch(h.grpcClient, h.workflow, req.Operations)
+	for i := range req.Operations {
+		op := &req.Operations[i]
+		var err error
+		switch op.Op {
+		case batchOpCreate:
+			b.creates[i], err = h.createRequest(c.GetHeader(auth.UserHeader), &op.createTaskRequest)
+		case batchOpUpdate:
+			b.updates[i], err = h.updateRequest(op.ID, &op.taskInput, op.fields)
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("operation %d: %v", i, err)})
+			return
+		}
+	}
+	if req.Atomic {
+		if !b.runAtomic(requestContext(c)) {
+			c.JSON(http.StatusConflict, batchResponse{Results: b.results})
+			return
+		}
+	} else {
+		b.runBestEffort(requestContext(c), deps)
+	}
+	c.JSON(http.StatusOK, batchResponse{Results: b.results})
+}
+
+// planBatch validates the operations and returns, for every operation, the
+// indexes of the operations that have to complete before it can run. An
+// atomic batch may delete a single task.
+func planBatch(ops []batchOperation, atomic bool) ([][]int, error) {
+	if len(ops) == 0 {
+		return nil, errors.New("operations must not be empty")
+	}
+	if len(ops) > maxBatchOperations {
+		return nil, fmt.Errorf("a batch accepts at most %d operations", maxBatchOperations)
+	}
+
+	created := make(map[string]int)
+	lastTouched := make(map[string]int)
+	deleted := make(map[string]int)
+	deps := make([][]int, len(ops))
+	for i, op := range ops {
+		switch op.Op {
+		case batchOpCreate:
+			if op.ID != "" {
+				return nil, fmt.Errorf("operation %d: create must not set id", i)
+			}
+		case batchOpUpdate, batchOpDelete:
+			if op.ID == "" {
+				return nil, fmt.Errorf("operation %d: %s requires an id", i, op.Op)
+			}
+			if op.TempID != "" {
+				return nil, fmt.Errorf("operation %d: temp_id is only allowed on create", i)
+			}
+			if op.ParentID != "" {
+				return nil, fmt.Errorf("operation %d: parent_id can only be set on create", i)
+			}
+		default:
+			return nil, fmt.Errorf("operation %d: unknown op %q", i, op.Op)
+		}
+
+		for _, ref := range []string{op.ID, op.ParentID} {
+			if ref == "" {
+				continue
+			}
+			if j, ok := deleted[ref]; ok {
+				return nil, fmt.Errorf("operation %d: task %q is deleted by operation %d", i, ref, j)
+			}
+			name, ok := tempRef(ref)
+			if !ok {
+				continue
+			}
+			j, ok := created[name]
+			if !ok {
+				return nil, fmt.Errorf("operation %d: %q does not reference an earlier create", i, ref)
+			}
+			deps[i] = append(deps[i], j)
+		}
+		if op.ID != "" {
+			// Operations on the same task run in request order.
+			if j, ok := lastTouched[op.ID]; ok {
+				deps[i] = append(deps[i], j)
+			}
+			lastTouched[op.ID] = i
+		}
+		if op.Op == batchOpDelete {
+			if atomic && len(deleted) > 0 {
+				return nil, fmt.Errorf("operation %d: an atomic batch can delete at most one task", i)
+			}
+			deleted[op.ID] = i
+		}
+		if op.TempID != "" {
+			if _, ok := created[op.TempID]; ok {
+				return nil, fmt.Errorf("operation %d: duplicate temp_id %q", i, op.TempID)
+			}
+			created[op.TempID] = i
+			lastTouched[tempRefPrefix+op.TempID] = i
+		}
+	}
+	return deps, nil
+}
+
+func tempRef(ref string) (string, bool) {
+	if !strings.HasPrefix(ref, tempRefPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(ref, tempRefPrefix), true
+}
+
+type batch struct {
+	grpcClient proto.TaskServiceClient
+	workflow   *workflow.StateMachine
+	ops        []batchOperation
+	results    []batchResult
+
+	// creates and updates hold the converted backend requests by operation
+	// index. Task references are resolved when the operation runs.
+	creates map[int]*proto.CreateTaskRequest
+	updates map[int]*proto.UpdateTaskRequest
+
+	mu  sync.Mutex
+	ids map[string]string // temp id -> created task id
+}
+
+func newBatch(grpcClient proto.TaskServiceClient, stateMachine *workflow.StateMachine, ops []batchOperation) *batch {
+	results := make([]batchResult, len(ops))
+	for i, op := range ops {
+		results[i] = batchResult{Index: i, Op: op.Op, TempID: op.TempID, Status: batchStatusSkipped}
+	}
+	return &batch{
+		grpcClient: grpcClient,
+		workflow:   stateMachine,
+		ops:        ops,
+		results:    results,
+		creates:    make(map[int]*proto.CreateTaskRequest),
+		updates:    make(map[int]*proto.UpdateTaskRequest),
+		ids:        make(map[string]string),
+	}
+}
+
+func (b *batch) resolve(ref string) (string, error) {
+	name, ok := tempRef(ref)
+	if !ok {
+		return ref, nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id, ok := b.ids[name]
+	if !ok {
+		return "", fmt.Errorf("task %q was not created", ref)
+	}
+	return id, nil
+}
+
+// apply runs operation i against the backend and records its outcome.
+func (b *batch) apply(ctx context.Context, i int) error {
+	op := b.ops[i]
+	id, err := b.resolve(op.ID)
+	if err != nil {
+		return err
+	}
+	parentID, err := b.resolve(op.ParentID)
+	if err != nil {
+		return err
+	}
+
+	switch op.Op {
+	case batchOpCreate:
+		req := b.creates[i]
+		req.ParentId = parentID
+		resp, err := b.grpcClient.CreateTask(ctx, req)
+		if err != nil {
+			return err
+		}
+		id = resp.Id
+		if op.TempID != "" {
+			b.mu.Lock()
+			b.ids[op.TempID] = id
+			b.mu.Unlock()
+		}
+	case batchOpUpdate:
+		req := b.updates[i]
+		req.Id = id
+		if hasPath(req.UpdateMask, "status") {
+			if _, err := checkStatusChange(ctx, b.grpcClient, b.workflow, id, req.Status); err != nil {
+				return err
+			}
+		}
+		resp, err := b.grpcClient.UpdateTask(ctx, req)
+		if err != nil {
+			return err
+		}
+		if !resp.Success {
+			return fmt.Errorf("task %q was not updated", id)
+		}
+	case batchOpDelete:
+		resp, err := b.grpcClient.DeleteTask(ctx, &proto.DeleteTaskRequest{Id: id})
+		if err != nil {
+			return err
+		}
+		if !resp.Success {
+			return fmt.Errorf("task %q was not deleted", id)
+		}
+	}
+
+	b.results[i].ID = id
+	b.results[i].Status = batchStatusOK
+	return nil
+}
+
+func (b *batch) fail(i int, err error) {
+	b.results[i].Status = batchStatusFailed
+	b.results[i].Error = err.Error()
+}
+
+// runBestEffort applies every operation whose dependencies succeeded, running
+// independent operations in parallel.
+func (b *batch) runBestEffort(ctx context.Context, deps [][]int) {
+	// Dependencies always point at earlier operations, so a single pass
+	// assigns every operation to the wave after its latest dependency.
+	var waves [][]int
+	wave := make([]int, len(b.ops))
+	for i := range b.ops {
+		for _, d := range deps[i] {
+			if wave[d]+1 > wave[i] {
+				wave[i] = wave[d] + 1
+			}
+		}
+		if wave[i] == len(waves) {
+			waves = append(waves, nil)
+		}
+		waves[wave[i]] = append(waves[wave[i]], i)
+	}
+
+	sem := make(chan struct{}, batchConcurrency)
+	for _, ops := range waves {
+		var wg sync.WaitGroup
+		for _, i := range ops {
+			if d, ok := b.failedDependency(deps[i]); ok {
+				b.fail(i, fmt.Errorf("operation %d failed", d))
+				continue
+			}
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(i int) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				if err := b.apply(ctx, i); err != nil {
+					b.fail(i, err)
+				}
+			}(i)
+		}
+		wg.Wait()
+	}
+}
+
+func (b *batch) failedDependency(deps []int) (int, bool) {
+	for _, d := range deps {
+		if b.results[d].Status != batchStatusOK {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// runAtomic applies the operations sequentially, the delete last, and rolls
+// back everything applied so far when one fails. As nothing runs after the
+// delete, a deleted task never has to be restored. It reports whether the
+// whole batch was applied.
+func (b *batch) runAtomic(ctx context.Context) bool {
+	snapshots := make(map[string]*proto.Task)
+	var order, deletes []int
+	for i, op := range b.ops {
+		if op.Op == batchOpDelete {
+			deletes = append(deletes, i)
+			continue
+		}
+		order = append(order, i)
+		if op.Op == batchOpCreate {
+			continue
+		}
+		if _, ok := tempRef(op.ID); ok {
+			continue
+		}
+		if _, ok := snapshots[op.ID]; ok {
+			continue
+		}
+		task, err := b.grpcClient.GetTask(ctx, &proto.GetTaskRequest{Id: op.ID})
+		if err != nil {
+			b.fail(i, err)
+			return false
+		}
+		snapshots[op.ID] = task
+	}
+	order = append(order, deletes...)
+
+	for n, i := range order {
+		if err := b.apply(ctx, i); err != nil {
+			b.fail(i, err)
+			b.rollback(ctx, order[:n], snapshots)
+			return false
+		}
+	}
+	return true
+}
+
+// rollback undoes the applied creates and updates in reverse order.
+func (b *batch) rollback(ctx context.Context, applied []int, snapshots map[string]*proto.Task) {
+	for n := len(applied) - 1; n >= 0; n-- {
+		i := applied[n]
+		op := b.ops[i]
+		result := &b.results[i]
+		snapshot, existing := snapshots[op.ID]
+
+		var err error
+		switch {
+		case op.Op == batchOpCreate:
+			_, err = b.grpcClient.DeleteTask(ctx, &proto.DeleteTaskRequest{Id: result.ID})
+		case !existing:
+			// Updates and deletes of tasks created in this batch are undone
+			// together with the create.
+		case op.Op == batchOpUpdate:
+			_, err = b.grpcClient.UpdateTask(ctx, restoreRequest(snapshot))
+		}
+		if err != nil {
+			b.fail(i, fmt.Errorf("rollback failed: %w", err))
+			continue
+		}
+		result.Status = batchStatusRolledBack
+	}
+}
+
+// restoreRequest writes every field of snapshot back to the task.
+func restoreRequest(snapshot *proto.Task) *proto.UpdateTaskRequest {
+	return &proto.UpdateTaskRequest{
+		Id:             snapshot.Id,
+		Title:          snapshot.Title,
+		Description:    snapshot.Description,
+		Status:         snapshot.Status,
+		DueAt:          snapshot.DueAt,
+		Priority:       snapshot.Priority,
+		Assignee:       snapshot.Assignee,
+		Reporter:       snapshot.Reporter,
+		Rrule:          snapshot.Rrule,
+		Timezone:       snapshot.Timezone,
+		SeriesStart:    snapshot.SeriesStart,
+		RecurrenceMode: snapshot.RecurrenceMode,
+		UpdateMask:     &fieldmaskpb.FieldMask{Paths: append([]string{"title", "description", "timezone", "series_start"}, maskableFields...)},
+	}
+}
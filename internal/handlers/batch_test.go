@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/workflow"
+	"github.com/gin-gonic/gin"
+)
+
+// postBatch posts body to /tasks:batch of a handler backed by tasks.
+func postBatch(t *testing.T, tasks *fakeTaskService, body string) (int, batchResponse) {
+	t.Helper()
+	stateMachine, err := workflow.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewTaskHandler(tasks, stateMachine, nil, nil, nil, nil, nil, nil, nil)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/tasks:action", h.tasksAction)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/tasks:batch", bytes.NewBufferString(body)))
+	var res batchResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &res)
+	return w.Code, res
+}
+
+func TestAtomicBatchDeletesOneTaskLast(t *testing.T) {
+	tasks := newFakeTaskService()
+	for _, title := range []string{"Draft the post", "Review the post"} {
+		if _, err := tasks.CreateTask(context.Background(), &proto.CreateTaskRequest{Title: title, Status: "todo"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	code, _ := postBatch(t, tasks, `{"atomic": true, "operations": [
+		{"op": "delete", "id": "1"},
+		{"op": "delete", "id": "2"}
+	]}`)
+	if code != http.StatusBadRequest {
+		t.Errorf("two deletes: got %d, want 400", code)
+	}
+
+	// The delete is listed first but runs after the illegal status change,
+	// so it never happens.
+	code, res := postBatch(t, tasks, `{"atomic": true, "operations": [
+		{"op": "delete", "id": "2"},
+		{"op": "update", "id": "1", "title": "Publish the post"},
+		{"op": "update", "id": "1", "status": "blocked"}
+	]}`)
+	if code != http.StatusConflict {
+		t.Fatalf("failing batch: got %d %+v, want 409", code, res)
+	}
+	want := []string{batchStatusSkipped, batchStatusRolledBack, batchStatusFailed}
+	for i, result := range res.Results {
+		if result.Status != want[i] {
+			t.Errorf("operation %d: status %q, want %q", i, result.Status, want[i])
+		}
+	}
+	if len(tasks.tasks) != 2 || tasks.tasks["1"].Title != "Draft the post" {
+		t.Errorf("the rolled-back batch left %d tasks, task 1 %+v", len(tasks.tasks), tasks.tasks["1"])
+	}
+
+	code, res = postBatch(t, tasks, `{"atomic": true, "operations": [
+		{"op": "delete", "id": "1"},
+		{"op": "update", "id": "2", "title": "Publish the post"}
+	]}`)
+	if code != http.StatusOK {
+		t.Fatalf("valid batch: got %d %+v, want 200", code, res)
+	}
+	if _, ok := tasks.tasks["1"]; ok || tasks.tasks["2"].Title != "Publish the post" {
+		t.Errorf("the batch was not applied: %v", tasks.tasks)
+	}
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/auth"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/taskinput"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/status"
+)
+
+const maxCommentLength = 10000
+
+type commentRequest struct {
+	Body     string `json:"body" binding:"required"`
+	ParentID string `json:"parent_id"`
+}
+
+type commentView struct {
+	ID         string `json:"id"`
+	TaskID     string `json:"task_id"`
+	ParentID   string `json:"parent_id,omitempty"`
+	Author     string `json:"author"`
+	Body       string `json:"body"`
+	ReplyCount int32  `json:"reply_count"`
+	CreatedAt  string `json:"created_at,omitempty"`
+	UpdatedAt  string `json:"updated_at,omitempty"`
+}
+
+func newCommentView(comment *proto.Comment, loc *time.Location) *commentView {
+	return &commentView{
+		ID:         comment.Id,
+		TaskID:     comment.TaskId,
+		ParentID:   comment.ParentId,
+		Author:     comment.Author,
+		Body:       comment.Body,
+		ReplyCount: comment.ReplyCount,
+		CreatedAt:  formatTimestamp(comment.CreatedAt, loc),
+		UpdatedAt:  formatTimestamp(comment.UpdatedAt, loc),
+	}
+}
+
+// createComment adds a comment, or a reply when parent_id names a comment of
+// the same task. The author is the calling user.
+func (h *TaskHandler) createComment(c *gin.Context) {
+	author := c.GetHeader(auth.UserHeader)
+	if author == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": auth.UserHeader + " header is required"})
+		return
+	}
+	var req commentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateCommentBody(req.Body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	taskID := c.Param("id")
+	if _, err := h.grpcClient.GetTask(context.Background(), &proto.GetTaskRequest{Id: taskID}); err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusNotFound, gin.H{"error": s})
+		return
+	}
+	if req.ParentID != "" {
+		parent, err := h.grpcClient.GetComment(context.Background(), &proto.GetCommentRequest{Id: req.ParentID})
+		if err != nil || parent.TaskId != taskID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("comment %q does not exist on task %q", req.ParentID, taskID)})
+			return
+		}
+	}
+
+	comment, err := h.grpcClient.CreateComment(requestContext(c), &proto.CreateCommentRequest{
+		TaskId:   taskID,
+		ParentId: req.ParentID,
+		Author:   author,
+		Body:     req.Body,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, newCommentView(comment, time.UTC))
+}
+
+func (h *TaskHandler) listComments(c *gin.Context) {
+	h.respondComments(c, "")
+}
+
+func (h *TaskHandler) listReplies(c *gin.Context) {
+	h.respondComments(c, c.Param("commentId"))
+}
+
+// respondComments lists a page of the replies to parentID, or of the
+// top-level comments of the task when parentID is empty.
+func (h *TaskHandler) respondComments(c *gin.Context, parentID string) {
+	vars := c.Request.URL.Query()
+	loc, err := taskinput.LoadLocation(vars.Get("tz"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	page, pageSize := parsePagination(vars)
+
+	res, err := h.grpcClient.ListComments(context.Background(), &proto.ListCommentsRequest{
+		TaskId:   c.Param("id"),
+		ParentId: parentID,
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	})
+	if err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": s})
+		return
+	}
+	comments := make([]*commentView, 0, len(res.Comments))
+	for _, comment := range res.Comments {
+		comments = append(comments, newCommentView(comment, loc))
+	}
+	var resp struct {
+		Results interface{} `json:"results"`
+	}
+
+	resp.Results = comments
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *TaskHandler) updateComment(c *gin.Context) {
+	var req commentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateCommentBody(req.Body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !h.authorizeComment(c) {
+		return
+	}
+
+	comment, err := h.grpcClient.UpdateComment(requestContext(c), &proto.UpdateCommentRequest{
+		Id:   c.Param("commentId"),
+		Body: req.Body,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, newCommentView(comment, time.UTC))
+}
+
+func (h *TaskHandler) deleteComment(c *gin.Context) {
+	if !h.authorizeComment(c) {
+		return
+	}
+
+	resp, err := h.grpcClient.DeleteComment(requestContext(c), &proto.DeleteCommentRequest{Id: c.Param("commentId")})
+	if err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": s})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// authorizeComment checks that the comment belongs to the task in the path
+// and was written by the calling user, responding with an error otherwise.
+func (h *TaskHandler) authorizeComment(c *gin.Context) bool {
+	comment, err := h.grpcClient.GetComment(context.Background(), &proto.GetCommentRequest{Id: c.Param("commentId")})
+	if err != nil || comment.TaskId != c.Param("id") {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return false
+	}
+	if comment.Author != c.GetHeader(auth.UserHeader) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the author can change a comment"})
+		return false
+	}
+	return true
+}
+
+func validateCommentBody(body string) error {
+	if strings.TrimSpace(body) == "" {
+		return errors.New("body must not be blank")
+	}
+	if utf8.RuneCountInString(body) > maxCommentLength {
+		return fmt.Errorf("body must not exceed %d characters", maxCommentLength)
+	}
+	return nil
+}
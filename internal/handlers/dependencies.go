@@ -0,0 +1,348 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/taskinput"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// maxDependencyWalk bounds the number of tasks read while following
+	// blocked_by edges.
+	maxDependencyWalk = 1000
+	// maxOrderTasks bounds the number of tasks GET /tasks/order accepts.
+	maxOrderTasks = 500
+)
+
+var errDependencyWalkTooLong = errors.New("dependency chain is too long")
+
+type dependencyRequest struct {
+	BlockedBy string `json:"blocked_by" binding:"required"`
+}
+
+type dependenciesView struct {
+	BlockedBy []*taskView `json:"blocked_by"`
+	Blocks    []*taskView `json:"blocks"`
+}
+
+// taskCache reads tasks once per request while walking dependencies. A
+// missing task is remembered as nil.
+type taskCache struct {
+	ctx    context.Context
+	client proto.TaskServiceClient
+	tasks  map[string]*proto.Task
+}
+
+func newTaskCache(ctx context.Context, client proto.TaskServiceClient, tasks ...*proto.Task) *taskCache {
+	cache := &taskCache{ctx: ctx, client: client, tasks: make(map[string]*proto.Task, len(tasks))}
+	for _, task := range tasks {
+		cache.tasks[task.Id] = task
+	}
+	return cache
+}
+
+func (tc *taskCache) get(id string) (*proto.Task, error) {
+	if task, ok := tc.tasks[id]; ok {
+		return task, nil
+	}
+	if len(tc.tasks) >= maxDependencyWalk {
+		return nil, errDependencyWalkTooLong
+	}
+	task, err := tc.client.GetTask(tc.ctx, &proto.GetTaskRequest{Id: id})
+	if status.Code(err) == codes.NotFound {
+		task, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	tc.tasks[id] = task
+	return task, nil
+}
+
+// addDependency records that the task is blocked by another task, rejecting
+// edges that would close a cycle.
+func (h *TaskHandler) addDependency(c *gin.Context) {
+	var req dependencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	id := c.Param("id")
+	if req.BlockedBy == id {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a task cannot block itself"})
+		return
+	}
+
+	cache := newTaskCache(context.Background(), h.grpcClient)
+	for _, taskID := range []string{id, req.BlockedBy} {
+		task, err := cache.get(taskID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if task == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("task %q does not exist", taskID)})
+			return
+		}
+	}
+	cycle, err := findDependencyPath(cache, req.BlockedBy, id)
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if cycle != nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "dependency would create a cycle",
+			"cycle": append([]string{id}, cycle...),
+		})
+		return
+	}
+
+	res, err := h.grpcClient.AddDependency(requestContext(c), &proto.TaskDependencyRequest{TaskId: id, BlockedBy: req.BlockedBy})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *TaskHandler) removeDependency(c *gin.Context) {
+	res, err := h.grpcClient.RemoveDependency(requestContext(c), &proto.TaskDependencyRequest{
+		TaskId:    c.Param("id"),
+		BlockedBy: c.Param("blockerId"),
+	})
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// listDependencies returns the tasks blocking the task and the tasks it
+// blocks.
+func (h *TaskHandler) listDependencies(c *gin.Context) {
+	loc, err := taskinput.LoadLocation(c.Query("tz"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx := context.Background()
+	task, err := h.grpcClient.GetTask(ctx, &proto.GetTaskRequest{Id: c.Param("id")})
+	if err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusNotFound, gin.H{"error": s})
+		return
+	}
+	blocks, err := h.grpcClient.ListTasks(ctx, &proto.ListTasksRequest{BlockedBy: task.Id, PageSize: maxOrderTasks})
+	if err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": s})
+		return
+	}
+
+	cache := newTaskCache(ctx, h.grpcClient, task)
+	blockers := make([]*proto.Task, 0, len(task.BlockedBy))
+	for _, blockerID := range task.BlockedBy {
+		blocker, err := cache.get(blockerID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if blocker != nil {
+			blockers = append(blockers, blocker)
+		}
+	}
+	resp := dependenciesView{
+		BlockedBy: newTaskViews(blockers, loc),
+		Blocks:    newTaskViews(blocks.Tasks, loc),
+	}
+	h.markBlocked(cache, resp.BlockedBy)
+	h.markBlocked(cache, resp.Blocks)
+	c.JSON(http.StatusOK, resp)
+}
+
+// orderTasks returns the tasks named in ids, or matching the /tasks filters
+// when ids is absent, so that every task comes after the tasks blocking it,
+// including through tasks outside the set. Independent tasks keep their
+// relative order.
+func (h *TaskHandler) orderTasks(c *gin.Context) {
+	vars := c.Request.URL.Query()
+	loc, err := taskinput.LoadLocation(vars.Get("tz"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx := context.Background()
+	cache := newTaskCache(ctx, h.grpcClient)
+
+	var tasks []*proto.Task
+	if ids := splitList(vars.Get("ids")); len(ids) > 0 {
+		if len(ids) > maxOrderTasks {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("ids must not name more than %d tasks", maxOrderTasks)})
+			return
+		}
+		for _, id := range ids {
+			task, err := cache.get(id)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			if task == nil {
+				c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("task %q does not exist", id)})
+				return
+			}
+			tasks = append(tasks, task)
+		}
+	} else {
+		req, err := h.parseListTasksRequest(vars)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		res, err := h.grpcClient.ListTasks(ctx, req)
+		if err != nil {
+			s, _ := status.FromError(err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": s})
+			return
+		}
+		tasks = res.Tasks
+		for _, task := range tasks {
+			cache.tasks[task.Id] = task
+		}
+	}
+
+	ordered, err := topologicalOrder(cache, tasks)
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	var resp struct {
+		Results interface{} `json:"results"`
+	}
+
+	views := newTaskViews(ordered, loc)
+	h.markBlocked(cache, views)
+	resp.Results = views
+	c.JSON(http.StatusOK, resp)
+}
+
+// findDependencyPath follows blocked_by edges from "from" and returns the
+// path to "to", or nil when "to" is not reachable.
+func findDependencyPath(cache *taskCache, from, to string) ([]string, error) {
+	visited := map[string]bool{}
+	var walk func(id string) ([]string, error)
+	walk = func(id string) ([]string, error) {
+		if id == to {
+			return []string{id}, nil
+		}
+		if visited[id] {
+			return nil, nil
+		}
+		visited[id] = true
+		task, err := cache.get(id)
+		if err != nil || task == nil {
+			return nil, err
+		}
+		for _, next := range task.BlockedBy {
+			path, err := walk(next)
+			if err != nil || path != nil {
+				if path != nil {
+					path = append([]string{id}, path...)
+				}
+				return path, err
+			}
+		}
+		return nil, nil
+	}
+	return walk(from)
+}
+
+// topologicalOrder sorts tasks so that blockers come first. Edges between
+// members of the set are found by walking blocked_by through any task.
+func topologicalOrder(cache *taskCache, tasks []*proto.Task) ([]*proto.Task, error) {
+	index := make(map[string]int, len(tasks))
+	for i, task := range tasks {
+		index[task.Id] = i
+	}
+
+	// waiting[i] counts the members of the set that task i waits for and
+	// dependents[j] lists the members waiting for task j.
+	waiting := make([]int, len(tasks))
+	dependents := make([][]int, len(tasks))
+	for i, task := range tasks {
+		visited := map[string]bool{task.Id: true}
+		pending := append([]string(nil), task.BlockedBy...)
+		for len(pending) > 0 {
+			id := pending[len(pending)-1]
+			pending = pending[:len(pending)-1]
+			if visited[id] {
+				continue
+			}
+			visited[id] = true
+			if j, ok := index[id]; ok {
+				waiting[i]++
+				dependents[j] = append(dependents[j], i)
+				continue
+			}
+			blocker, err := cache.get(id)
+			if err != nil {
+				return nil, err
+			}
+			if blocker != nil {
+				pending = append(pending, blocker.BlockedBy...)
+			}
+		}
+	}
+
+	ordered := make([]*proto.Task, 0, len(tasks))
+	done := make([]bool, len(tasks))
+	for len(ordered) < len(tasks) {
+		next := -1
+		for i := range tasks {
+			if !done[i] && waiting[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			var stuck []string
+			for i, task := range tasks {
+				if !done[i] {
+					stuck = append(stuck, task.Id)
+				}
+			}
+			return nil, fmt.Errorf("tasks %s depend on each other", strings.Join(stuck, ", "))
+		}
+		done[next] = true
+		ordered = append(ordered, tasks[next])
+		for _, i := range dependents[next] {
+			waiting[i]--
+		}
+	}
+	return ordered, nil
+}
+
+// markBlocked flags the views whose blockers are not in a final status.
+// Blockers that no longer exist do not block.
+func (h *TaskHandler) markBlocked(cache *taskCache, views []*taskView) {
+	for _, view := range views {
+		for _, id := range view.BlockedBy {
+			blocker, err := cache.get(id)
+			if err != nil {
+				break
+			}
+			if blocker != nil && !h.workflow.IsFinal(blocker.Status) {
+				view.Blocked = true
+				break
+			}
+		}
+	}
+}
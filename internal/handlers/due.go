@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/taskinput"
+	"github.com/gin-gonic/gin"
+)
+
+// listOverdueTasks lists the tasks that are past their due date and not in a
+// final status, most urgent first.
+func (h *TaskHandler) listOverdueTasks(c *gin.Context) {
+	req, err := h.parseListTasksRequest(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	req.DueBefore = time.Now().UTC().Format(time.RFC3339)
+	req.ExcludeStatuses = h.workflow.FinalStatuses()
+	if req.SortBy == "" {
+		req.SortBy = sortByPriority
+	}
+	h.respondTasks(c, req)
+}
+
+// listDueTasks lists the tasks due between the from and to query parameters.
+// from defaults to now; date-only values cover the whole day.
+func (h *TaskHandler) listDueTasks(c *gin.Context) {
+	vars := c.Request.URL.Query()
+	req, err := h.parseListTasksRequest(vars)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	loc, err := taskinput.LoadLocation(vars.Get("tz"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if vars.Get("to") == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to is required"})
+		return
+	}
+	if req.DueBefore, err = parseTimeParam(vars, "to", loc, true); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.DueAfter = time.Now().UTC().Format(time.RFC3339)
+	if vars.Get("from") != "" {
+		if req.DueAfter, err = parseTimeParam(vars, "from", loc, false); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.DueBefore < req.DueAfter {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to cannot be before from"})
+		return
+	}
+	req.ExcludeStatuses = h.workflow.FinalStatuses()
+	if req.SortBy == "" {
+		req.SortBy = sortByDue
+	}
+	h.respondTasks(c, req)
+}
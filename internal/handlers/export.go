@@ -0,0 +1,347 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/taskinput"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	exportFormatCSV    = "csv"
+	exportFormatJSON   = "json"
+	exportFormatNDJSON = "ndjson"
+
+	exportPageSize = 50
+	// parentPathSeparator joins the titles of the ancestors in parent_path.
+	parentPathSeparator = " / "
+	// exportListSeparator joins list values in CSV cells.
+	exportListSeparator = ";"
+)
+
+// exportRow is a task being exported together with the titles of its
+// ancestors.
+type exportRow struct {
+	task       *taskView
+	parentPath string
+}
+
+// exportColumn renders one column of an export. Values are strings or, for
+// list columns, string slices.
+type exportColumn struct {
+	name  string
+	value func(*exportRow) interface{}
+}
+
+var exportColumns = []exportColumn{
+	{"id", func(r *exportRow) interface{} { return r.task.ID }},
+	{"external_id", func(r *exportRow) interface{} { return r.task.ExternalID }},
+	{"parent_id", func(r *exportRow) interface{} { return r.task.ParentID }},
+	{"parent_path", func(r *exportRow) interface{} { return r.parentPath }},
+	{"title", func(r *exportRow) interface{} { return r.task.Title }},
+	{"description", func(r *exportRow) interface{} { return r.task.Description }},
+	{"status", func(r *exportRow) interface{} { return r.task.Status }},
+	{"priority", func(r *exportRow) interface{} { return r.task.Priority }},
+	{"due_at", func(r *exportRow) interface{} { return r.task.DueAt }},
+	{"assignee", func(r *exportRow) interface{} { return r.task.Assignee }},
+	{"reporter", func(r *exportRow) interface{} { return r.task.Reporter }},
+	{"labels", func(r *exportRow) interface{} { return nonNil(r.task.Labels) }},
+	{"blocked_by", func(r *exportRow) interface{} { return nonNil(r.task.BlockedBy) }},
+	{"rrule", func(r *exportRow) interface{} { return r.task.RRule }},
+	{"timezone", func(r *exportRow) interface{} { return r.task.Timezone }},
+	{"series_id", func(r *exportRow) interface{} { return r.task.SeriesID }},
+	{"created_at", func(r *exportRow) interface{} { return r.task.CreatedAt }},
+	{"updated_at", func(r *exportRow) interface{} { return r.task.UpdatedAt }},
+}
+
+var defaultExportColumns = []string{
+	"id", "parent_path", "title", "status", "priority", "due_at",
+	"assignee", "reporter", "labels", "created_at", "updated_at",
+}
+
+// exportTasks streams every task matching the /tasks filters in the format
+// and columns requested. Pages are written as they are read, so a failure
+// part way through truncates the export.
+func (h *TaskHandler) exportTasks(c *gin.Context) {
+	vars := c.Request.URL.Query()
+	req, err := h.parseListTasksRequest(vars)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	loc, err := taskinput.LoadLocation(vars.Get("tz"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	columns, err := parseExportColumns(vars.Get("columns"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	format := vars.Get("format")
+	if format == "" {
+		format = exportFormatCSV
+	}
+	enc, contentType, err := newExportEncoder(format, c.Writer, columns)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	extendDeadlines(c)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="tasks.%s"`, format))
+	c.Status(http.StatusOK)
+
+	ctx := context.Background()
+	cache := newTaskCache(ctx, h.grpcClient)
+	req.PageSize = exportPageSize
+	for req.Page = 0; ; req.Page++ {
+		res, err := h.grpcClient.ListTasks(ctx, req)
+		if err != nil {
+			log.Printf("Export stopped after %d pages: %v", req.Page, err)
+			return
+		}
+		for _, task := range res.Tasks {
+			row := &exportRow{task: newTaskView(task, loc), parentPath: parentPath(cache, task)}
+			if err := enc.write(row); err != nil {
+				log.Printf("Export stopped: %v", err)
+				return
+			}
+		}
+		if err := enc.flush(); err != nil {
+			log.Printf("Export stopped: %v", err)
+			return
+		}
+		c.Writer.Flush()
+		if len(res.Tasks) < exportPageSize {
+			break
+		}
+	}
+	if err := enc.close(); err != nil {
+		log.Printf("Export stopped: %v", err)
+	}
+	c.Writer.Flush()
+}
+
+func parseExportColumns(value string) ([]exportColumn, error) {
+	names := splitList(value)
+	if len(names) == 0 {
+		names = defaultExportColumns
+	}
+	columns := make([]exportColumn, 0, len(names))
+	for _, name := range names {
+		i := exportColumnIndex(name)
+		if i < 0 {
+			available := make([]string, len(exportColumns))
+			for j, column := range exportColumns {
+				available[j] = column.name
+			}
+			return nil, fmt.Errorf("unknown column %q, use %s", name, strings.Join(available, ", "))
+		}
+		columns = append(columns, exportColumns[i])
+	}
+	return columns, nil
+}
+
+func exportColumnIndex(name string) int {
+	for i, column := range exportColumns {
+		if column.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// parentPath joins the titles of the ancestors of task, root first.
+// Ancestors that cannot be read end the path.
+func parentPath(cache *taskCache, task *proto.Task) string {
+	var titles []string
+	for id, depth := task.ParentId, 0; id != "" && depth < maxParentDepth; depth++ {
+		parent, err := cache.get(id)
+		if err != nil || parent == nil {
+			break
+		}
+		titles = append(titles, parent.Title)
+		id = parent.ParentId
+	}
+	for i, j := 0, len(titles)-1; i < j; i, j = i+1, j-1 {
+		titles[i], titles[j] = titles[j], titles[i]
+	}
+	return strings.Join(titles, parentPathSeparator)
+}
+
+// exportEncoder writes rows in one export format.
+type exportEncoder interface {
+	write(row *exportRow) error
+	// flush pushes the buffered rows to the response.
+	flush() error
+	// close ends the export.
+	close() error
+}
+
+func newExportEncoder(format string, w io.Writer, columns []exportColumn) (exportEncoder, string, error) {
+	switch format {
+	case exportFormatCSV:
+		return newCSVEncoder(w, columns), "text/csv; charset=utf-8", nil
+	case exportFormatJSON:
+		return &jsonEncoder{w: w, columns: columns, array: true}, "application/json", nil
+	case exportFormatNDJSON:
+		return &jsonEncoder{w: w, columns: columns}, "application/x-ndjson", nil
+	}
+	return nil, "", fmt.Errorf("unknown format %q, use csv, json or ndjson", format)
+}
+
+type csvEncoder struct {
+	w       *csv.Writer
+	columns []exportColumn
+	header  bool
+}
+
+func newCSVEncoder(w io.Writer, columns []exportColumn) *csvEncoder {
+	return &csvEncoder{w: csv.NewWriter(w), columns: columns}
+}
+
+func (e *csvEncoder) writeHeader() error {
+	e.header = true
+	names := make([]string, len(e.columns))
+	for i, column := range e.columns {
+		names[i] = column.name
+	}
+	return e.w.Write(names)
+}
+
+func (e *csvEncoder) write(row *exportRow) error {
+	if !e.header {
+		if err := e.writeHeader(); err != nil {
+			return err
+		}
+	}
+	record := make([]string, len(e.columns))
+	for i, column := range e.columns {
+		switch v := column.value(row).(type) {
+		case []string:
+			record[i] = csvCell(strings.Join(v, exportListSeparator))
+		case string:
+			record[i] = csvCell(v)
+		}
+	}
+	return e.w.Write(record)
+}
+
+func (e *csvEncoder) flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvEncoder) close() error {
+	if !e.header {
+		if err := e.writeHeader(); err != nil {
+			return err
+		}
+	}
+	return e.flush()
+}
+
+// csvFormulaStart holds the characters that make spreadsheets read a cell
+// as a formula.
+const csvFormulaStart = "=+-@\t\r"
+
+// csvCell keeps spreadsheets from evaluating user text as a formula by
+// prefixing cells that start like one with a quote. Text starting with
+// quotes before such a character gets one more, so that csvCellValue gives
+// back the text unchanged.
+func csvCell(v string) string {
+	if startsLikeFormula(v) {
+		return "'" + v
+	}
+	return v
+}
+
+// csvCellValue undoes csvCell.
+func csvCellValue(v string) string {
+	if strings.HasPrefix(v, "'") && startsLikeFormula(v) {
+		return v[1:]
+	}
+	return v
+}
+
+// startsLikeFormula reports whether v starts like a formula, once its
+// leading quotes are skipped.
+func startsLikeFormula(v string) bool {
+	v = strings.TrimLeft(v, "'")
+	return v != "" && strings.ContainsRune(csvFormulaStart, rune(v[0]))
+}
+
+// jsonEncoder writes one object per row with the columns in order, either
+// as the elements of a JSON array or as newline delimited JSON.
+type jsonEncoder struct {
+	w       io.Writer
+	columns []exportColumn
+	array   bool
+	buf     bytes.Buffer
+	rows    int
+}
+
+func (e *jsonEncoder) write(row *exportRow) error {
+	if e.array {
+		if e.rows == 0 {
+			e.buf.WriteString("[\n")
+		} else {
+			e.buf.WriteString(",\n")
+		}
+	}
+	e.rows++
+	e.buf.WriteByte('{')
+	for i, column := range e.columns {
+		if i > 0 {
+			e.buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(column.name)
+		value, err := json.Marshal(column.value(row))
+		if err != nil {
+			return err
+		}
+		e.buf.Write(name)
+		e.buf.WriteByte(':')
+		e.buf.Write(value)
+	}
+	e.buf.WriteByte('}')
+	if !e.array {
+		e.buf.WriteByte('\n')
+	}
+	return nil
+}
+
+func (e *jsonEncoder) flush() error {
+	_, err := e.w.Write(e.buf.Bytes())
+	e.buf.Reset()
+	return err
+}
+
+func (e *jsonEncoder) close() error {
+	if e.array {
+		if e.rows == 0 {
+			e.buf.WriteString("[")
+		}
+		e.buf.WriteString("\n]\n")
+	}
+	return e.flush()
+}
+
+func nonNil(v []string) []string {
+	if v == nil {
+		return []string{}
+	}
+	return v
+}
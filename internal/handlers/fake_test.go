@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	gproto "google.golang.org/protobuf/proto"
+)
+
+// fakeTaskService keeps tasks in memory. It implements the calls the tests
+// make; the others panic.
+type fakeTaskService struct {
+	proto.TaskServiceClient
+
+	mu    sync.Mutex
+	tasks map[string]*proto.Task
+	next  int
+}
+
+func newFakeTaskService() *fakeTaskService {
+	return &fakeTaskService{tasks: make(map[string]*proto.Task)}
+}
+
+func (f *fakeTaskService) CreateTask(_ context.Context, in *proto.CreateTaskRequest, _ ...grpc.CallOption) (*proto.CreateTaskResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.next++
+	id := strconv.Itoa(f.next)
+	f.tasks[id] = &proto.Task{
+		Id:          id,
+		Title:       in.Title,
+		Description: in.Description,
+		Status:      in.Status,
+		Reporter:    in.Reporter,
+		ParentId:    in.ParentId,
+		ExternalId:  in.ExternalId,
+	}
+	return &proto.CreateTaskResponse{Id: id}, nil
+}
+
+func (f *fakeTaskService) GetTask(_ context.Context, in *proto.GetTaskRequest, _ ...grpc.CallOption) (*proto.Task, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	task, ok := f.tasks[in.Id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "task %s not found", in.Id)
+	}
+	// Like a remote backend, callers get a copy of the task.
+	return gproto.Clone(task).(*proto.Task), nil
+}
+
+func (f *fakeTaskService) UpdateTask(_ context.Context, in *proto.UpdateTaskRequest, _ ...grpc.CallOption) (*proto.UpdateTaskResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	task, ok := f.tasks[in.Id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "task %s not found", in.Id)
+	}
+	for _, path := range in.UpdateMask.GetPaths() {
+		switch path {
+		case "title":
+			task.Title = in.Title
+		case "description":
+			task.Description = in.Description
+		case "status":
+			task.Status = in.Status
+		case "parent_id":
+			task.ParentId = in.ParentId
+		}
+	}
+	return &proto.UpdateTaskResponse{Success: true}, nil
+}
+
+func (f *fakeTaskService) ListTasks(_ context.Context, in *proto.ListTasksRequest, _ ...grpc.CallOption) (*proto.ListTasksResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	res := &proto.ListTasksResponse{}
+	for _, task := range f.tasks {
+		if slices.Contains(in.Ids, task.Id) || slices.Contains(in.ExternalIds, task.ExternalId) {
+			res.Tasks = append(res.Tasks, task)
+		}
+	}
+	return res, nil
+}
+
+func (f *fakeTaskService) DeleteTask(_ context.Context, in *proto.DeleteTaskRequest, _ ...grpc.CallOption) (*proto.DeleteTaskResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.tasks[in.Id]; !ok {
+		return nil, status.Errorf(codes.NotFound, "task %s not found", in.Id)
+	}
+	delete(f.tasks, in.Id)
+	return &proto.DeleteTaskResponse{Success: true}, nil
+}
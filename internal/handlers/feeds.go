@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/auth"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/ical"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// maxFeedTasks caps the tasks of a calendar feed.
+	maxFeedTasks   = 1000
+	feedTokenBytes = 32
+	feedProdID     = "-//Nashville//Task Feed//EN"
+)
+
+type feedRequest struct {
+	Name string `json:"name"`
+	// Filter holds /tasks query parameters such as "labels=work&status=todo".
+	// Without it the feed lists the tasks assigned to the caller.
+	Filter string `json:"filter"`
+}
+
+type feedView struct {
+	ID        string `json:"id"`
+	Name      string `json:"name,omitempty"`
+	Owner     string `json:"owner"`
+	Filter    string `json:"filter,omitempty"`
+	CreatedAt string `json:"created_at,omitempty"`
+	// Token and URL are only returned when the feed is created.
+	Token string `json:"token,omitempty"`
+	URL   string `json:"url,omitempty"`
+}
+
+func newFeedView(t *proto.FeedToken) *feedView {
+	return &feedView{
+		ID:        t.Id,
+		Name:      t.Name,
+		Owner:     t.Owner,
+		Filter:    t.Filter,
+		CreatedAt: formatTimestamp(t.CreatedAt, time.UTC),
+	}
+}
+
+// createFeed issues a secret token for an ICS feed of the caller's tasks, or
+// of the tasks matching filter. The token is only shown in this response.
+func (h *TaskHandler) createFeed(c *gin.Context) {
+	owner := c.GetHeader(auth.UserHeader)
+	if owner == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": auth.UserHeader + " header is required"})
+		return
+	}
+	var req feedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter, err := url.ParseQuery(req.Filter)
+	if err == nil {
+		_, err = h.parseListTasksRequest(filter)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid filter: %v", err)})
+		return
+	}
+
+	secret := make([]byte, feedTokenBytes)
+	if _, err := rand.Read(secret); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+	feed, err := h.grpcClient.CreateFeedToken(requestContext(c), &proto.CreateFeedTokenRequest{
+		Token: &proto.FeedToken{
+			Owner:     owner,
+			Name:      req.Name,
+			TokenHash: hashFeedToken(token),
+			Filter:    filter.Encode(),
+		},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	view := newFeedView(feed)
+	view.Token = token
+	view.URL = feedURL(c, token)
+	c.JSON(http.StatusOK, view)
+}
+
+// listFeeds lists the feeds of the caller without their tokens.
+func (h *TaskHandler) listFeeds(c *gin.Context) {
+	owner := c.GetHeader(auth.UserHeader)
+	if owner == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": auth.UserHeader + " header is required"})
+		return
+	}
+	res, err := h.grpcClient.ListFeedTokens(context.Background(), &proto.ListFeedTokensRequest{Owner: owner})
+	if err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": s})
+		return
+	}
+	feeds := make([]*feedView, 0, len(res.Tokens))
+	for _, t := range res.Tokens {
+		feeds = append(feeds, newFeedView(t))
+	}
+	var resp struct {
+		Results interface{} `json:"results"`
+	}
+
+	resp.Results = feeds
+	c.JSON(http.StatusOK, resp)
+}
+
+// deleteFeed revokes a feed token. Only its owner or an admin may do so.
+func (h *TaskHandler) deleteFeed(c *gin.Context) {
+	user := c.GetHeader(auth.UserHeader)
+	feed, err := h.grpcClient.GetFeedToken(context.Background(), &proto.GetFeedTokenRequest{Id: c.Param("id")})
+	if err != nil || user == "" || (feed.Owner != user && !h.admins[user]) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feed not found"})
+		return
+	}
+	resp, err := h.grpcClient.DeleteFeedToken(requestContext(c), &proto.DeleteFeedTokenRequest{Id: feed.Id})
+	if err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": s})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// taskFeed serves the tasks of the feed named by the token query parameter
+// as an iCalendar of VTODOs. Calendar apps cannot send headers, so the
+// token is the only credential.
+func (h *TaskHandler) taskFeed(c *gin.Context) {
+	token := c.Query("token")
+	var feed *proto.FeedToken
+	var err error
+	if token != "" {
+		feed, err = h.grpcClient.GetFeedToken(context.Background(), &proto.GetFeedTokenRequest{TokenHash: hashFeedToken(token)})
+	}
+	if token == "" || err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feed not found"})
+		return
+	}
+	req := &proto.ListTasksRequest{Assignee: feed.Owner}
+	if feed.Filter != "" {
+		filter, _ := url.ParseQuery(feed.Filter)
+		if req, err = h.parseListTasksRequest(filter); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("invalid feed filter: %v", err)})
+			return
+		}
+	}
+
+	name := feed.Name
+	if name == "" {
+		name = "Tasks"
+	}
+	c.Header("Content-Type", ical.ContentType)
+	c.Header("Content-Disposition", `inline; filename="tasks.ics"`)
+	c.Header("Cache-Control", "private, no-cache")
+	c.Status(http.StatusOK)
+	w := ical.NewWriter(c.Writer, feedProdID, name)
+
+	ctx := context.Background()
+	req.PageSize = exportPageSize
+	written := 0
+	for req.Page = 0; written < maxFeedTasks; req.Page++ {
+		res, err := h.grpcClient.ListTasks(ctx, req)
+		if err != nil {
+			log.Printf("Feed %s stopped after %d pages: %v", feed.Id, req.Page, err)
+			return
+		}
+		for _, task := range res.Tasks[:min(len(res.Tasks), maxFeedTasks-written)] {
+			if err := w.WriteTodo(h.newTodo(task)); err != nil {
+				log.Printf("Feed %s stopped: %v", feed.Id, err)
+				return
+			}
+			written++
+		}
+		if len(res.Tasks) < exportPageSize {
+			break
+		}
+	}
+	if err := w.Close(); err != nil {
+		log.Printf("Feed %s stopped: %v", feed.Id, err)
+	}
+}
+
+func (h *TaskHandler) newTodo(task *proto.Task) *ical.Todo {
+	todo := &ical.Todo{
+		UID:         h.todoUID(task.Id),
+		Summary:     task.Title,
+		Description: task.Description,
+		Status:      h.todoStatus(task.Status),
+		Priority:    todoPriority(task.Priority),
+		Categories:  task.Labels,
+	}
+	if task.ParentId != "" {
+		todo.RelatedTo = h.todoUID(task.ParentId)
+	}
+	if task.DueAt != nil {
+		todo.Due = task.DueAt.AsTime()
+	}
+	if task.CreatedAt != nil {
+		todo.Created = task.CreatedAt.AsTime()
+	}
+	if task.UpdatedAt != nil {
+		todo.LastModified = task.UpdatedAt.AsTime()
+	}
+	return todo
+}
+
+func (h *TaskHandler) todoUID(id string) string {
+	return id + "@" + h.serviceName
+}
+
+// todoStatus maps a workflow status onto the VTODO statuses.
+func (h *TaskHandler) todoStatus(s string) string {
+	switch {
+	case h.workflow.IsFinal(s):
+		return ical.StatusCompleted
+	case s == "" || s == h.workflow.Initial():
+		return ical.StatusNeedsAction
+	}
+	return ical.StatusInProcess
+}
+
+// todoPriority maps a priority onto the 1 (highest) to 9 (lowest) scale.
+func todoPriority(p proto.Priority) int {
+	switch p {
+	case proto.Priority_PRIORITY_URGENT:
+		return 1
+	case proto.Priority_PRIORITY_HIGH:
+		return 3
+	case proto.Priority_PRIORITY_MEDIUM:
+		return 5
+	case proto.Priority_PRIORITY_LOW:
+		return 9
+	}
+	return 0
+}
+
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// feedURL is the address of the feed of token, next to the /feeds route
+// that served c.
+func feedURL(c *gin.Context, token string) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	path := strings.TrimSuffix(c.FullPath(), "/feeds") + "/tasks.ics"
+	return (&url.URL{Scheme: scheme, Host: c.Request.Host, Path: path, RawQuery: "token=" + token}).String()
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/auth"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/github"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// maxGitHubPayload is the largest payload GitHub sends.
+const maxGitHubPayload = 25 << 20
+
+// Results of a GitHub event.
+const (
+	githubCreated = "created"
+	githubUpdated = "updated"
+	githubIgnored = "ignored"
+)
+
+var errNoCompletedStatus = errors.New("the workflow has no final status for closed items")
+
+// githubStatusError is a status change of a GitHub event that the workflow
+// rejects.
+type githubStatusError struct {
+	from string
+	err  error
+}
+
+func (e *githubStatusError) Error() string { return e.err.Error() }
+
+func (e *githubStatusError) Unwrap() error { return e.err }
+
+// receiveGitHub keeps a task in sync with every issue and pull request that
+// a GitHub webhook reports. The task is linked to its item by external id.
+// Items closed on GitHub move their task to the first configured final
+// status and reopened items move it back to the initial status, when the
+// workflow allows it; other moves are rejected like those of the REST API.
+func (h *TaskHandler) receiveGitHub(c *gin.Context) {
+	if h.github == nil || h.github.Secret == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "the GitHub integration is not configured"})
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxGitHubPayload))
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("payloads must not exceed %d bytes", maxGitHubPayload)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := github.Verify(h.github.Secret, body, c.GetHeader(github.SignatureHeader)); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch c.GetHeader(github.EventHeader) {
+	case github.EventPing:
+		c.JSON(http.StatusOK, gin.H{"result": "pong"})
+		return
+	case github.EventIssues, github.EventPullRequest:
+	default:
+		c.JSON(http.StatusAccepted, gin.H{"result": githubIgnored})
+		return
+	}
+	var event github.Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := event.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, id, err := h.applyGitHubEvent(&event)
+	var statusErr *githubStatusError
+	if errors.As(err, &statusErr) {
+		h.transitionError(c, statusErr.from, statusErr.err)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if result == githubIgnored {
+		c.JSON(http.StatusAccepted, gin.H{"result": result})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": result, "task_id": id, "external_id": event.ExternalID()})
+}
+
+// applyGitHubEvent creates or updates the task linked to the event's item.
+// Items first seen in an edited or closed event get a task too.
+func (h *TaskHandler) applyGitHubEvent(event *github.Event) (string, string, error) {
+	var status string
+	switch event.Action {
+	case github.ActionOpened, github.ActionEdited:
+	case github.ActionClosed:
+		completed, ok := h.workflow.CompletedStatus()
+		if !ok {
+			return "", "", &githubStatusError{err: errNoCompletedStatus}
+		}
+		status = completed
+	case github.ActionReopened:
+		status = h.workflow.Initial()
+	default:
+		return githubIgnored, "", nil
+	}
+
+	// Deliveries for the same item may arrive together; the lock keeps them
+	// from creating the task twice.
+	h.githubMu.Lock()
+	defer h.githubMu.Unlock()
+
+	ctx := auth.WithUser(context.Background(), github.Actor)
+	item := event.Item()
+	externalID := event.ExternalID()
+	res, err := h.grpcClient.ListTasks(ctx, &proto.ListTasksRequest{ExternalIds: []string{externalID}, PageSize: 1})
+	if err != nil {
+		return "", "", err
+	}
+
+	if len(res.Tasks) == 0 {
+		req, err := h.createRequest(item.Reporter(), &createTaskRequest{
+			taskInput: taskInput{
+				Title:       item.Title,
+				Description: item.Description(),
+				Status:      status,
+			},
+			ExternalID: externalID,
+		})
+		if err != nil {
+			return "", "", err
+		}
+		created, err := h.grpcClient.CreateTask(ctx, req)
+		if err != nil {
+			return "", "", err
+		}
+		return githubCreated, created.Id, nil
+	}
+
+	task := res.Tasks[0]
+	req := &proto.UpdateTaskRequest{
+		Id:          task.Id,
+		Title:       item.Title,
+		Description: item.Description(),
+		UpdateMask:  &fieldmaskpb.FieldMask{Paths: []string{"title", "description"}},
+	}
+	if status != "" {
+		if _, err := checkStatusChange(ctx, h.grpcClient, h.workflow, task.Id, status); err != nil {
+			return "", "", &githubStatusError{from: task.Status, err: err}
+		}
+		req.Status = status
+		req.UpdateMask.Paths = append(req.UpdateMask.Paths, "status")
+	}
+	if _, err := h.grpcClient.UpdateTask(ctx, req); err != nil {
+		return "", "", err
+	}
+	return githubUpdated, task.Id, nil
+}
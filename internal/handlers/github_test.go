@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/github"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/models"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/workflow"
+	"github.com/gin-gonic/gin"
+)
+
+const testGitHubSecret = "It's a Secret to Everybody"
+
+// newGitHubReceiver serves the GitHub receiver of a handler backed by
+// tasks, with the workflow conf (the default one when nil).
+func newGitHubReceiver(t *testing.T, tasks *fakeTaskService, conf *models.Workflow) http.Handler {
+	t.Helper()
+	stateMachine, err := workflow.New(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewTaskHandler(tasks, stateMachine, nil, nil, nil, nil, nil, &models.GitHub{Secret: testGitHubSecret}, nil)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/integrations/github", h.receiveGitHub)
+	return router
+}
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	payload, err := os.ReadFile(filepath.Join("..", "github", "testdata", name+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return payload
+}
+
+func sign(payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(testGitHubSecret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// deliver posts payload as a GitHub event and returns the response status
+// and body.
+func deliver(t *testing.T, receiver http.Handler, event string, payload []byte, signature string) (int, map[string]interface{}) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/integrations/github", bytes.NewReader(payload))
+	req.Header.Set(github.EventHeader, event)
+	req.Header.Set(github.SignatureHeader, signature)
+	w := httptest.NewRecorder()
+	receiver.ServeHTTP(w, req)
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("%s: decoding %q: %v", event, w.Body.String(), err)
+	}
+	return w.Code, body
+}
+
+func TestGitHubFixtures(t *testing.T) {
+	tasks := newFakeTaskService()
+	receiver := newGitHubReceiver(t, tasks, nil)
+
+	// The fixtures follow issue 42 and pull request 43 through their life
+	// on GitHub, so they are replayed in order.
+	for _, tc := range []struct {
+		fixture    string
+		event      string
+		code       int
+		result     string
+		externalID string
+		title      string
+		status     string
+	}{
+		{fixture: "ping", event: github.EventPing, code: http.StatusOK, result: "pong"},
+		{"issues-opened", github.EventIssues, http.StatusOK, githubCreated, "github:octo-org/nashville#42", "Export ignores the tz parameter", "todo"},
+		{"issues-edited", github.EventIssues, http.StatusOK, githubUpdated, "github:octo-org/nashville#42", "CSV export ignores the tz parameter", "todo"},
+		{"issues-closed", github.EventIssues, http.StatusOK, githubUpdated, "github:octo-org/nashville#42", "CSV export ignores the tz parameter", "done"},
+		{"issues-reopened", github.EventIssues, http.StatusOK, githubUpdated, "github:octo-org/nashville#42", "CSV export ignores the tz parameter", "todo"},
+		{"pull_request-opened", github.EventPullRequest, http.StatusOK, githubCreated, "github:octo-org/nashville#43", "Honour tz in CSV exports", "todo"},
+		{"pull_request-closed", github.EventPullRequest, http.StatusOK, githubUpdated, "github:octo-org/nashville#43", "Honour tz in CSV exports", "done"},
+		// Events other than issues and pull requests are acknowledged and
+		// ignored.
+		{fixture: "issues-opened", event: "push", code: http.StatusAccepted, result: githubIgnored},
+	} {
+		payload := readFixture(t, tc.fixture)
+		code, body := deliver(t, receiver, tc.event, payload, sign(payload))
+		if code != tc.code || body["result"] != tc.result {
+			t.Fatalf("%s as %s: got %d %v, want %d with result %q", tc.fixture, tc.event, code, body, tc.code, tc.result)
+		}
+		if tc.externalID == "" {
+			continue
+		}
+		if body["external_id"] != tc.externalID {
+			t.Errorf("%s: external_id = %v, want %s", tc.fixture, body["external_id"], tc.externalID)
+		}
+		id, _ := body["task_id"].(string)
+		task, err := tasks.GetTask(context.Background(), &proto.GetTaskRequest{Id: id})
+		if err != nil {
+			t.Fatalf("%s: %v", tc.fixture, err)
+		}
+		if task.ExternalId != tc.externalID || task.Title != tc.title || task.Status != tc.status {
+			t.Errorf("%s: task is %q %q in %q, want %q %q in %q", tc.fixture,
+				task.ExternalId, task.Title, task.Status, tc.externalID, tc.title, tc.status)
+		}
+	}
+	if len(tasks.tasks) != 2 {
+		t.Errorf("the fixtures created %d tasks, want 2", len(tasks.tasks))
+	}
+}
+
+func TestGitHubBadSignature(t *testing.T) {
+	tasks := newFakeTaskService()
+	receiver := newGitHubReceiver(t, tasks, nil)
+	payload := readFixture(t, "issues-opened")
+	for _, signature := range []string{"", "sha256=00", sign([]byte("another payload"))} {
+		if code, body := deliver(t, receiver, github.EventIssues, payload, signature); code != http.StatusUnauthorized {
+			t.Errorf("signature %q: got %d %v, want 401", signature, code, body)
+		}
+	}
+	if len(tasks.tasks) != 0 {
+		t.Errorf("unsigned deliveries created %d tasks", len(tasks.tasks))
+	}
+}
+
+func TestGitHubCloseFollowsWorkflow(t *testing.T) {
+	tasks := newFakeTaskService()
+	receiver := newGitHubReceiver(t, tasks, nil)
+	opened := readFixture(t, "issues-opened")
+	_, body := deliver(t, receiver, github.EventIssues, opened, sign(opened))
+	id, _ := body["task_id"].(string)
+	tasks.tasks[id].Status = "blocked"
+
+	closed := readFixture(t, "issues-closed")
+	if code, body := deliver(t, receiver, github.EventIssues, closed, sign(closed)); code != http.StatusConflict {
+		t.Errorf("closing a blocked task: got %d %v, want 409", code, body)
+	}
+	if got := tasks.tasks[id].Status; got != "blocked" {
+		t.Errorf("status = %q after a rejected close, want blocked", got)
+	}
+}
+
+func TestGitHubCloseWithoutFinalStatus(t *testing.T) {
+	tasks := newFakeTaskService()
+	receiver := newGitHubReceiver(t, tasks, &models.Workflow{
+		Initial:     "open",
+		Transitions: map[string][]string{"open": {"closed"}, "closed": {"open"}},
+	})
+	closed := readFixture(t, "issues-closed")
+	if code, body := deliver(t, receiver, github.EventIssues, closed, sign(closed)); code != http.StatusBadRequest {
+		t.Errorf("got %d %v, want 400", code, body)
+	}
+	if len(tasks.tasks) != 0 {
+		t.Errorf("a rejected close created %d tasks", len(tasks.tasks))
+	}
+}
@@ -0,0 +1,456 @@
+package handlers
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/auth"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/events"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/taskinput"
+	"github.com/gin-gonic/gin"
+	"github.com/graph-gophers/graphql-go"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	graphqlMaxDepth = 10
+	// graphqlEventBuffer is the number of events a GraphQL subscription
+	// holds before it starts dropping them.
+	graphqlEventBuffer = 64
+)
+
+//go:embed schema.graphql
+var graphqlSchema string
+
+type graphqlRequest struct {
+	Query         string                 `json:"query" form:"query" binding:"required"`
+	OperationName string                 `json:"operationName" form:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+func newGraphQLSchema(h *TaskHandler, bus *events.Bus) *graphql.Schema {
+	return graphql.MustParseSchema(graphqlSchema, &graphqlResolver{h: h, bus: bus},
+		graphql.MaxDepth(graphqlMaxDepth))
+}
+
+// serveGraphQL executes a query or mutation sent as JSON, or a query sent
+// with GET. Subscriptions are served over the task WebSocket. Backend reads
+// are batched per request by a taskLoader.
+func (h *TaskHandler) serveGraphQL(c *gin.Context) {
+	var req graphqlRequest
+	var err error
+	if c.Request.Method == http.MethodGet {
+		err = c.ShouldBindQuery(&req)
+	} else {
+		err = c.ShouldBindJSON(&req)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := requestContext(c)
+	ctx = withTaskLoader(ctx, newTaskLoader(ctx, h.grpcClient))
+	c.JSON(http.StatusOK, h.graphql.Exec(ctx, req.Query, req.OperationName, req.Variables))
+}
+
+type graphqlResolver struct {
+	h   *TaskHandler
+	bus *events.Bus
+}
+
+type taskFilterInput struct {
+	Status    *[]string
+	Assignee  *string
+	Reporter  *string
+	Labels    *[]string
+	LabelMode *string
+	DueAfter  *string
+	DueBefore *string
+	StartTime *string
+	EndTime   *string
+	Sort      *string
+	ParentID  *graphql.ID
+	Tz        *string
+}
+
+// values converts the filter into /tasks query parameters.
+func (f *taskFilterInput) values() url.Values {
+	vars := url.Values{}
+	set := func(name string, v *string) {
+		if v != nil {
+			vars.Set(name, *v)
+		}
+	}
+	list := func(name string, v *[]string) {
+		if v != nil {
+			vars.Set(name, strings.Join(*v, ","))
+		}
+	}
+	if f == nil {
+		return vars
+	}
+	list("status", f.Status)
+	set("assignee", f.Assignee)
+	set("reporter", f.Reporter)
+	list("labels", f.Labels)
+	set("labelMode", f.LabelMode)
+	set("dueAfter", f.DueAfter)
+	set("dueBefore", f.DueBefore)
+	set("startTime", f.StartTime)
+	set("endTime", f.EndTime)
+	set("sort", f.Sort)
+	set("tz", f.Tz)
+	if f.ParentID != nil {
+		vars.Set("parentId", string(*f.ParentID))
+	}
+	return vars
+}
+
+func (r *graphqlResolver) Task(ctx context.Context, args struct{ ID graphql.ID }) (*taskResolver, error) {
+	l := taskLoaderFrom(ctx)
+	task, err := l.load(ctx, string(args.ID))
+	if err != nil || task == nil {
+		return nil, err
+	}
+	return r.newTaskResolver(l, task), nil
+}
+
+func (r *graphqlResolver) Tasks(ctx context.Context, args struct {
+	Filter   *taskFilterInput
+	Page     *int32
+	PageSize *int32
+}) ([]*taskResolver, error) {
+	vars := args.Filter.values()
+	if args.Page != nil {
+		vars.Set("page", fmt.Sprint(*args.Page))
+	}
+	if args.PageSize != nil {
+		vars.Set("pageSize", fmt.Sprint(*args.PageSize))
+	}
+	req, err := r.h.parseListTasksRequest(vars)
+	if err != nil {
+		return nil, err
+	}
+	res, err := r.h.grpcClient.ListTasks(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	l := taskLoaderFrom(ctx)
+	l.prime(res.Tasks...)
+	return r.newTaskResolvers(l, res.Tasks), nil
+}
+
+type createTaskInput struct {
+	Title          string
+	Description    *string
+	Status         *string
+	Priority       *string
+	DueAt          *string
+	Timezone       *string
+	Assignee       *string
+	Reporter       *string
+	ParentID       *graphql.ID
+	Rrule          *string
+	RecurrenceMode *string
+	ExternalID     *string
+}
+
+type updateTaskInput struct {
+	Title          *string
+	Description    *string
+	Status         *string
+	Priority       *string
+	DueAt          *string
+	Timezone       *string
+	Assignee       *string
+	Reporter       *string
+	Rrule          *string
+	RecurrenceMode *string
+}
+
+func (r *graphqlResolver) CreateTask(ctx context.Context, args struct{ Input createTaskInput }) (*taskResolver, error) {
+	in := args.Input
+	req, err := r.h.createRequest(auth.UserFromContext(ctx), &createTaskRequest{
+		taskInput: taskInput{
+			Title:          in.Title,
+			Description:    deref(in.Description),
+			Status:         deref(in.Status),
+			DueAt:          deref(in.DueAt),
+			Timezone:       deref(in.Timezone),
+			Priority:       deref(in.Priority),
+			Assignee:       deref(in.Assignee),
+			Reporter:       deref(in.Reporter),
+			RRule:          deref(in.Rrule),
+			RecurrenceMode: deref(in.RecurrenceMode),
+		},
+		ParentID:   string(deref(in.ParentID)),
+		ExternalID: deref(in.ExternalID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	created, err := r.h.grpcClient.CreateTask(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return r.reload(ctx, created.Id)
+}
+
+// UpdateTask writes the fields set in the input. Unlike PUT /task/:id it
+// keeps the title and description when they are not given.
+func (r *graphqlResolver) UpdateTask(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input updateTaskInput
+}) (*taskResolver, error) {
+	id, in := string(args.ID), args.Input
+	task, err := r.h.grpcClient.GetTask(ctx, &proto.GetTaskRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]bool{}
+	value := func(name string, v *string, current string) string {
+		if v == nil {
+			return current
+		}
+		fields[name] = true
+		return *v
+	}
+	input := taskInput{
+		Title:          value("title", in.Title, task.Title),
+		Description:    value("description", in.Description, task.Description),
+		Status:         value("status", in.Status, task.Status),
+		DueAt:          value("due_at", in.DueAt, ""),
+		Timezone:       value("timezone", in.Timezone, ""),
+		Priority:       value("priority", in.Priority, ""),
+		Assignee:       value("assignee", in.Assignee, ""),
+		Reporter:       value("reporter", in.Reporter, ""),
+		RRule:          value("rrule", in.Rrule, ""),
+		RecurrenceMode: value("recurrence_mode", in.RecurrenceMode, ""),
+	}
+	present := make(map[string]json.RawMessage, len(fields))
+	for name := range fields {
+		present[name] = nil
+	}
+	req, err := r.h.updateRequest(id, &input, present)
+	if err != nil {
+		return nil, err
+	}
+	if hasPath(req.UpdateMask, "status") && req.Status != task.Status {
+		if err := r.h.workflow.Transition(task.Status, req.Status); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := r.h.grpcClient.UpdateTask(ctx, req); err != nil {
+		return nil, err
+	}
+	return r.reload(ctx, id)
+}
+
+func (r *graphqlResolver) DeleteTask(ctx context.Context, args struct{ ID graphql.ID }) (bool, error) {
+	res, err := r.h.grpcClient.DeleteTask(ctx, &proto.DeleteTaskRequest{Id: string(args.ID)})
+	if err != nil {
+		return false, err
+	}
+	return res.Success, nil
+}
+
+// reload reads a task after a mutation, bypassing the cache of the loader.
+func (r *graphqlResolver) reload(ctx context.Context, id string) (*taskResolver, error) {
+	task, err := r.h.grpcClient.GetTask(ctx, &proto.GetTaskRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	return r.newTaskResolver(taskLoaderFrom(ctx), task), nil
+}
+
+// TaskEvents streams task events until the subscription ends. Each event
+// is resolved with a loader of its own so that nested fields are current.
+func (r *graphqlResolver) TaskEvents(ctx context.Context, args struct {
+	TaskID *graphql.ID
+	Types  *[]string
+}) (<-chan *taskEventResolver, error) {
+	types := map[events.Type]bool{}
+	for _, t := range deref(args.Types) {
+		if !isEventType(t) {
+			return nil, fmt.Errorf("unknown event type %q", t)
+		}
+		types[events.Type(t)] = true
+	}
+	topic := events.TopicAll
+	if args.TaskID != nil {
+		topic = events.TaskTopic(string(*args.TaskID))
+	}
+
+	evs, unsubscribe := r.bus.Subscribe(graphqlEventBuffer)
+	out := make(chan *taskEventResolver)
+	go func() {
+		defer close(out)
+		defer unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-evs:
+				if !ok {
+					return
+				}
+				if !e.HasTopic(topic) || (len(types) > 0 && !types[e.Type]) {
+					continue
+				}
+				l := newTaskLoader(ctx, r.h.grpcClient)
+				select {
+				case out <- &taskEventResolver{r: r, l: l, e: e}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+func isEventType(t string) bool {
+	for _, known := range events.Types {
+		if string(known) == t {
+			return true
+		}
+	}
+	return false
+}
+
+type taskEventResolver struct {
+	r *graphqlResolver
+	l *taskLoader
+	e events.Event
+}
+
+func (e *taskEventResolver) Type() string       { return string(e.e.Type) }
+func (e *taskEventResolver) TaskID() graphql.ID { return graphql.ID(e.e.TaskID) }
+func (e *taskEventResolver) Actor() *string     { return optional(e.e.Actor) }
+func (e *taskEventResolver) Time() string       { return e.e.Time.UTC().Format(time.RFC3339) }
+
+func (e *taskEventResolver) Task() *taskResolver {
+	if e.e.Task == nil {
+		return nil
+	}
+	return e.r.newTaskResolver(e.l, e.e.Task)
+}
+
+func (e *taskEventResolver) Previous() *taskResolver {
+	if e.e.Previous == nil {
+		return nil
+	}
+	return e.r.newTaskResolver(e.l, e.e.Previous)
+}
+
+// taskResolver resolves the fields of a task, reading related tasks
+// through its loader.
+type taskResolver struct {
+	r    *graphqlResolver
+	l    *taskLoader
+	task *proto.Task
+}
+
+func (r *graphqlResolver) newTaskResolver(l *taskLoader, task *proto.Task) *taskResolver {
+	return &taskResolver{r: r, l: l, task: task}
+}
+
+func (r *graphqlResolver) newTaskResolvers(l *taskLoader, tasks []*proto.Task) []*taskResolver {
+	resolvers := make([]*taskResolver, 0, len(tasks))
+	for _, task := range tasks {
+		resolvers = append(resolvers, r.newTaskResolver(l, task))
+	}
+	return resolvers
+}
+
+func (t *taskResolver) ID() graphql.ID       { return graphql.ID(t.task.Id) }
+func (t *taskResolver) Title() string        { return t.task.Title }
+func (t *taskResolver) Description() *string { return optional(t.task.Description) }
+func (t *taskResolver) Status() string       { return t.task.Status }
+func (t *taskResolver) Priority() *string    { return optional(taskinput.PriorityName(t.task.Priority)) }
+func (t *taskResolver) DueAt() *string       { return optionalTime(t.task.DueAt) }
+func (t *taskResolver) Assignee() *string    { return optional(t.task.Assignee) }
+func (t *taskResolver) Reporter() *string    { return optional(t.task.Reporter) }
+func (t *taskResolver) Labels() []string     { return nonNil(t.task.Labels) }
+func (t *taskResolver) ExternalID() *string  { return optional(t.task.ExternalId) }
+func (t *taskResolver) Rrule() *string       { return optional(t.task.Rrule) }
+func (t *taskResolver) CreatedAt() *string   { return optionalTime(t.task.CreatedAt) }
+func (t *taskResolver) UpdatedAt() *string   { return optionalTime(t.task.UpdatedAt) }
+
+// Blocked tells whether a blocker is not in a final status. Blockers that
+// no longer exist do not block.
+func (t *taskResolver) Blocked(ctx context.Context) (bool, error) {
+	for _, id := range t.task.BlockedBy {
+		blocker, err := t.l.load(ctx, id)
+		if err != nil {
+			return false, err
+		}
+		if blocker != nil && !t.r.h.workflow.IsFinal(blocker.Status) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (t *taskResolver) BlockedBy(ctx context.Context) ([]*taskResolver, error) {
+	var blockers []*proto.Task
+	for _, id := range t.task.BlockedBy {
+		blocker, err := t.l.load(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if blocker != nil {
+			blockers = append(blockers, blocker)
+		}
+	}
+	return t.r.newTaskResolvers(t.l, blockers), nil
+}
+
+func (t *taskResolver) Parent(ctx context.Context) (*taskResolver, error) {
+	if t.task.ParentId == "" {
+		return nil, nil
+	}
+	parent, err := t.l.load(ctx, t.task.ParentId)
+	if err != nil || parent == nil {
+		return nil, err
+	}
+	return t.r.newTaskResolver(t.l, parent), nil
+}
+
+func (t *taskResolver) Children() ([]*taskResolver, error) {
+	children, err := t.l.loadChildren(t.task.Id)
+	if err != nil {
+		return nil, err
+	}
+	return t.r.newTaskResolvers(t.l, children), nil
+}
+
+// optional maps empty strings to null.
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// optionalTime formats ts in UTC, mapping unset timestamps to null.
+func optionalTime(ts *timestamppb.Timestamp) *string {
+	return optional(formatTimestamp(ts, time.UTC))
+}
+
+func deref[T any](v *T) T {
+	if v == nil {
+		var zero T
+		return zero
+	}
+	return *v
+}
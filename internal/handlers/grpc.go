@@ -0,0 +1,566 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/auth"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/workflow"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	gproto "google.golang.org/protobuf/proto"
+)
+
+// taskServer serves TaskService to the gRPC clients of the gateway. It
+// checks requests the way the REST handlers do and forwards them through
+// the same client, so that their changes are published alike. The RPCs
+// the REST API only makes on behalf of its users, such as recording
+// activity, are reserved for admins.
+type taskServer struct {
+	proto.UnimplementedTaskServiceServer
+	h *TaskHandler
+}
+
+// TaskServer returns the gRPC counterpart of the REST API. The caller is the
+// user of the request context, see auth.WithUser.
+func (h *TaskHandler) TaskServer() proto.TaskServiceServer {
+	return &taskServer{h: h}
+}
+
+func invalidArgument(err error) error {
+	return status.Error(codes.InvalidArgument, err.Error())
+}
+
+// requireUser returns the caller, failing when the request is anonymous.
+func requireUser(ctx context.Context) (string, error) {
+	user := auth.UserFromContext(ctx)
+	if user == "" {
+		return "", status.Error(codes.Unauthenticated, auth.UserMetadata+" metadata is required")
+	}
+	return user, nil
+}
+
+func (s *taskServer) requireAdmin(ctx context.Context) error {
+	user, err := requireUser(ctx)
+	if err != nil {
+		return err
+	}
+	if !s.h.admins[user] {
+		return status.Error(codes.PermissionDenied, "only admins may call this method")
+	}
+	return nil
+}
+
+// transitionError reports a rejected status change like its REST
+// counterpart.
+func transitionError(err error) error {
+	if errors.Is(err, workflow.ErrIllegalTransition) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return invalidArgument(err)
+}
+
+func (s *taskServer) CreateTask(ctx context.Context, in *proto.CreateTaskRequest) (*proto.CreateTaskResponse, error) {
+	req := gproto.Clone(in).(*proto.CreateTaskRequest)
+	if req.Reporter == "" {
+		req.Reporter = auth.UserFromContext(ctx)
+	}
+	if req.Status == "" {
+		req.Status = s.h.workflow.Initial()
+	} else if !s.h.workflow.Valid(req.Status) {
+		return nil, invalidArgument(fmt.Errorf("%w %q", workflow.ErrUnknownStatus, req.Status))
+	}
+	if _, ok := proto.Priority_name[int32(req.Priority)]; !ok {
+		return nil, invalidArgument(fmt.Errorf("unknown priority %d", req.Priority))
+	}
+	series, err := parseRecurrence(&taskInput{
+		Timezone:       req.Timezone,
+		RRule:          req.Rrule,
+		RecurrenceMode: req.RecurrenceMode,
+	}, req.DueAt)
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+	req.DueAt, req.Rrule, req.Timezone = series.dueAt, series.rrule, series.timezone
+	req.SeriesStart, req.RecurrenceMode = series.start, series.mode
+	return s.h.grpcClient.CreateTask(ctx, req)
+}
+
+func (s *taskServer) GetTask(ctx context.Context, in *proto.GetTaskRequest) (*proto.Task, error) {
+	return s.h.grpcClient.GetTask(ctx, in)
+}
+
+// UpdateTask writes the fields named in the update mask, which is required.
+// The mask may name the fields a REST update writes and parent_id, which is
+// checked like a move.
+func (s *taskServer) UpdateTask(ctx context.Context, in *proto.UpdateTaskRequest) (*proto.UpdateTaskResponse, error) {
+	req := gproto.Clone(in).(*proto.UpdateTaskRequest)
+	if len(req.UpdateMask.GetPaths()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "update_mask is required")
+	}
+	for _, path := range req.UpdateMask.Paths {
+		switch {
+		case path == "title", path == "description", path == "parent_id":
+		case slices.Contains(maskableFields, path):
+		default:
+			return nil, invalidArgument(fmt.Errorf("update_mask cannot name %q", path))
+		}
+	}
+	if _, ok := proto.Priority_name[int32(req.Priority)]; !ok {
+		return nil, invalidArgument(fmt.Errorf("unknown priority %d", req.Priority))
+	}
+	err := applyRecurrence(req, &taskInput{
+		Timezone:       req.Timezone,
+		RRule:          req.Rrule,
+		RecurrenceMode: req.RecurrenceMode,
+	})
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+	if hasPath(req.UpdateMask, "parent_id") {
+		if err := checkParent(ctx, s.h.grpcClient, req.Id, req.ParentId); err != nil {
+			if errors.Is(err, errUnknownParent) {
+				return nil, status.Error(codes.NotFound, err.Error())
+			}
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+	}
+	if hasPath(req.UpdateMask, "status") {
+		task, err := checkStatusChange(ctx, s.h.grpcClient, s.h.workflow, req.Id, req.Status)
+		if task == nil {
+			return nil, err
+		}
+		if err != nil {
+			return nil, transitionError(err)
+		}
+	}
+	return s.h.grpcClient.UpdateTask(ctx, req)
+}
+
+func (s *taskServer) DeleteTask(ctx context.Context, in *proto.DeleteTaskRequest) (*proto.DeleteTaskResponse, error) {
+	return s.h.grpcClient.DeleteTask(ctx, in)
+}
+
+// ListTasks checks the filters like /tasks and caps the page size alike.
+func (s *taskServer) ListTasks(ctx context.Context, in *proto.ListTasksRequest) (*proto.ListTasksResponse, error) {
+	req := gproto.Clone(in).(*proto.ListTasksRequest)
+	for _, st := range req.Statuses {
+		if !s.h.workflow.Valid(st) {
+			return nil, invalidArgument(fmt.Errorf("%w %q", workflow.ErrUnknownStatus, st))
+		}
+	}
+	switch req.LabelMode {
+	case "", labelModeAny, labelModeAll:
+	default:
+		return nil, invalidArgument(fmt.Errorf("labelMode must be %s or %s", labelModeAny, labelModeAll))
+	}
+	switch req.SortBy {
+	case "", sortByPriority, sortByDue:
+	default:
+		return nil, invalidArgument(fmt.Errorf("unknown sort %q", req.SortBy))
+	}
+	if req.PageSize <= 0 || req.PageSize > exportPageSize {
+		req.PageSize = exportPageSize
+	}
+	return s.h.grpcClient.ListTasks(ctx, req)
+}
+
+func (s *taskServer) SearchTasks(ctx context.Context, in *proto.SearchTasksRequest) (*proto.SearchTasksResponse, error) {
+	return s.h.grpcClient.SearchTasks(ctx, in)
+}
+
+func (s *taskServer) CreateLabel(ctx context.Context, in *proto.CreateLabelRequest) (*proto.Label, error) {
+	label := &labelRequest{Name: in.Name, Color: in.Color, Description: in.Description}
+	if err := validateLabel(label); err != nil {
+		return nil, invalidArgument(err)
+	}
+	return s.h.grpcClient.CreateLabel(ctx, &proto.CreateLabelRequest{
+		Name:        label.Name,
+		Color:       label.Color,
+		Description: label.Description,
+	})
+}
+
+func (s *taskServer) GetLabel(ctx context.Context, in *proto.GetLabelRequest) (*proto.Label, error) {
+	return s.h.grpcClient.GetLabel(ctx, in)
+}
+
+// UpdateLabel relabels the tasks carrying the label when it is renamed.
+func (s *taskServer) UpdateLabel(ctx context.Context, in *proto.UpdateLabelRequest) (*proto.Label, error) {
+	label := &labelRequest{Name: in.NewName, Color: in.Color, Description: in.Description}
+	if label.Name == "" {
+		label.Name = in.Name
+	}
+	if err := validateLabel(label); err != nil {
+		return nil, invalidArgument(err)
+	}
+	req := &proto.UpdateLabelRequest{Name: in.Name, Color: label.Color, Description: label.Description}
+	if label.Name != in.Name {
+		req.NewName = label.Name
+	}
+	res, err := s.h.grpcClient.UpdateLabel(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if req.NewName != "" {
+		if _, err := s.h.relabelTasks(ctx, req.Name, req.NewName); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// DeleteLabel detaches the label from every task.
+func (s *taskServer) DeleteLabel(ctx context.Context, in *proto.DeleteLabelRequest) (*proto.DeleteLabelResponse, error) {
+	res, err := s.h.grpcClient.DeleteLabel(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.h.relabelTasks(ctx, in.Name, ""); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *taskServer) ListLabels(ctx context.Context, in *proto.ListLabelsRequest) (*proto.ListLabelsResponse, error) {
+	return s.h.grpcClient.ListLabels(ctx, in)
+}
+
+// AttachLabels only attaches existing labels.
+func (s *taskServer) AttachLabels(ctx context.Context, in *proto.TaskLabelsRequest) (*proto.TaskLabelsResponse, error) {
+	for _, name := range in.Labels {
+		_, err := s.h.grpcClient.GetLabel(ctx, &proto.GetLabelRequest{Name: name})
+		if status.Code(err) == codes.NotFound {
+			return nil, status.Errorf(codes.NotFound, "label %q does not exist", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.h.grpcClient.AttachLabels(ctx, in)
+}
+
+func (s *taskServer) DetachLabels(ctx context.Context, in *proto.TaskLabelsRequest) (*proto.TaskLabelsResponse, error) {
+	return s.h.grpcClient.DetachLabels(ctx, in)
+}
+
+// CreateComment adds a comment written by the caller.
+func (s *taskServer) CreateComment(ctx context.Context, in *proto.CreateCommentRequest) (*proto.Comment, error) {
+	author, err := requireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateCommentBody(in.Body); err != nil {
+		return nil, invalidArgument(err)
+	}
+	if _, err := s.h.grpcClient.GetTask(ctx, &proto.GetTaskRequest{Id: in.TaskId}); err != nil {
+		return nil, err
+	}
+	if in.ParentId != "" {
+		parent, err := s.h.grpcClient.GetComment(ctx, &proto.GetCommentRequest{Id: in.ParentId})
+		if err != nil || parent.TaskId != in.TaskId {
+			return nil, status.Errorf(codes.InvalidArgument, "comment %q does not exist on task %q", in.ParentId, in.TaskId)
+		}
+	}
+	return s.h.grpcClient.CreateComment(ctx, &proto.CreateCommentRequest{
+		TaskId:   in.TaskId,
+		ParentId: in.ParentId,
+		Author:   author,
+		Body:     in.Body,
+	})
+}
+
+func (s *taskServer) GetComment(ctx context.Context, in *proto.GetCommentRequest) (*proto.Comment, error) {
+	return s.h.grpcClient.GetComment(ctx, in)
+}
+
+func (s *taskServer) UpdateComment(ctx context.Context, in *proto.UpdateCommentRequest) (*proto.Comment, error) {
+	if err := validateCommentBody(in.Body); err != nil {
+		return nil, invalidArgument(err)
+	}
+	if err := s.authorizeComment(ctx, in.Id); err != nil {
+		return nil, err
+	}
+	return s.h.grpcClient.UpdateComment(ctx, in)
+}
+
+func (s *taskServer) DeleteComment(ctx context.Context, in *proto.DeleteCommentRequest) (*proto.DeleteCommentResponse, error) {
+	if err := s.authorizeComment(ctx, in.Id); err != nil {
+		return nil, err
+	}
+	return s.h.grpcClient.DeleteComment(ctx, in)
+}
+
+// authorizeComment checks that the caller wrote the comment.
+func (s *taskServer) authorizeComment(ctx context.Context, id string) error {
+	user, err := requireUser(ctx)
+	if err != nil {
+		return err
+	}
+	comment, err := s.h.grpcClient.GetComment(ctx, &proto.GetCommentRequest{Id: id})
+	if err != nil {
+		return err
+	}
+	if comment.Author != user {
+		return status.Error(codes.PermissionDenied, "only the author can change a comment")
+	}
+	return nil
+}
+
+func (s *taskServer) ListComments(ctx context.Context, in *proto.ListCommentsRequest) (*proto.ListCommentsResponse, error) {
+	return s.h.grpcClient.ListComments(ctx, in)
+}
+
+// AddAttachment is reserved for admins: the content of attachments is
+// uploaded through the REST API, which records it with this method.
+func (s *taskServer) AddAttachment(ctx context.Context, in *proto.AddAttachmentRequest) (*proto.Attachment, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.h.grpcClient.AddAttachment(ctx, in)
+}
+
+func (s *taskServer) GetAttachment(ctx context.Context, in *proto.GetAttachmentRequest) (*proto.Attachment, error) {
+	return s.h.grpcClient.GetAttachment(ctx, in)
+}
+
+// DeleteAttachment deletes the content of the attachment along with it.
+func (s *taskServer) DeleteAttachment(ctx context.Context, in *proto.DeleteAttachmentRequest) (*proto.DeleteAttachmentResponse, error) {
+	attachment, err := s.h.grpcClient.GetAttachment(ctx, &proto.GetAttachmentRequest{Id: in.Id})
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.h.grpcClient.DeleteAttachment(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	s.h.deleteBlob(attachment.StorageKey)
+	return res, nil
+}
+
+func (s *taskServer) ListAttachments(ctx context.Context, in *proto.ListAttachmentsRequest) (*proto.ListAttachmentsResponse, error) {
+	return s.h.grpcClient.ListAttachments(ctx, in)
+}
+
+// AddDependency rejects edges that would close a cycle.
+func (s *taskServer) AddDependency(ctx context.Context, in *proto.TaskDependencyRequest) (*proto.TaskDependenciesResponse, error) {
+	if in.BlockedBy == in.TaskId {
+		return nil, status.Error(codes.InvalidArgument, "a task cannot block itself")
+	}
+	cache := newTaskCache(ctx, s.h.grpcClient)
+	for _, id := range []string{in.TaskId, in.BlockedBy} {
+		task, err := cache.get(id)
+		if err != nil {
+			return nil, err
+		}
+		if task == nil {
+			return nil, status.Errorf(codes.NotFound, "task %q does not exist", id)
+		}
+	}
+	cycle, err := findDependencyPath(cache, in.BlockedBy, in.TaskId)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if cycle != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "dependency would create a cycle: %v", append([]string{in.TaskId}, cycle...))
+	}
+	return s.h.grpcClient.AddDependency(ctx, in)
+}
+
+func (s *taskServer) RemoveDependency(ctx context.Context, in *proto.TaskDependencyRequest) (*proto.TaskDependenciesResponse, error) {
+	return s.h.grpcClient.RemoveDependency(ctx, in)
+}
+
+// CreateReminder fires at remind_at, or offset_seconds before the task is
+// due when remind_at is unset. It is addressed like POST
+// /task/:id/reminders.
+func (s *taskServer) CreateReminder(ctx context.Context, in *proto.CreateReminderRequest) (*proto.Reminder, error) {
+	if in.GetReminder() == nil {
+		return nil, status.Error(codes.InvalidArgument, "reminder is required")
+	}
+	r := gproto.Clone(in.Reminder).(*proto.Reminder)
+	task, err := s.h.grpcClient.GetTask(ctx, &proto.GetTaskRequest{Id: r.TaskId})
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case r.RemindAt != nil && r.OffsetSeconds != 0:
+		return nil, status.Error(codes.InvalidArgument, "remind_at and offset_seconds cannot be combined")
+	case r.RemindAt != nil:
+		if !r.RemindAt.AsTime().After(time.Now()) {
+			return nil, status.Error(codes.InvalidArgument, "remind_at must be in the future")
+		}
+	case task.DueAt == nil:
+		return nil, status.Error(codes.InvalidArgument, "offset_seconds needs a task with a due date")
+	case r.OffsetSeconds < 0 || time.Duration(r.OffsetSeconds)*time.Second > maxReminderOffset:
+		return nil, status.Errorf(codes.InvalidArgument, "offset_seconds must be between 0 and %d", int64(maxReminderOffset/time.Second))
+	}
+	user := auth.UserFromContext(ctx)
+	if err := s.h.addressReminder(r, task, user, r.Channels); err != nil {
+		return nil, invalidArgument(err)
+	}
+	r.CreatedBy = user
+	r.Id, r.CreatedAt, r.FireAt, r.FiredAt = "", nil, nil, nil
+
+	created, err := s.h.grpcClient.CreateReminder(ctx, &proto.CreateReminderRequest{Reminder: r})
+	if err != nil {
+		return nil, err
+	}
+	s.h.reminders.Wake()
+	return created, nil
+}
+
+func (s *taskServer) GetReminder(ctx context.Context, in *proto.GetReminderRequest) (*proto.Reminder, error) {
+	return s.h.grpcClient.GetReminder(ctx, in)
+}
+
+func (s *taskServer) DeleteReminder(ctx context.Context, in *proto.DeleteReminderRequest) (*proto.DeleteReminderResponse, error) {
+	return s.h.grpcClient.DeleteReminder(ctx, in)
+}
+
+func (s *taskServer) ListReminders(ctx context.Context, in *proto.ListRemindersRequest) (*proto.ListRemindersResponse, error) {
+	return s.h.grpcClient.ListReminders(ctx, in)
+}
+
+func (s *taskServer) MarkReminderFired(ctx context.Context, in *proto.MarkReminderFiredRequest) (*proto.Reminder, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.h.grpcClient.MarkReminderFired(ctx, in)
+}
+
+func (s *taskServer) RecordActivity(ctx context.Context, in *proto.RecordActivityRequest) (*proto.Activity, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.h.grpcClient.RecordActivity(ctx, in)
+}
+
+func (s *taskServer) ListActivity(ctx context.Context, in *proto.ListActivityRequest) (*proto.ListActivityResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.h.grpcClient.ListActivity(ctx, in)
+}
+
+func (s *taskServer) CreateFeedToken(ctx context.Context, in *proto.CreateFeedTokenRequest) (*proto.FeedToken, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.h.grpcClient.CreateFeedToken(ctx, in)
+}
+
+func (s *taskServer) GetFeedToken(ctx context.Context, in *proto.GetFeedTokenRequest) (*proto.FeedToken, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.h.grpcClient.GetFeedToken(ctx, in)
+}
+
+func (s *taskServer) DeleteFeedToken(ctx context.Context, in *proto.DeleteFeedTokenRequest) (*proto.DeleteFeedTokenResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.h.grpcClient.DeleteFeedToken(ctx, in)
+}
+
+func (s *taskServer) ListFeedTokens(ctx context.Context, in *proto.ListFeedTokensRequest) (*proto.ListFeedTokensResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.h.grpcClient.ListFeedTokens(ctx, in)
+}
+
+// CreateWebhook checks the webhook like POST /webhooks and generates its
+// secret when it has none.
+func (s *taskServer) CreateWebhook(ctx context.Context, in *proto.CreateWebhookRequest) (*proto.Webhook, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := validateWebhookMessage(in.GetWebhook()); err != nil {
+		return nil, err
+	}
+	hook := gproto.Clone(in.Webhook).(*proto.Webhook)
+	if hook.Secret == "" {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+		hook.Secret = secret
+	}
+	hook.CreatedBy = auth.UserFromContext(ctx)
+	return s.h.grpcClient.CreateWebhook(ctx, &proto.CreateWebhookRequest{Webhook: hook})
+}
+
+func validateWebhookMessage(hook *proto.Webhook) error {
+	if hook == nil {
+		return status.Error(codes.InvalidArgument, "webhook is required")
+	}
+	if err := validateWebhook(&webhookRequest{URL: hook.Url, EventTypes: hook.EventTypes}); err != nil {
+		return invalidArgument(err)
+	}
+	return nil
+}
+
+func (s *taskServer) GetWebhook(ctx context.Context, in *proto.GetWebhookRequest) (*proto.Webhook, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.h.grpcClient.GetWebhook(ctx, in)
+}
+
+func (s *taskServer) UpdateWebhook(ctx context.Context, in *proto.UpdateWebhookRequest) (*proto.Webhook, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := validateWebhookMessage(in.GetWebhook()); err != nil {
+		return nil, err
+	}
+	return s.h.grpcClient.UpdateWebhook(ctx, in)
+}
+
+func (s *taskServer) DeleteWebhook(ctx context.Context, in *proto.DeleteWebhookRequest) (*proto.DeleteWebhookResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.h.grpcClient.DeleteWebhook(ctx, in)
+}
+
+func (s *taskServer) ListWebhooks(ctx context.Context, in *proto.ListWebhooksRequest) (*proto.ListWebhooksResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.h.grpcClient.ListWebhooks(ctx, in)
+}
+
+func (s *taskServer) CreateWebhookDelivery(ctx context.Context, in *proto.CreateWebhookDeliveryRequest) (*proto.WebhookDelivery, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.h.grpcClient.CreateWebhookDelivery(ctx, in)
+}
+
+func (s *taskServer) GetWebhookDelivery(ctx context.Context, in *proto.GetWebhookDeliveryRequest) (*proto.WebhookDelivery, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.h.grpcClient.GetWebhookDelivery(ctx, in)
+}
+
+func (s *taskServer) UpdateWebhookDelivery(ctx context.Context, in *proto.UpdateWebhookDeliveryRequest) (*proto.WebhookDelivery, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.h.grpcClient.UpdateWebhookDelivery(ctx, in)
+}
+
+func (s *taskServer) ListWebhookDeliveries(ctx context.Context, in *proto.ListWebhookDeliveriesRequest) (*proto.ListWebhookDeliveriesResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.h.grpcClient.ListWebhookDeliveries(ctx, in)
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/workflow"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func newTaskServer(t *testing.T, tasks *fakeTaskService) proto.TaskServiceServer {
+	t.Helper()
+	stateMachine, err := workflow.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewTaskHandler(tasks, stateMachine, nil, nil, nil, nil, nil, nil, nil).TaskServer()
+}
+
+func TestGRPCUpdateTaskMask(t *testing.T) {
+	tasks := newFakeTaskService()
+	ctx := context.Background()
+	// Task 2 is a child of task 1.
+	for _, req := range []*proto.CreateTaskRequest{
+		{Title: "Plan the release", Status: "todo"},
+		{Title: "Write the notes", Status: "todo", ParentId: "1"},
+		{Title: "Tag the build", Status: "todo"},
+	} {
+		if _, err := tasks.CreateTask(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := newTaskServer(t, tasks)
+
+	for _, tc := range []struct {
+		name     string
+		id       string
+		parentID string
+		paths    []string
+		code     codes.Code
+	}{
+		{"a task below its child", "1", "2", []string{"parent_id"}, codes.FailedPrecondition},
+		{"a task below itself", "3", "3", []string{"parent_id"}, codes.FailedPrecondition},
+		{"a missing parent", "3", "42", []string{"parent_id"}, codes.NotFound},
+		{"an unchecked field", "3", "", []string{"title", "series_start"}, codes.InvalidArgument},
+		{"an unknown field", "3", "", []string{"external_id"}, codes.InvalidArgument},
+		{"a valid move", "3", "1", []string{"parent_id"}, codes.OK},
+	} {
+		_, err := s.UpdateTask(ctx, &proto.UpdateTaskRequest{
+			Id:         tc.id,
+			ParentId:   tc.parentID,
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: tc.paths},
+		})
+		if got := status.Code(err); got != tc.code {
+			t.Errorf("%s: got %v (%v), want %v", tc.name, got, err, tc.code)
+		}
+	}
+	if got := tasks.tasks["1"].ParentId; got != "" {
+		t.Errorf("task 1 was moved below %q", got)
+	}
+	if got := tasks.tasks["3"].ParentId; got != "1" {
+		t.Errorf("task 3 has parent %q, want 1", got)
+	}
+}
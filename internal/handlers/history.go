@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/auth"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/taskinput"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxParentDepth bounds the parent chain walked when a task is moved.
+const maxParentDepth = 100
+
+var (
+	errParentCycle   = errors.New("a task cannot be moved below itself")
+	errParentTooDeep = errors.New("the parent chain is too deep")
+	errUnknownParent = errors.New("parent does not exist")
+)
+
+type activityView struct {
+	ID      string             `json:"id"`
+	TaskID  string             `json:"task_id"`
+	Action  string             `json:"action"`
+	Actor   string             `json:"actor,omitempty"`
+	Time    string             `json:"time"`
+	Changes []*fieldChangeView `json:"changes"`
+}
+
+type fieldChangeView struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+type moveRequest struct {
+	ParentID string `json:"parent_id"`
+}
+
+func newActivityView(a *proto.Activity, loc *time.Location) *activityView {
+	view := &activityView{
+		ID:      a.Id,
+		TaskID:  a.TaskId,
+		Action:  a.Action,
+		Actor:   a.Actor,
+		Time:    formatTimestamp(a.Time, loc),
+		Changes: make([]*fieldChangeView, 0, len(a.Changes)),
+	}
+	for _, change := range a.Changes {
+		view.Changes = append(view.Changes, &fieldChangeView{
+			Field:  change.Field,
+			Before: change.Before,
+			After:  change.After,
+		})
+	}
+	return view
+}
+
+// taskHistory lists the activity of a task, newest first.
+func (h *TaskHandler) taskHistory(c *gin.Context) {
+	h.respondActivity(c, &proto.ListActivityRequest{TaskId: c.Param("id")})
+}
+
+// auditLog lists the activity of all tasks for admins, optionally filtered
+// by actor and by time with from and to.
+func (h *TaskHandler) auditLog(c *gin.Context) {
+	if !h.requireAdmin(c, "the audit log is restricted to admins") {
+		return
+	}
+
+	vars := c.Request.URL.Query()
+	loc, err := taskinput.LoadLocation(vars.Get("tz"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req := &proto.ListActivityRequest{Actor: vars.Get("actor")}
+	if req.From, err = parseTimestampParam(vars, "from", loc, false); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.To, err = parseTimestampParam(vars, "to", loc, true); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.respondActivity(c, req)
+}
+
+// requireAdmin responds with an error unless the caller is an admin, using
+// denied as the message of the 403 response.
+func (h *TaskHandler) requireAdmin(c *gin.Context, denied string) bool {
+	user := c.GetHeader(auth.UserHeader)
+	if user == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": auth.UserHeader + " header is required"})
+		return false
+	}
+	if !h.admins[user] {
+		c.JSON(http.StatusForbidden, gin.H{"error": denied})
+		return false
+	}
+	return true
+}
+
+func (h *TaskHandler) respondActivity(c *gin.Context, req *proto.ListActivityRequest) {
+	vars := c.Request.URL.Query()
+	loc, err := taskinput.LoadLocation(vars.Get("tz"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	page, pageSize := parsePagination(vars)
+	req.Page, req.PageSize = int32(page), int32(pageSize)
+
+	res, err := h.grpcClient.ListActivity(context.Background(), req)
+	if err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": s})
+		return
+	}
+	var resp struct {
+		Results interface{} `json:"results"`
+	}
+
+	views := make([]*activityView, 0, len(res.Activities))
+	for _, a := range res.Activities {
+		views = append(views, newActivityView(a, loc))
+	}
+	resp.Results = views
+	c.JSON(http.StatusOK, resp)
+}
+
+// moveTask gives the task a new parent, or makes it a top-level task when
+// parent_id is empty. A task cannot be moved below itself.
+func (h *TaskHandler) moveTask(c *gin.Context) {
+	var req moveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	id := c.Param("id")
+	ctx := context.Background()
+	if _, err := h.grpcClient.GetTask(ctx, &proto.GetTaskRequest{Id: id}); err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusNotFound, gin.H{"error": s})
+		return
+	}
+
+	if err := checkParent(ctx, h.grpcClient, id, req.ParentID); err != nil {
+		code := http.StatusConflict
+		if errors.Is(err, errUnknownParent) {
+			code = http.StatusNotFound
+		}
+		c.JSON(code, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.grpcClient.UpdateTask(requestContext(c), &proto.UpdateTaskRequest{
+		Id:         id,
+		ParentId:   req.ParentID,
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"parent_id"}},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// checkParent walks the parent chain from parentID and fails when it reaches
+// the task id, which would become its own ancestor. An empty parentID makes
+// the task a top-level task.
+func checkParent(ctx context.Context, client proto.TaskServiceClient, id, parentID string) error {
+	for depth := 0; parentID != ""; depth++ {
+		if parentID == id {
+			return errParentCycle
+		}
+		if depth == maxParentDepth {
+			return errParentTooDeep
+		}
+		parent, err := client.GetTask(ctx, &proto.GetTaskRequest{Id: parentID})
+		if err != nil {
+			return fmt.Errorf("%w: task %q", errUnknownParent, parentID)
+		}
+		parentID = parent.ParentId
+	}
+	return nil
+}
+
+func parseTimestampParam(vars url.Values, name string, loc *time.Location, endOfDay bool) (*timestamppb.Timestamp, error) {
+	v := vars.Get(name)
+	if v == "" {
+		return nil, nil
+	}
+	t, err := taskinput.ParseTime(v, loc, endOfDay)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return timestamppb.New(t), nil
+}
@@ -0,0 +1,544 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/auth"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	maxImportSize = 32 << 20
+	maxImportRows = 10000
+
+	importStatusValid   = "valid"
+	importStatusCreated = "created"
+	importStatusSkipped = "skipped"
+	importStatusFailed  = "failed"
+)
+
+// importFields are the task fields import columns can be mapped to.
+var importFields = []string{
+	"external_id", "parent_external_id", "parent_id", "title", "description",
+	"status", "priority", "due_at", "timezone", "assignee", "reporter", "labels",
+}
+
+// importRecord is a row of the uploaded file keyed by source column. Values
+// are strings, or string slices for JSON arrays.
+type importRecord struct {
+	row    int
+	values map[string]interface{}
+}
+
+// importRow is a record mapped to task fields and its outcome.
+type importRow struct {
+	Row        int      `json:"row"`
+	ExternalID string   `json:"external_id,omitempty"`
+	Status     string   `json:"status"`
+	ID         string   `json:"id,omitempty"`
+	Errors     []string `json:"errors,omitempty"`
+
+	fields map[string]string
+	labels []string
+	req    *proto.CreateTaskRequest
+	// parent is the index of the row creating the parent, or -1.
+	parent int
+	depth  int
+}
+
+type importReport struct {
+	DryRun  bool         `json:"dry_run"`
+	Total   int          `json:"total"`
+	Valid   int          `json:"valid"`
+	Created int          `json:"created"`
+	Skipped int          `json:"skipped"`
+	Failed  int          `json:"failed"`
+	Rows    []*importRow `json:"rows"`
+}
+
+// importTasks creates tasks from a CSV or JSON upload. The mapping query
+// parameter maps task fields to source columns as "field:column" pairs;
+// unmapped fields are read from the column of the same name. Parents are
+// referenced by parent_id or by the external id of a task in the file or
+// imported before. Rows whose external id exists already are skipped. With
+// dry_run=true the rows are only validated.
+func (h *TaskHandler) importTasks(c *gin.Context) {
+	vars := c.Request.URL.Query()
+	format, err := importFormat(vars.Get("format"), c.ContentType())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	dryRun, _ := strconv.ParseBool(vars.Get("dry_run"))
+	mapping, err := parseImportMapping(vars.Get("mapping"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	extendDeadlines(c)
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	var records []importRecord
+	if format == exportFormatCSV {
+		records, err = readCSVRecords(body)
+	} else {
+		records, err = readJSONRecords(body)
+	}
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("imports must not exceed %d bytes", maxImportSize)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	imp := &taskImport{
+		h:      h,
+		user:   c.GetHeader(auth.UserHeader),
+		rows:   mapImportRecords(records, mapping),
+		labels: map[string]bool{},
+	}
+	ctx := context.Background()
+	if err := imp.validate(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !dryRun {
+		imp.run(requestContext(c))
+	}
+	c.JSON(http.StatusOK, imp.report(dryRun))
+}
+
+func importFormat(format, contentType string) (string, error) {
+	if format == "" {
+		switch mediaType, _, _ := mime.ParseMediaType(contentType); mediaType {
+		case "text/csv":
+			format = exportFormatCSV
+		case "application/json":
+			format = exportFormatJSON
+		}
+	}
+	if format != exportFormatCSV && format != exportFormatJSON {
+		return "", errors.New("format must be csv or json")
+	}
+	return format, nil
+}
+
+// parseImportMapping reads "field:column" pairs into a map from field to
+// column.
+func parseImportMapping(value string) (map[string]string, error) {
+	mapping := make(map[string]string, len(importFields))
+	for _, field := range importFields {
+		mapping[field] = field
+	}
+	for _, pair := range splitList(value) {
+		field, column, ok := strings.Cut(pair, ":")
+		field, column = strings.TrimSpace(field), strings.TrimSpace(column)
+		if !ok || column == "" {
+			return nil, fmt.Errorf("mapping %q must be field:column", pair)
+		}
+		if _, known := mapping[field]; !known {
+			return nil, fmt.Errorf("unknown field %q in mapping, use %s", field, strings.Join(importFields, ", "))
+		}
+		mapping[field] = column
+	}
+	return mapping, nil
+}
+
+// readCSVRecords reads a CSV file whose first row names the columns. Rows
+// are numbered as in a spreadsheet, the header being row 1. The quotes
+// that exports put before cells starting like a formula are removed.
+func readCSVRecords(r io.Reader) ([]importRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("the file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+	var records []importRecord
+	for row := 2; ; row++ {
+		values, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(records) == maxImportRows {
+			return nil, fmt.Errorf("imports must not exceed %d rows", maxImportRows)
+		}
+		record := importRecord{row: row, values: make(map[string]interface{}, len(header))}
+		for i, column := range header {
+			if i < len(values) {
+				record.values[strings.TrimSpace(column)] = csvCellValue(values[i])
+			}
+		}
+		records = append(records, record)
+	}
+}
+
+// readJSONRecords reads a JSON array of objects, numbering them from 1.
+func readJSONRecords(r io.Reader) ([]importRecord, error) {
+	dec := json.NewDecoder(r)
+	// Numbers are kept as written, so that numeric ids such as 1234567 do
+	// not become 1.234567e+06.
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return nil, errors.New("the file must hold a JSON array of objects")
+	}
+	var records []importRecord
+	for row := 1; dec.More(); row++ {
+		if len(records) == maxImportRows {
+			return nil, fmt.Errorf("imports must not exceed %d rows", maxImportRows)
+		}
+		var object map[string]interface{}
+		if err := dec.Decode(&object); err != nil {
+			return nil, fmt.Errorf("row %d: %w", row, err)
+		}
+		record := importRecord{row: row, values: make(map[string]interface{}, len(object))}
+		for column, v := range object {
+			switch v := v.(type) {
+			case nil:
+			case string:
+				record.values[column] = v
+			case []interface{}:
+				list := make([]string, 0, len(v))
+				for _, item := range v {
+					list = append(list, jsonImportValue(item))
+				}
+				record.values[column] = list
+			default:
+				record.values[column] = jsonImportValue(v)
+			}
+		}
+		records = append(records, record)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// jsonImportValue formats a JSON scalar read with UseNumber as a cell.
+func jsonImportValue(v interface{}) string {
+	if n, ok := v.(json.Number); ok {
+		return n.String()
+	}
+	return fmt.Sprint(v)
+}
+
+func mapImportRecords(records []importRecord, mapping map[string]string) []*importRow {
+	rows := make([]*importRow, 0, len(records))
+	for _, record := range records {
+		row := &importRow{Row: record.row, fields: map[string]string{}, parent: -1}
+		for _, field := range importFields {
+			switch v := record.values[mapping[field]].(type) {
+			case string:
+				if field == "labels" {
+					row.labels = splitImportList(v)
+				} else {
+					row.fields[field] = strings.TrimSpace(v)
+				}
+			case []string:
+				if field == "labels" {
+					row.labels = v
+				} else {
+					row.addError(fmt.Errorf("%s must not be a list", field))
+				}
+			}
+		}
+		row.ExternalID = row.fields["external_id"]
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// splitImportList splits the list cells written by exports.
+func splitImportList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, exportListSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (r *importRow) addError(err error) {
+	r.Errors = append(r.Errors, err.Error())
+}
+
+// taskImport validates and creates the rows of one import.
+type taskImport struct {
+	h    *TaskHandler
+	user string
+	rows []*importRow
+	// labels caches whether a label exists.
+	labels map[string]bool
+	mu     sync.Mutex
+}
+
+// validate checks every row, resolves parents and marks rows that already
+// exist as skipped. It only fails when the backend cannot be read.
+func (imp *taskImport) validate(ctx context.Context) error {
+	byExternalID := map[string]int{}
+	for i, row := range imp.rows {
+		if row.fields["title"] == "" {
+			row.addError(errors.New("title is required"))
+		}
+		if err := imp.buildRequest(row); err != nil {
+			row.addError(err)
+		}
+		if err := imp.checkLabels(ctx, row.labels); err != nil {
+			if status.Code(err) != codes.NotFound {
+				return err
+			}
+			row.addError(errors.New(status.Convert(err).Message()))
+		}
+		if id := row.ExternalID; id != "" {
+			if first, ok := byExternalID[id]; ok {
+				row.addError(fmt.Errorf("external_id %q is used by row %d too", id, imp.rows[first].Row))
+			} else {
+				byExternalID[id] = i
+			}
+		}
+	}
+
+	existing, err := imp.existingTasks(ctx)
+	if err != nil {
+		return err
+	}
+	cache := newTaskCache(ctx, imp.h.grpcClient)
+	for _, row := range imp.rows {
+		if id, ok := existing[row.ExternalID]; ok && row.ExternalID != "" {
+			row.Status, row.ID = importStatusSkipped, id
+		}
+		parentID, parentExternalID := row.fields["parent_id"], row.fields["parent_external_id"]
+		switch {
+		case parentID != "" && parentExternalID != "":
+			row.addError(errors.New("parent_id and parent_external_id cannot be combined"))
+		case parentID != "":
+			parent, err := cache.get(parentID)
+			if err != nil {
+				return err
+			}
+			if parent == nil {
+				row.addError(fmt.Errorf("parent task %q does not exist", parentID))
+			}
+		case parentExternalID != "":
+			if i, ok := byExternalID[parentExternalID]; ok {
+				row.parent = i
+			} else if id, ok := existing[parentExternalID]; ok {
+				row.fields["parent_id"] = id
+			} else {
+				row.addError(fmt.Errorf("no task has external_id %q", parentExternalID))
+			}
+		}
+		if row.req != nil {
+			row.req.ParentId = row.fields["parent_id"]
+		}
+	}
+
+	for i := range imp.rows {
+		imp.resolveDepth(i, map[int]bool{})
+	}
+	for _, row := range imp.rows {
+		if row.Status == "" {
+			row.Status = importStatusValid
+			if len(row.Errors) > 0 {
+				row.Status = importStatusFailed
+			}
+		}
+	}
+	return nil
+}
+
+func (imp *taskImport) buildRequest(row *importRow) error {
+	in := &createTaskRequest{
+		taskInput: taskInput{
+			Title:       row.fields["title"],
+			Description: row.fields["description"],
+			Status:      row.fields["status"],
+			DueAt:       row.fields["due_at"],
+			Timezone:    row.fields["timezone"],
+			Priority:    row.fields["priority"],
+			Assignee:    row.fields["assignee"],
+			Reporter:    row.fields["reporter"],
+		},
+		ExternalID: row.ExternalID,
+	}
+	req, err := imp.h.createRequest(imp.user, in)
+	if err != nil {
+		return err
+	}
+	row.req = req
+	return nil
+}
+
+func (imp *taskImport) checkLabels(ctx context.Context, labels []string) error {
+	for _, name := range labels {
+		exists, ok := imp.labels[name]
+		if !ok {
+			_, err := imp.h.grpcClient.GetLabel(ctx, &proto.GetLabelRequest{Name: name})
+			if err != nil && status.Code(err) != codes.NotFound {
+				return err
+			}
+			exists = err == nil
+			imp.labels[name] = exists
+		}
+		if !exists {
+			return status.Errorf(codes.NotFound, "label %q does not exist", name)
+		}
+	}
+	return nil
+}
+
+// existingTasks maps the external ids referenced by the import to the
+// tasks that carry them already.
+func (imp *taskImport) existingTasks(ctx context.Context) (map[string]string, error) {
+	seen := map[string]bool{}
+	var ids []string
+	for _, row := range imp.rows {
+		for _, id := range []string{row.ExternalID, row.fields["parent_external_id"]} {
+			if id != "" && !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	existing := map[string]string{}
+	for start := 0; start < len(ids); start += exportPageSize {
+		end := min(start+exportPageSize, len(ids))
+		res, err := imp.h.grpcClient.ListTasks(ctx, &proto.ListTasksRequest{
+			ExternalIds: ids[start:end],
+			PageSize:    exportPageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range res.Tasks {
+			existing[task.ExternalId] = task.Id
+		}
+	}
+	return existing, nil
+}
+
+// resolveDepth sets the depth of row i below the rows creating its
+// ancestors, failing rows whose parent row fails or that are their own
+// ancestor.
+func (imp *taskImport) resolveDepth(i int, visiting map[int]bool) bool {
+	row := imp.rows[i]
+	if row.parent < 0 || row.depth > 0 {
+		return len(row.Errors) == 0
+	}
+	if visiting[i] {
+		row.addError(errors.New("the row is its own ancestor"))
+		return false
+	}
+	visiting[i] = true
+	defer delete(visiting, i)
+
+	parent := imp.rows[row.parent]
+	if !imp.resolveDepth(row.parent, visiting) && len(row.Errors) == 0 {
+		row.addError(fmt.Errorf("parent row %d is invalid", parent.Row))
+	}
+	row.depth = parent.depth + 1
+	return len(row.Errors) == 0
+}
+
+// run creates the valid rows, parents before children, running rows of the
+// same depth in parallel.
+func (imp *taskImport) run(ctx context.Context) {
+	var levels [][]int
+	for i, row := range imp.rows {
+		for len(levels) <= row.depth {
+			levels = append(levels, nil)
+		}
+		levels[row.depth] = append(levels[row.depth], i)
+	}
+
+	sem := make(chan struct{}, batchConcurrency)
+	for _, level := range levels {
+		var wg sync.WaitGroup
+		for _, i := range level {
+			row := imp.rows[i]
+			if row.Status != importStatusValid {
+				continue
+			}
+			if row.parent >= 0 {
+				parent := imp.rows[row.parent]
+				if parent.ID == "" {
+					row.Status = importStatusFailed
+					row.addError(fmt.Errorf("parent row %d was not created", parent.Row))
+					continue
+				}
+				row.req.ParentId = parent.ID
+			}
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(row *importRow) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				imp.create(ctx, row)
+			}(row)
+		}
+		wg.Wait()
+	}
+}
+
+func (imp *taskImport) create(ctx context.Context, row *importRow) {
+	res, err := imp.h.grpcClient.CreateTask(ctx, row.req)
+	imp.mu.Lock()
+	defer imp.mu.Unlock()
+	if err != nil {
+		row.Status = importStatusFailed
+		row.addError(err)
+		return
+	}
+	row.Status, row.ID = importStatusCreated, res.Id
+	if len(row.labels) > 0 {
+		_, err = imp.h.grpcClient.AttachLabels(ctx, &proto.TaskLabelsRequest{TaskId: res.Id, Labels: row.labels})
+		if err != nil {
+			row.addError(fmt.Errorf("labels: %w", err))
+		}
+	}
+}
+
+func (imp *taskImport) report(dryRun bool) *importReport {
+	report := &importReport{DryRun: dryRun, Total: len(imp.rows), Rows: imp.rows}
+	for _, row := range imp.rows {
+		switch row.Status {
+		case importStatusValid:
+			report.Valid++
+		case importStatusCreated:
+			report.Created++
+		case importStatusSkipped:
+			report.Skipped++
+		case importStatusFailed:
+			report.Failed++
+		}
+	}
+	if report.Rows == nil {
+		report.Rows = []*importRow{}
+	}
+	return report
+}
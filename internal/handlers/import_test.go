@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCSVCellRoundTrip(t *testing.T) {
+	for _, title := range []string{
+		"", "fix login", "-fix login", "=total", "+1 for this", "@alice", "\ttabbed",
+		"'quoted", "'=already quoted", "''-twice", "it's fine",
+	} {
+		if got := csvCellValue(csvCell(title)); got != title {
+			t.Errorf("csvCellValue(csvCell(%q)) = %q", title, got)
+		}
+	}
+}
+
+func TestImportReadsExportedCSV(t *testing.T) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"title", "labels"})
+	_ = w.Write([]string{csvCell("-fix login"), csvCell("-ops" + exportListSeparator + "urgent")})
+	_ = w.Write([]string{csvCell("=total"), csvCell("")})
+	w.Flush()
+
+	records, err := readCSVRecords(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := mapImportRecords(records, map[string]string{"title": "title", "labels": "labels"})
+	if len(rows) != 2 {
+		t.Fatalf("read %d rows, want 2", len(rows))
+	}
+	if got := rows[0].fields["title"]; got != "-fix login" {
+		t.Errorf("title = %q, want -fix login", got)
+	}
+	if got := rows[0].labels; !slices.Equal(got, []string{"-ops", "urgent"}) {
+		t.Errorf("labels = %q, want [-ops urgent]", got)
+	}
+	if got := rows[1].fields["title"]; got != "=total" {
+		t.Errorf("title = %q, want =total", got)
+	}
+}
+
+func TestImportReadsNumericJSONIds(t *testing.T) {
+	records, err := readJSONRecords(strings.NewReader(`[
+		{"external_id": 1234567, "title": "Migrate the tracker", "labels": [2024, "ops"]},
+		{"external_id": 1234568, "parent_external_id": 1234567, "title": "Map the fields"}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := mapImportRecords(records, map[string]string{
+		"external_id": "external_id", "parent_external_id": "parent_external_id",
+		"title": "title", "labels": "labels",
+	})
+	if len(rows) != 2 {
+		t.Fatalf("read %d rows, want 2", len(rows))
+	}
+	if rows[0].ExternalID != "1234567" || rows[1].ExternalID != "1234568" {
+		t.Errorf("external ids = %q, %q, want 1234567, 1234568", rows[0].ExternalID, rows[1].ExternalID)
+	}
+	if got := rows[1].fields["parent_external_id"]; got != rows[0].ExternalID {
+		t.Errorf("parent_external_id = %q, want %q", got, rows[0].ExternalID)
+	}
+	if got := rows[0].labels; !slices.Equal(got, []string{"2024", "ops"}) {
+		t.Errorf("labels = %q, want [2024 ops]", got)
+	}
+}
@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	maxLabelNameLength = 64
+
+	labelModeAny = "any"
+	labelModeAll = "all"
+)
+
+var labelColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+type labelRequest struct {
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+}
+
+type labelView struct {
+	Name        string `json:"name"`
+	Color       string `json:"color,omitempty"`
+	Description string `json:"description,omitempty"`
+	CreatedAt   string `json:"created_at,omitempty"`
+	UpdatedAt   string `json:"updated_at,omitempty"`
+}
+
+type taskLabelsRequest struct {
+	Labels []string `json:"labels" binding:"required"`
+}
+
+func newLabelView(label *proto.Label) *labelView {
+	return &labelView{
+		Name:        label.Name,
+		Color:       label.Color,
+		Description: label.Description,
+		CreatedAt:   formatTimestamp(label.CreatedAt, time.UTC),
+		UpdatedAt:   formatTimestamp(label.UpdatedAt, time.UTC),
+	}
+}
+
+func (h *TaskHandler) createLabel(c *gin.Context) {
+	var req labelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateLabel(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	label, err := h.grpcClient.CreateLabel(context.Background(), &proto.CreateLabelRequest{
+		Name:        req.Name,
+		Color:       req.Color,
+		Description: req.Description,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, newLabelView(label))
+}
+
+func (h *TaskHandler) getLabel(c *gin.Context) {
+	label, err := h.grpcClient.GetLabel(context.Background(), &proto.GetLabelRequest{Name: c.Param("name")})
+	if err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusNotFound, gin.H{"error": s})
+		return
+	}
+	c.JSON(http.StatusOK, newLabelView(label))
+}
+
+func (h *TaskHandler) listLabels(c *gin.Context) {
+	res, err := h.grpcClient.ListLabels(context.Background(), &proto.ListLabelsRequest{})
+	if err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": s})
+		return
+	}
+	labels := make([]*labelView, 0, len(res.Labels))
+	for _, label := range res.Labels {
+		labels = append(labels, newLabelView(label))
+	}
+	var resp struct {
+		Results interface{} `json:"results"`
+	}
+
+	resp.Results = labels
+	c.JSON(http.StatusOK, resp)
+}
+
+// updateLabel replaces color and description of a label. When the body
+// carries a new name, every task tagged with the old name is relabelled.
+func (h *TaskHandler) updateLabel(c *gin.Context) {
+	var req labelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	name := c.Param("name")
+	if req.Name == "" {
+		req.Name = name
+	}
+	if err := validateLabel(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	grpcReq := &proto.UpdateLabelRequest{
+		Name:        name,
+		Color:       req.Color,
+		Description: req.Description,
+	}
+	if req.Name != name {
+		grpcReq.NewName = req.Name
+	}
+	label, err := h.grpcClient.UpdateLabel(context.Background(), grpcReq)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	relabelled := 0
+	if grpcReq.NewName != "" {
+		relabelled, err = h.relabelTasks(requestContext(c), name, grpcReq.NewName)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "relabelled": relabelled})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"label": newLabelView(label), "relabelled": relabelled})
+}
+
+// deleteLabel deletes a label and detaches it from every task.
+func (h *TaskHandler) deleteLabel(c *gin.Context) {
+	name := c.Param("name")
+	resp, err := h.grpcClient.DeleteLabel(context.Background(), &proto.DeleteLabelRequest{Name: name})
+	if err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": s})
+		return
+	}
+
+	relabelled, err := h.relabelTasks(requestContext(c), name, "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "relabelled": relabelled})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": resp.Success, "relabelled": relabelled})
+}
+
+// relabelTasks replaces the label from with to on every task carrying it,
+// or only removes it when to is empty. It returns the number of tasks
+// changed.
+func (h *TaskHandler) relabelTasks(ctx context.Context, from, to string) (int, error) {
+	const pageSize = 50
+	done := map[string]bool{}
+	for page := int32(0); ; {
+		// Relabelled tasks drop out of the result, so the same page holds
+		// the next tasks to change. Tasks that stay in it, because the label
+		// index lags behind or from and to are the same label, are changed
+		// only once; a full page of them is skipped, and a last one ends the
+		// loop.
+		res, err := h.grpcClient.ListTasks(ctx, &proto.ListTasksRequest{
+			Page:     page,
+			PageSize: pageSize,
+			Labels:   []string{from},
+		})
+		if err != nil {
+			return len(done), err
+		}
+		changed := false
+		for _, task := range res.Tasks {
+			if done[task.Id] {
+				continue
+			}
+			if to != "" {
+				_, err = h.grpcClient.AttachLabels(ctx, &proto.TaskLabelsRequest{TaskId: task.Id, Labels: []string{to}})
+				if err != nil {
+					return len(done), fmt.Errorf("relabelling task %s: %w", task.Id, err)
+				}
+			}
+			_, err = h.grpcClient.DetachLabels(ctx, &proto.TaskLabelsRequest{TaskId: task.Id, Labels: []string{from}})
+			if err != nil {
+				return len(done), fmt.Errorf("relabelling task %s: %w", task.Id, err)
+			}
+			done[task.Id] = true
+			changed = true
+		}
+		switch {
+		case changed:
+		case len(res.Tasks) < pageSize:
+			return len(done), nil
+		default:
+			page++
+		}
+	}
+}
+
+// attachLabels adds existing labels to a task.
+func (h *TaskHandler) attachLabels(c *gin.Context) {
+	var req taskLabelsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for _, name := range req.Labels {
+		_, err := h.grpcClient.GetLabel(context.Background(), &proto.GetLabelRequest{Name: name})
+		if status.Code(err) == codes.NotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("label %q does not exist", name)})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	res, err := h.grpcClient.AttachLabels(requestContext(c), &proto.TaskLabelsRequest{
+		TaskId: c.Param("id"),
+		Labels: req.Labels,
+	})
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *TaskHandler) detachLabel(c *gin.Context) {
+	res, err := h.grpcClient.DetachLabels(requestContext(c), &proto.TaskLabelsRequest{
+		TaskId: c.Param("id"),
+		Labels: []string{c.Param("label")},
+	})
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// validateLabel trims the label name and checks name and color.
+func validateLabel(req *labelRequest) error {
+	req.Name = strings.TrimSpace(req.Name)
+	switch {
+	case req.Name == "":
+		return errors.New("name is required")
+	case len(req.Name) > maxLabelNameLength:
+		return fmt.Errorf("name must not exceed %d characters", maxLabelNameLength)
+	case strings.ContainsAny(req.Name, ",/"):
+		return errors.New("name must not contain ',' or '/'")
+	case req.Color != "" && !labelColorPattern.MatchString(req.Color):
+		return errors.New("color must be a hex color such as #1f77b4")
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"google.golang.org/grpc"
+)
+
+// labelIndex lists the tasks carrying a label, a page at a time. When lags
+// is set, detached labels stay in the index, like a label index that is
+// only eventually consistent.
+type labelIndex struct {
+	proto.TaskServiceClient
+
+	labels   map[string][]string
+	lags     bool
+	detached int
+}
+
+func (l *labelIndex) ListTasks(_ context.Context, in *proto.ListTasksRequest, _ ...grpc.CallOption) (*proto.ListTasksResponse, error) {
+	var ids []string
+	for id, labels := range l.labels {
+		if slices.Contains(labels, in.Labels[0]) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	res := &proto.ListTasksResponse{}
+	for _, id := range ids[min(int(in.Page*in.PageSize), len(ids)):min(int((in.Page+1)*in.PageSize), len(ids))] {
+		res.Tasks = append(res.Tasks, &proto.Task{Id: id, Labels: l.labels[id]})
+	}
+	return res, nil
+}
+
+func (l *labelIndex) AttachLabels(_ context.Context, in *proto.TaskLabelsRequest, _ ...grpc.CallOption) (*proto.TaskLabelsResponse, error) {
+	l.labels[in.TaskId] = append(l.labels[in.TaskId], in.Labels...)
+	return &proto.TaskLabelsResponse{}, nil
+}
+
+func (l *labelIndex) DetachLabels(_ context.Context, in *proto.TaskLabelsRequest, _ ...grpc.CallOption) (*proto.TaskLabelsResponse, error) {
+	l.detached++
+	if !l.lags {
+		l.labels[in.TaskId] = slices.DeleteFunc(l.labels[in.TaskId], func(label string) bool {
+			return slices.Contains(in.Labels, label)
+		})
+	}
+	return &proto.TaskLabelsResponse{}, nil
+}
+
+func newLabelIndex(tasks int, lags bool) *labelIndex {
+	l := &labelIndex{labels: map[string][]string{}, lags: lags}
+	for i := 0; i < tasks; i++ {
+		l.labels[strconv.Itoa(1000+i)] = []string{"bug"}
+	}
+	return l
+}
+
+func TestRelabelTasks(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		tasks int
+		lags  bool
+	}{
+		{"consistent index", 120, false},
+		{"lagging index", 120, true},
+		{"lagging index with a full last page", 100, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			index := newLabelIndex(tc.tasks, tc.lags)
+			h := &TaskHandler{grpcClient: index}
+			relabelled, err := h.relabelTasks(context.Background(), "bug", "defect")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if relabelled != tc.tasks || index.detached != tc.tasks {
+				t.Errorf("relabelled %d tasks with %d detaches, want %d", relabelled, index.detached, tc.tasks)
+			}
+			for id, labels := range index.labels {
+				if !slices.Contains(labels, "defect") {
+					t.Errorf("task %s has labels %v, want defect", id, labels)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+)
+
+const (
+	// loaderWait is how long a loader collects ids before reading them.
+	loaderWait = 2 * time.Millisecond
+	// loaderMaxBatch is the most ids read with one ListTasks call.
+	loaderMaxBatch = 50
+)
+
+type taskLoaderKey struct{}
+
+// taskLoader batches the task reads made while resolving one GraphQL
+// operation: ids requested within loaderWait of each other are read with a
+// single ListTasks call. Every task read is cached for the life of the
+// loader, as are the children of a task.
+type taskLoader struct {
+	ctx    context.Context
+	client proto.TaskServiceClient
+
+	mu       sync.Mutex
+	tasks    map[string]*taskResult
+	batch    []string
+	children map[string]*childrenResult
+}
+
+type taskResult struct {
+	done chan struct{}
+	task *proto.Task
+	err  error
+}
+
+type childrenResult struct {
+	once  sync.Once
+	tasks []*proto.Task
+	err   error
+}
+
+func newTaskLoader(ctx context.Context, client proto.TaskServiceClient) *taskLoader {
+	return &taskLoader{
+		ctx:      ctx,
+		client:   client,
+		tasks:    map[string]*taskResult{},
+		children: map[string]*childrenResult{},
+	}
+}
+
+func withTaskLoader(ctx context.Context, l *taskLoader) context.Context {
+	return context.WithValue(ctx, taskLoaderKey{}, l)
+}
+
+func taskLoaderFrom(ctx context.Context) *taskLoader {
+	return ctx.Value(taskLoaderKey{}).(*taskLoader)
+}
+
+// load returns the task with the given id, or nil when it does not exist.
+func (l *taskLoader) load(ctx context.Context, id string) (*proto.Task, error) {
+	l.mu.Lock()
+	res, ok := l.tasks[id]
+	if !ok {
+		res = &taskResult{done: make(chan struct{})}
+		l.tasks[id] = res
+		l.batch = append(l.batch, id)
+		switch len(l.batch) {
+		case loaderMaxBatch:
+			go l.dispatch()
+		case 1:
+			time.AfterFunc(loaderWait, l.dispatch)
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-res.done:
+		return res.task, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// prime caches tasks that were read some other way.
+func (l *taskLoader) prime(tasks ...*proto.Task) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, task := range tasks {
+		if _, ok := l.tasks[task.Id]; !ok {
+			res := &taskResult{done: make(chan struct{}), task: task}
+			close(res.done)
+			l.tasks[task.Id] = res
+		}
+	}
+}
+
+// dispatch reads the collected ids.
+func (l *taskLoader) dispatch() {
+	l.mu.Lock()
+	batch := l.batch
+	l.batch = nil
+	l.mu.Unlock()
+	if len(batch) == 0 {
+		return
+	}
+
+	found := make(map[string]*proto.Task, len(batch))
+	res, err := l.client.ListTasks(l.ctx, &proto.ListTasksRequest{Ids: batch, PageSize: int32(len(batch))})
+	if err == nil {
+		for _, task := range res.Tasks {
+			found[task.Id] = task
+		}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, id := range batch {
+		r := l.tasks[id]
+		r.task, r.err = found[id], err
+		if err != nil {
+			// Let a later load try again.
+			delete(l.tasks, id)
+		}
+		close(r.done)
+	}
+}
+
+// loadChildren returns the children of the task, reading at most
+// maxOutlineTasks of them.
+func (l *taskLoader) loadChildren(id string) ([]*proto.Task, error) {
+	l.mu.Lock()
+	res, ok := l.children[id]
+	if !ok {
+		res = &childrenResult{}
+		l.children[id] = res
+	}
+	l.mu.Unlock()
+
+	res.once.Do(func() {
+		req := &proto.ListTasksRequest{ParentId: id, PageSize: exportPageSize}
+		for req.Page = 0; len(res.tasks) < maxOutlineTasks; req.Page++ {
+			page, err := l.client.ListTasks(l.ctx, req)
+			if err != nil {
+				res.tasks, res.err = nil, err
+				return
+			}
+			res.tasks = append(res.tasks, page.Tasks...)
+			if len(page.Tasks) < exportPageSize {
+				break
+			}
+		}
+		l.prime(res.tasks...)
+	})
+	return res.tasks, res.err
+}
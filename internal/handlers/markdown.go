@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/auth"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// maxOutlineTasks caps the tasks exported or imported as one outline.
+	maxOutlineTasks = 1000
+	maxOutlineSize  = 1 << 20
+	outlineIndent   = "  "
+	// outlineTabWidth is the number of spaces a tab stands for.
+	outlineTabWidth = 4
+)
+
+// outlineItem matches a list item with an optional task checkbox, such as
+// "- [x] Ship it". Its groups are the indentation, the box and the text.
+var outlineItem = regexp.MustCompile(`^([ \t]*)[-*+][ \t]+(?:\[([ xX])\][ \t]+)?(.*)$`)
+
+// outlineNode is a task of an outline and its children in order.
+type outlineNode struct {
+	task     *proto.Task
+	children []*outlineNode
+}
+
+// exportMarkdown renders the task and its subtree as a nested Markdown
+// checklist. Tasks in a final status are checked and siblings keep their
+// creation order.
+func (h *TaskHandler) exportMarkdown(c *gin.Context) {
+	ctx := context.Background()
+	task, err := h.grpcClient.GetTask(ctx, &proto.GetTaskRequest{Id: c.Param("id")})
+	if err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusNotFound, gin.H{"error": s})
+		return
+	}
+	root, err := h.loadOutline(ctx, task)
+	if err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": s})
+		return
+	}
+
+	var b strings.Builder
+	h.writeOutline(&b, root, 0)
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="task-%s.md"`, task.Id))
+	c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(b.String()))
+}
+
+// loadOutline reads the subtree of task breadth first, failing when it
+// holds more than maxOutlineTasks tasks.
+func (h *TaskHandler) loadOutline(ctx context.Context, task *proto.Task) (*outlineNode, error) {
+	root := &outlineNode{task: task}
+	queue := []*outlineNode{root}
+	for count := 1; len(queue) > 0; queue = queue[1:] {
+		node := queue[0]
+		req := &proto.ListTasksRequest{ParentId: node.task.Id, PageSize: exportPageSize}
+		for req.Page = 0; ; req.Page++ {
+			res, err := h.grpcClient.ListTasks(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			for _, child := range res.Tasks {
+				if count++; count > maxOutlineTasks {
+					return nil, status.Errorf(codes.ResourceExhausted, "the subtree holds more than %d tasks", maxOutlineTasks)
+				}
+				node.children = append(node.children, &outlineNode{task: child})
+			}
+			if len(res.Tasks) < exportPageSize {
+				break
+			}
+		}
+		sort.SliceStable(node.children, func(i, j int) bool {
+			return node.children[i].task.GetCreatedAt().AsTime().Before(node.children[j].task.GetCreatedAt().AsTime())
+		})
+		queue = append(queue, node.children...)
+	}
+	return root, nil
+}
+
+func (h *TaskHandler) writeOutline(w io.StringWriter, node *outlineNode, depth int) {
+	box := "[ ]"
+	if h.workflow.IsFinal(node.task.Status) {
+		box = "[x]"
+	}
+	title := strings.Join(strings.Fields(node.task.Title), " ")
+	_, _ = w.WriteString(strings.Repeat(outlineIndent, depth) + "- " + box + " " + title + "\n")
+	for _, child := range node.children {
+		h.writeOutline(w, child, depth+1)
+	}
+}
+
+// outlineEntry is a parsed outline item. parent is the index of the item it
+// is nested under, or -1 for top-level items.
+type outlineEntry struct {
+	line   int
+	title  string
+	done   bool
+	parent int
+}
+
+type outlineResult struct {
+	ID       string `json:"id"`
+	ParentID string `json:"parent_id"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+	Line     int    `json:"line"`
+}
+
+// importMarkdown creates the items of a Markdown outline as a new subtree
+// under the task named by the parent_id query parameter. Items are created
+// in document order so that siblings keep their order; checked items are
+// created in the first configured final status, and rejected when the
+// workflow has none. Blank lines and headings are skipped, any other text
+// is rejected.
+func (h *TaskHandler) importMarkdown(c *gin.Context) {
+	parentID := c.Query("parent_id")
+	if parentID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "parent_id is required"})
+		return
+	}
+	entries, err := parseOutline(http.MaxBytesReader(c.Writer, c.Request.Body, maxOutlineSize))
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("outlines must not exceed %d bytes", maxOutlineSize)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err := h.grpcClient.GetTask(context.Background(), &proto.GetTaskRequest{Id: parentID}); err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusNotFound, gin.H{"error": s})
+		return
+	}
+
+	user := c.GetHeader(auth.UserHeader)
+	reqs := make([]*proto.CreateTaskRequest, len(entries))
+	for i, entry := range entries {
+		in := &createTaskRequest{taskInput: taskInput{Title: entry.title}}
+		if entry.done {
+			completed, ok := h.workflow.CompletedStatus()
+			if !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("line %d: the workflow has no final status for checked items", entry.line)})
+				return
+			}
+			in.Status = completed
+		}
+		if reqs[i], err = h.createRequest(user, in); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("line %d: %v", entry.line, err)})
+			return
+		}
+	}
+
+	ctx := requestContext(c)
+	results := make([]*outlineResult, 0, len(entries))
+	for i, entry := range entries {
+		req := reqs[i]
+		req.ParentId = parentID
+		if entry.parent >= 0 {
+			req.ParentId = results[entry.parent].ID
+		}
+		res, err := h.grpcClient.CreateTask(ctx, req)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   fmt.Sprintf("line %d: %v", entry.line, err),
+				"results": results,
+			})
+			return
+		}
+		results = append(results, &outlineResult{
+			ID:       res.Id,
+			ParentID: req.ParentId,
+			Title:    req.Title,
+			Status:   req.Status,
+			Line:     entry.line,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
+
+// parseOutline reads the list items of a Markdown outline. An item is nested
+// under the closest preceding item that is indented less; tabs count as
+// outlineTabWidth spaces.
+func parseOutline(r io.Reader) ([]outlineEntry, error) {
+	type level struct{ indent, entry int }
+	var entries []outlineEntry
+	var stack []level
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxOutlineSize)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), " \t\r")
+		if trimmed := strings.TrimSpace(text); trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		m := outlineItem.FindStringSubmatch(text)
+		if m == nil {
+			return nil, fmt.Errorf("line %d is not a list item", line)
+		}
+		title := strings.TrimSpace(m[3])
+		if title == "" {
+			return nil, fmt.Errorf("line %d has no text", line)
+		}
+		if len(entries) == maxOutlineTasks {
+			return nil, fmt.Errorf("outlines must not exceed %d items", maxOutlineTasks)
+		}
+
+		indent := len(strings.ReplaceAll(m[1], "\t", strings.Repeat(" ", outlineTabWidth)))
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		parent := -1
+		if len(stack) > 0 {
+			parent = stack[len(stack)-1].entry
+		}
+		stack = append(stack, level{indent: indent, entry: len(entries)})
+		entries = append(entries, outlineEntry{
+			line:   line,
+			title:  title,
+			done:   m[2] == "x" || m[2] == "X",
+			parent: parent,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("the outline has no list items")
+	}
+	return entries, nil
+}
@@ -1,11 +1,89 @@
 package handlers
 
+import "github.com/bhupeshpandey/task-manager-nashville/internal/events"
+
 type healthzGetResponse struct {
 	ServiceName string `json:"serviceName,omitempty"`
 	Version     string `json:"version,omitempty"`
 	Status      string `json:"status,omitempty"`
 }
 
-type response struct {
-	Message string
+// subscriptionMessage is sent by WebSocket clients to change their topics.
+// The graphql action starts the GraphQL subscription in Query under ID and
+// the stop action ends it.
+type subscriptionMessage struct {
+	Action        string                 `json:"action"`
+	Topic         string                 `json:"topic"`
+	ID            string                 `json:"id"`
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// graphqlMessage carries a result of a GraphQL subscription, or tells that
+// the subscription is complete.
+type graphqlMessage struct {
+	Type    string      `json:"type"`
+	ID      string      `json:"id"`
+	Payload interface{} `json:"payload,omitempty"`
+}
+
+// eventMessage is the WebSocket representation of a task event.
+type eventMessage struct {
+	Type     events.Type   `json:"type"`
+	TaskID   string        `json:"task_id"`
+	Actor    string        `json:"actor,omitempty"`
+	Time     string        `json:"time"`
+	Task     *taskView     `json:"task,omitempty"`
+	Previous *taskView     `json:"previous,omitempty"`
+	Comment  *commentView  `json:"comment,omitempty"`
+	Reminder *reminderView `json:"reminder,omitempty"`
+}
+
+// taskInput holds the writable task fields shared by create and update
+// requests. DueAt is RFC 3339 or a date-only value in Timezone, which is also
+// the time zone RRule is expanded in.
+type taskInput struct {
+	Title          string `json:"title"`
+	Description    string `json:"description"`
+	Status         string `json:"status"`
+	DueAt          string `json:"due_at"`
+	Timezone       string `json:"timezone"`
+	Priority       string `json:"priority"`
+	Assignee       string `json:"assignee"`
+	Reporter       string `json:"reporter"`
+	RRule          string `json:"rrule"`
+	RecurrenceMode string `json:"recurrence_mode"`
+}
+
+type createTaskRequest struct {
+	taskInput
+	ParentID   string `json:"parent_id"`
+	ExternalID string `json:"external_id"`
+}
+
+// taskView is the REST representation of a task. Timestamps are rendered as
+// RFC 3339 in the time zone requested by the caller.
+type taskView struct {
+	ID          string            `json:"id"`
+	ParentID    string            `json:"parent_id,omitempty"`
+	Title       string            `json:"title"`
+	Description string            `json:"description,omitempty"`
+	Status      string            `json:"status,omitempty"`
+	Priority    string            `json:"priority,omitempty"`
+	DueAt       string            `json:"due_at,omitempty"`
+	Assignee    string            `json:"assignee,omitempty"`
+	Reporter    string            `json:"reporter,omitempty"`
+	Labels      []string          `json:"labels,omitempty"`
+	Attachments []*attachmentView `json:"attachments,omitempty"`
+	BlockedBy   []string          `json:"blocked_by,omitempty"`
+	// Blocked is set when a task in BlockedBy is not finished yet.
+	Blocked        bool   `json:"blocked"`
+	RRule          string `json:"rrule,omitempty"`
+	Timezone       string `json:"timezone,omitempty"`
+	RecurrenceMode string `json:"recurrence_mode,omitempty"`
+	SeriesID       string `json:"series_id,omitempty"`
+	ExternalID     string `json:"external_id,omitempty"`
+	CreatedAt      string `json:"created_at,omitempty"`
+	UpdatedAt      string `json:"updated_at,omitempty"`
 }
@@ -0,0 +1,387 @@
+package handlers
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/auth"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/openapi"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed docs.html
+var docsPage []byte
+
+// route is a method and path registered by AddServiceRoutes.
+type route struct {
+	method string
+	path   string
+}
+
+func (r route) String() string {
+	return r.method + " " + r.path
+}
+
+// routeDoc documents a route for the OpenAPI document. Bodies and responses
+// are JSON unless their content types are listed in rawBody and rawResponse.
+type routeDoc struct {
+	id      string
+	summary string
+	// description explains the behavior a summary cannot.
+	description string
+	tag         string
+	// path replaces the registered path, for routes whose gin pattern is not
+	// the path clients call.
+	path  string
+	query []*openapi.Parameter
+	body  interface{}
+	// rawBody maps content types to the schemas of non-JSON bodies.
+	rawBody  map[string]*openapi.Schema
+	response interface{}
+	// list wraps response in {"results": [...]}.
+	list        bool
+	rawResponse map[string]*openapi.Schema
+	// status is the status of a successful response, 200 by default.
+	status int
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+type labelUpdateResponse struct {
+	Label      *labelView `json:"label"`
+	Relabelled int        `json:"relabelled"`
+}
+
+type labelDeleteResponse struct {
+	Success    bool `json:"success"`
+	Relabelled int  `json:"relabelled"`
+}
+
+type skipResponse struct {
+	ID      string `json:"id"`
+	Skipped string `json:"skipped"`
+	DueAt   string `json:"due_at"`
+}
+
+type githubResponse struct {
+	Result     string `json:"result"`
+	TaskID     string `json:"task_id,omitempty"`
+	ExternalID string `json:"external_id,omitempty"`
+}
+
+var (
+	stringSchema = &openapi.Schema{Type: "string"}
+	binarySchema = &openapi.Schema{Type: "string", Format: "binary"}
+	anySchema    = &openapi.Schema{}
+)
+
+func queryParam(name, description string) *openapi.Parameter {
+	return &openapi.Parameter{Name: name, In: "query", Description: description, Schema: stringSchema}
+}
+
+func requiredQueryParam(name, description string) *openapi.Parameter {
+	p := queryParam(name, description)
+	p.Required = true
+	return p
+}
+
+func intQueryParam(name, description string) *openapi.Parameter {
+	return &openapi.Parameter{Name: name, In: "query", Description: description, Schema: &openapi.Schema{Type: "integer"}}
+}
+
+var (
+	tzParam          = queryParam("tz", "IANA time zone timestamps are rendered in. Defaults to UTC.")
+	pageParams       = []*openapi.Parameter{intQueryParam("page", "Zero based page number."), intQueryParam("pageSize", "Results per page.")}
+	taskFilterParams = append(pageParams[:len(pageParams):len(pageParams)],
+		queryParam("startTime", "Only tasks created at or after this RFC 3339 time."),
+		queryParam("endTime", "Only tasks created at or before this RFC 3339 time."),
+		queryParam("status", "Comma separated statuses."),
+		tzParam,
+		queryParam("assignee", "Only tasks assigned to this user."),
+		queryParam("reporter", "Only tasks reported by this user."),
+		queryParam("parentId", "Only the children of this task."),
+		queryParam("labels", "Comma separated labels."),
+		queryParam("labelMode", "any (default) or all of labels."),
+		queryParam("dueAfter", "Only tasks due at or after this time or date."),
+		queryParam("dueBefore", "Only tasks due at or before this time or date."),
+		queryParam("sort", "priority or due."),
+	)
+)
+
+// withParams returns base followed by extra without modifying base.
+func withParams(base []*openapi.Parameter, extra ...*openapi.Parameter) []*openapi.Parameter {
+	return append(base[:len(base):len(base)], extra...)
+}
+
+// routeDocs documents every route of AddServiceRoutes, keyed by method and
+// gin path. buildOpenAPI fails when a route is missing.
+var routeDocs = map[string]*routeDoc{
+	"GET /healthz": {id: "getHealthz", summary: "Report the service health", tag: "service", response: healthzGetResponse{}},
+	"GET /openapi.json": {id: "getOpenAPI", summary: "This OpenAPI document", tag: "service",
+		rawResponse: map[string]*openapi.Schema{"application/json": anySchema}},
+	"GET /docs": {id: "getDocs", summary: "Interactive API documentation", tag: "service",
+		rawResponse: map[string]*openapi.Schema{"text/html": stringSchema}},
+
+	"POST /task":                {id: "createTask", summary: "Create a task", tag: "tasks", body: createTaskRequest{}, response: proto.CreateTaskResponse{}},
+	"GET /task/:id":             {id: "getTask", summary: "Get a task", tag: "tasks", query: []*openapi.Parameter{tzParam}, response: taskView{}},
+	"PUT /task/:id":             {id: "updateTask", summary: "Update a task; title and description are always written", tag: "tasks", body: taskInput{}, response: proto.UpdateTaskResponse{}},
+	"PATCH /task/:id":           {id: "patchTask", summary: "Update the fields present in the body", tag: "tasks", body: taskInput{}, response: proto.UpdateTaskResponse{}},
+	"DELETE /task/:id":          {id: "deleteTask", summary: "Delete a task", tag: "tasks", response: proto.DeleteTaskResponse{}},
+	"POST /task/:id/transition": {id: "transitionTask", summary: "Move a task to another status", tag: "tasks", body: transitionRequest{}, response: transitionResponse{}},
+	"POST /task/:id/move":       {id: "moveTask", summary: "Move a task under another parent", tag: "tasks", body: moveRequest{}, response: proto.UpdateTaskResponse{}},
+	"GET /task/:id/history":     {id: "taskHistory", summary: "List the changes made to a task", tag: "tasks", query: withParams(pageParams, tzParam), response: activityView{}, list: true},
+	"GET /task/:id/markdown": {id: "exportMarkdown", summary: "Export a task and its subtasks as a Markdown outline", tag: "tasks",
+		rawResponse: map[string]*openapi.Schema{"text/markdown": stringSchema}},
+	"PUT /task/:id/assignee":    {id: "assignTask", summary: "Assign a task", tag: "tasks", body: assignRequest{}, response: proto.UpdateTaskResponse{}},
+	"DELETE /task/:id/assignee": {id: "unassignTask", summary: "Unassign a task", tag: "tasks", response: proto.UpdateTaskResponse{}},
+
+	"POST /task/:id/labels":          {id: "attachLabels", summary: "Attach labels to a task", tag: "labels", body: taskLabelsRequest{}, response: proto.TaskLabelsResponse{}},
+	"DELETE /task/:id/labels/:label": {id: "detachLabel", summary: "Detach a label from a task", tag: "labels", response: proto.TaskLabelsResponse{}},
+
+	"POST /task/:id/comments":                   {id: "createComment", summary: "Comment on a task", tag: "comments", body: commentRequest{}, response: commentView{}},
+	"GET /task/:id/comments":                    {id: "listComments", summary: "List the top level comments of a task", tag: "comments", query: withParams(pageParams, tzParam), response: commentView{}, list: true},
+	"GET /task/:id/comments/:commentId/replies": {id: "listReplies", summary: "List the replies to a comment", tag: "comments", query: withParams(pageParams, tzParam), response: commentView{}, list: true},
+	"PUT /task/:id/comments/:commentId":         {id: "updateComment", summary: "Edit a comment", tag: "comments", body: commentRequest{}, response: commentView{}},
+	"DELETE /task/:id/comments/:commentId":      {id: "deleteComment", summary: "Delete a comment", tag: "comments", response: proto.DeleteCommentResponse{}},
+
+	"GET /task/:id/recurrence": {id: "previewRecurrence", summary: "Preview the next occurrences of a recurring task", tag: "recurrence",
+		query: []*openapi.Parameter{intQueryParam("count", "Number of occurrences."), tzParam}, response: recurrenceView{}},
+	"POST /task/:id/recurrence/skip": {id: "skipOccurrence", summary: "Skip the current occurrence", tag: "recurrence", response: skipResponse{}},
+	"POST /task/:id/recurrence/stop": {id: "stopRecurrence", summary: "End the series", tag: "recurrence", response: proto.UpdateTaskResponse{}},
+
+	"POST /task/:id/dependencies":              {id: "addDependency", summary: "Block a task by another", tag: "dependencies", body: dependencyRequest{}, response: proto.TaskDependenciesResponse{}},
+	"GET /task/:id/dependencies":               {id: "listDependencies", summary: "List the blockers and dependents of a task", tag: "dependencies", query: []*openapi.Parameter{tzParam}, response: dependenciesView{}},
+	"DELETE /task/:id/dependencies/:blockerId": {id: "removeDependency", summary: "Remove a blocker", tag: "dependencies", response: proto.TaskDependenciesResponse{}},
+
+	"POST /task/:id/reminders":               {id: "createReminder", summary: "Schedule a reminder", tag: "reminders", body: reminderRequest{}, response: reminderView{}},
+	"GET /task/:id/reminders":                {id: "listReminders", summary: "List the reminders of a task", tag: "reminders", query: pageParams, response: reminderView{}, list: true},
+	"DELETE /task/:id/reminders/:reminderId": {id: "deleteReminder", summary: "Cancel a reminder", tag: "reminders", response: proto.DeleteReminderResponse{}},
+
+	"POST /task/:id/attachments": {id: "uploadAttachment", summary: "Upload an attachment", tag: "attachments",
+		rawBody: map[string]*openapi.Schema{"multipart/form-data": {
+			Type:       "object",
+			Properties: map[string]*openapi.Schema{"file": binarySchema},
+			Required:   []string{"file"},
+		}},
+		response: attachmentView{}},
+	"GET /task/:id/attachments": {id: "listAttachments", summary: "List the attachments of a task", tag: "attachments", response: attachmentView{}, list: true},
+	"GET /task/:id/attachments/:attachmentId": {id: "downloadAttachment", summary: "Download an attachment", tag: "attachments",
+		rawResponse: map[string]*openapi.Schema{"application/octet-stream": binarySchema}},
+	"DELETE /task/:id/attachments/:attachmentId": {id: "deleteAttachment", summary: "Delete an attachment", tag: "attachments", response: proto.DeleteAttachmentResponse{}},
+
+	"GET /tasks": {id: "listTasks", summary: "List tasks", tag: "tasks", query: taskFilterParams, response: taskView{}, list: true},
+	"GET /tasks/search": {id: "searchTasks", summary: "Search tasks", tag: "tasks",
+		query: withParams(pageParams, requiredQueryParam("q", "Search terms, such as title:report status:todo."), tzParam), response: searchResult{}, list: true},
+	"GET /tasks/overdue": {id: "listOverdueTasks", summary: "List unfinished tasks past their due date", tag: "tasks", query: taskFilterParams, response: taskView{}, list: true},
+	"GET /tasks/due": {id: "listDueTasks", summary: "List the tasks due in a time range", tag: "tasks",
+		query: withParams(taskFilterParams, queryParam("from", "Start of the range. Defaults to now."), requiredQueryParam("to", "End of the range.")), response: taskView{}, list: true},
+	"GET /tasks/order": {id: "orderTasks", summary: "List tasks in dependency order", tag: "dependencies",
+		query: withParams(taskFilterParams, queryParam("ids", "Comma separated task IDs, instead of the filters.")), response: taskView{}, list: true},
+	"GET /tasks/export": {id: "exportTasks", summary: "Export tasks", tag: "import/export",
+		query: withParams(taskFilterParams, queryParam("format", "csv (default), json or ndjson."), queryParam("columns", "Comma separated columns.")),
+		rawResponse: map[string]*openapi.Schema{
+			"text/csv":             stringSchema,
+			"application/json":     {Type: "array", Items: &openapi.Schema{Type: "object"}},
+			"application/x-ndjson": stringSchema,
+		}},
+	"POST /tasks/import": {id: "importTasks", summary: "Import tasks from CSV or JSON", tag: "import/export",
+		query: []*openapi.Parameter{
+			queryParam("format", "csv or json. Defaults to the Content-Type."),
+			&openapi.Parameter{Name: "dry_run", In: "query", Description: "Validate without creating tasks.", Schema: &openapi.Schema{Type: "boolean"}},
+			queryParam("mapping", "Comma separated field:column pairs."),
+		},
+		rawBody: map[string]*openapi.Schema{
+			"text/csv":         stringSchema,
+			"application/json": {Type: "array", Items: &openapi.Schema{Type: "object"}},
+		},
+		response: importReport{}},
+	"POST /tasks/import/markdown": {id: "importMarkdown", summary: "Create a subtree from a Markdown outline", tag: "import/export",
+		query:   []*openapi.Parameter{requiredQueryParam("parent_id", "Task the outline is created under.")},
+		rawBody: map[string]*openapi.Schema{"text/markdown": stringSchema}, response: outlineResult{}, list: true},
+	"GET /tasks.ics": {id: "taskFeed", summary: "Subscribe to tasks as an iCalendar feed", tag: "feeds",
+		query:       []*openapi.Parameter{requiredQueryParam("token", "Feed token.")},
+		rawResponse: map[string]*openapi.Schema{"text/calendar": stringSchema}},
+	"POST /tasks:action": {id: "batchTasks", summary: "Apply several operations in one request", tag: "tasks", path: "/tasks:batch",
+		description: "An atomic batch applies its operations one by one and undoes the applied ones when one fails. " +
+			"It may delete at most one task, which is deleted last: a deleted task cannot be restored, " +
+			"so a rolled-back batch never includes a delete.",
+		body: batchRequest{}, response: batchResponse{}},
+
+	"GET /users/me/tasks": {id: "listMyTasks", summary: "List the tasks of the caller", tag: "users", query: taskFilterParams, response: taskView{}, list: true},
+	"GET /users/:id/tasks": {id: "listUserTasks", summary: "List the tasks of a user", tag: "users",
+		query: withParams(taskFilterParams, queryParam("role", "assignee (default) or reporter.")), response: taskView{}, list: true},
+
+	"POST /label":         {id: "createLabel", summary: "Create a label", tag: "labels", body: labelRequest{}, response: labelView{}},
+	"GET /label/:name":    {id: "getLabel", summary: "Get a label", tag: "labels", response: labelView{}},
+	"PUT /label/:name":    {id: "updateLabel", summary: "Update or rename a label", tag: "labels", body: labelRequest{}, response: labelUpdateResponse{}},
+	"DELETE /label/:name": {id: "deleteLabel", summary: "Delete a label and detach it from its tasks", tag: "labels", response: labelDeleteResponse{}},
+	"GET /labels":         {id: "listLabels", summary: "List labels", tag: "labels", query: pageParams, response: labelView{}, list: true},
+
+	"POST /feeds":       {id: "createFeed", summary: "Create an iCalendar feed token", tag: "feeds", body: feedRequest{}, response: feedView{}},
+	"GET /feeds":        {id: "listFeeds", summary: "List the feed tokens of the caller", tag: "feeds", response: feedView{}, list: true},
+	"DELETE /feeds/:id": {id: "deleteFeed", summary: "Revoke a feed token", tag: "feeds", response: proto.DeleteFeedTokenResponse{}},
+
+	"POST /graphql": {id: "postGraphQL", summary: "Run a GraphQL query or mutation", tag: "graphql", body: graphqlRequest{}, rawResponse: map[string]*openapi.Schema{"application/json": anySchema}},
+	"GET /graphql": {id: "getGraphQL", summary: "Run a GraphQL query", tag: "graphql",
+		query:       []*openapi.Parameter{requiredQueryParam("query", "GraphQL document."), queryParam("operationName", "Operation to run.")},
+		rawResponse: map[string]*openapi.Schema{"application/json": anySchema}},
+	"GET /task/ws": {id: "subscribe", summary: "Subscribe to task events over a WebSocket", tag: "events",
+		query:  []*openapi.Parameter{queryParam("topics", "Comma separated topics to subscribe to initially.")},
+		status: http.StatusSwitchingProtocols},
+
+	"GET /audit": {id: "auditLog", summary: "Read the audit log (admins only)", tag: "audit",
+		query:    withParams(pageParams, queryParam("actor", "Only changes made by this user."), queryParam("from", "Start of the range."), queryParam("to", "End of the range."), tzParam),
+		response: activityView{}, list: true},
+
+	"POST /webhooks":             {id: "createWebhook", summary: "Register a webhook (admins only)", tag: "webhooks", body: webhookRequest{}, response: webhookView{}},
+	"GET /webhooks":              {id: "listWebhooks", summary: "List webhooks (admins only)", tag: "webhooks", query: []*openapi.Parameter{tzParam}, response: webhookView{}, list: true},
+	"GET /webhooks/dead-letters": {id: "listDeadLetters", summary: "List the deliveries that ran out of attempts (admins only)", tag: "webhooks", query: withParams(pageParams, tzParam), response: deliveryView{}, list: true},
+	"GET /webhooks/:id":          {id: "getWebhook", summary: "Get a webhook (admins only)", tag: "webhooks", query: []*openapi.Parameter{tzParam}, response: webhookView{}},
+	"PUT /webhooks/:id":          {id: "updateWebhook", summary: "Update a webhook (admins only)", tag: "webhooks", body: webhookRequest{}, response: webhookView{}},
+	"DELETE /webhooks/:id":       {id: "deleteWebhook", summary: "Delete a webhook (admins only)", tag: "webhooks", response: proto.DeleteWebhookResponse{}},
+	"GET /webhooks/:id/deliveries": {id: "listDeliveries", summary: "List the deliveries of a webhook (admins only)", tag: "webhooks",
+		query: withParams(pageParams, queryParam("status", "Comma separated delivery statuses."), tzParam), response: deliveryView{}, list: true},
+	"POST /webhooks/:id/deliveries/:deliveryId/redeliver": {id: "redeliver", summary: "Send a delivery again (admins only)", tag: "webhooks", response: deliveryView{}},
+
+	"POST /integrations/github": {id: "receiveGitHub", summary: "Receive GitHub issue and pull request events", tag: "integrations",
+		rawBody: map[string]*openapi.Schema{"application/json": anySchema}, response: githubResponse{}},
+}
+
+// buildOpenAPI documents routes, which are served under basePath. It fails
+// when a route has no entry in routeDocs or an entry has no route, so that
+// the document cannot fall behind AddServiceRoutes.
+func buildOpenAPI(basePath string, routes []route) (*openapi.Document, error) {
+	b := openapi.NewBuilder(openapi.Info{
+		Title:   "Nashville task service",
+		Version: version,
+	}, basePath)
+	b.Secure("user", auth.UserHeader, "ID of the calling user.")
+	errorSchema := b.Schema(errorResponse{})
+
+	var undocumented []string
+	registered := make(map[string]bool, len(routes))
+	for _, r := range routes {
+		registered[r.String()] = true
+		doc, ok := routeDocs[r.String()]
+		if !ok {
+			undocumented = append(undocumented, r.String())
+			continue
+		}
+		path := r.path
+		if doc.path != "" {
+			path = doc.path
+		}
+		b.Add(r.method, openAPIPath(path), doc.operation(b, path, errorSchema))
+	}
+	var stale []string
+	for key := range routeDocs {
+		if !registered[key] {
+			stale = append(stale, key)
+		}
+	}
+	if len(undocumented) > 0 || len(stale) > 0 {
+		sort.Strings(undocumented)
+		sort.Strings(stale)
+		return nil, fmt.Errorf("openapi: undocumented routes %v, documented routes that are not registered %v", undocumented, stale)
+	}
+	return b.Document(), nil
+}
+
+func (d *routeDoc) operation(b *openapi.Builder, path string, errorSchema *openapi.Schema) *openapi.Operation {
+	op := &openapi.Operation{
+		OperationID: d.id,
+		Summary:     d.summary,
+		Description: d.description,
+		Tags:        []string{d.tag},
+		Responses: map[string]*openapi.Response{
+			"default": {Description: "Error", Content: map[string]*openapi.MediaType{"application/json": {Schema: errorSchema}}},
+		},
+	}
+	for _, segment := range strings.Split(path, "/") {
+		if name, ok := strings.CutPrefix(segment, ":"); ok {
+			op.Parameters = append(op.Parameters, &openapi.Parameter{Name: name, In: "path", Required: true, Schema: stringSchema})
+		}
+	}
+	op.Parameters = append(op.Parameters, d.query...)
+
+	if d.body != nil || d.rawBody != nil {
+		op.RequestBody = &openapi.RequestBody{Required: true, Content: map[string]*openapi.MediaType{}}
+		if d.body != nil {
+			op.RequestBody.Content["application/json"] = &openapi.MediaType{Schema: b.Schema(d.body)}
+		}
+		for contentType, schema := range d.rawBody {
+			op.RequestBody.Content[contentType] = &openapi.MediaType{Schema: schema}
+		}
+	}
+
+	status := d.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	ok := &openapi.Response{Description: http.StatusText(status)}
+	if d.response != nil {
+		schema := b.Schema(d.response)
+		if d.list {
+			schema = &openapi.Schema{
+				Type:       "object",
+				Properties: map[string]*openapi.Schema{"results": {Type: "array", Items: schema}},
+			}
+		}
+		ok.Content = map[string]*openapi.MediaType{"application/json": {Schema: schema}}
+	}
+	for contentType, schema := range d.rawResponse {
+		if ok.Content == nil {
+			ok.Content = map[string]*openapi.MediaType{}
+		}
+		ok.Content[contentType] = &openapi.MediaType{Schema: schema}
+	}
+	op.Responses[strconv.Itoa(status)] = ok
+	return op
+}
+
+// openAPIPath rewrites the gin parameters of path, such as :id, as {id}.
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if name, ok := strings.CutPrefix(segment, ":"); ok {
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// mustOpenAPI is buildOpenAPI encoded as JSON. It panics when a route is
+// undocumented, like the GraphQL schema does when it does not match its
+// resolvers.
+func mustOpenAPI(basePath string, routes []route) []byte {
+	doc, err := buildOpenAPI(basePath, routes)
+	if err != nil {
+		panic(err)
+	}
+	spec, err := json.Marshal(doc)
+	if err != nil {
+		panic(err)
+	}
+	return spec
+}
+
+func (h *TaskHandler) getOpenAPI(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", h.openapi)
+}
+
+// docsPolicy keeps the docs page to its own inline script and style, and to
+// fetching the document from the service.
+const docsPolicy = "default-src 'none'; script-src 'unsafe-inline'; style-src 'unsafe-inline'; connect-src 'self'"
+
+func (h *TaskHandler) getDocs(c *gin.Context) {
+	c.Header("Content-Security-Policy", docsPolicy)
+	c.Data(http.StatusOK, "text/html; charset=utf-8", docsPage)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/events"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/workflow"
+	"github.com/gin-gonic/gin"
+)
+
+// registeredRoutes runs AddServiceRoutes and returns the routes it
+// registers. The panic of its own coverage check is recovered, so that the
+// test can report what is missing.
+func registeredRoutes(t *testing.T) (string, []route) {
+	t.Helper()
+	stateMachine, err := workflow.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewTaskHandler(nil, stateMachine, events.NewBus(), nil, nil, nil, nil, nil, nil)
+	router := gin.New().Group("/service/v1")
+	var routes []route
+	func() {
+		defer func() { _ = recover() }()
+		h.AddServiceRoutes(router, func(wsRouter *gin.RouterGroup, method string, path string, handler func(c *gin.Context)) {
+			routes = append(routes, route{method: method, path: path})
+		})
+	}()
+	return router.BasePath(), routes
+}
+
+func TestEveryRouteIsDocumented(t *testing.T) {
+	basePath, routes := registeredRoutes(t)
+	if len(routes) == 0 {
+		t.Fatal("AddServiceRoutes registered no routes")
+	}
+	doc, err := buildOpenAPI(basePath, routes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range routes {
+		path := r.path
+		if override := routeDocs[r.String()].path; override != "" {
+			path = override
+		}
+		item, ok := doc.Paths[openAPIPath(path)]
+		if !ok || (*item)[strings.ToLower(r.method)] == nil {
+			t.Errorf("%s has no operation in the document", r)
+		}
+	}
+}
+
+func TestUndocumentedRouteFails(t *testing.T) {
+	basePath, routes := registeredRoutes(t)
+	routes = append(routes, route{method: "GET", path: "/undocumented"})
+	if _, err := buildOpenAPI(basePath, routes); err == nil || !strings.Contains(err.Error(), "GET /undocumented") {
+		t.Fatalf("buildOpenAPI() error = %v, want it to name GET /undocumented", err)
+	}
+}
+
+func TestDocsPageIsSelfContained(t *testing.T) {
+	page := string(docsPage)
+	for _, external := range []string{"<script src", "<link", "http://", "https://"} {
+		if strings.Contains(page, external) {
+			t.Errorf("the docs page contains %q; it must not load anything but openapi.json", external)
+		}
+	}
+	if !strings.Contains(page, `fetch("openapi.json")`) {
+		t.Error("the docs page does not load openapi.json")
+	}
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/recurrence"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/taskinput"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultPreviewCount = 5
+	maxPreviewCount     = 100
+)
+
+var errNotRecurring = errors.New("task is not the current occurrence of a recurring series")
+
+type recurrenceView struct {
+	RRule          string   `json:"rrule"`
+	Timezone       string   `json:"timezone"`
+	RecurrenceMode string   `json:"recurrence_mode"`
+	SeriesID       string   `json:"series_id"`
+	Occurrences    []string `json:"occurrences"`
+}
+
+// previewRecurrence lists the occurrences following the task. They are
+// rendered in the tz query parameter, or in the time zone of the series.
+func (h *TaskHandler) previewRecurrence(c *gin.Context) {
+	count := defaultPreviewCount
+	if s := c.Query("count"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > maxPreviewCount {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("count must be between 1 and %d", maxPreviewCount)})
+			return
+		}
+		count = n
+	}
+	task, rule, ok := h.lookupSeries(c)
+	if !ok {
+		return
+	}
+	tz := c.Query("tz")
+	if tz == "" {
+		tz = task.Timezone
+	}
+	loc, err := taskinput.LoadLocation(tz)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	view := recurrenceView{
+		RRule:          task.Rrule,
+		Timezone:       task.Timezone,
+		RecurrenceMode: task.RecurrenceMode,
+		SeriesID:       task.SeriesId,
+		Occurrences:    []string{},
+	}
+	if view.RecurrenceMode == "" {
+		view.RecurrenceMode = recurrence.ModeOnCompletion
+	}
+	if view.SeriesID == "" {
+		view.SeriesID = task.Id
+	}
+	for _, t := range rule.Upcoming(recurrence.OccurrenceTime(task).AsTime(), count) {
+		view.Occurrences = append(view.Occurrences, t.In(loc).Format(time.RFC3339))
+	}
+	c.JSON(http.StatusOK, view)
+}
+
+// skipOccurrence moves the current occurrence to the next date of its
+// series without creating a task.
+func (h *TaskHandler) skipOccurrence(c *gin.Context) {
+	task, rule, ok := h.lookupSeries(c)
+	if !ok {
+		return
+	}
+	next, ok := rule.Next(recurrence.OccurrenceTime(task).AsTime())
+	if !ok {
+		c.JSON(http.StatusConflict, gin.H{"error": "the series has no further occurrence"})
+		return
+	}
+
+	_, err := h.grpcClient.UpdateTask(requestContext(c), &proto.UpdateTaskRequest{
+		Id:         task.Id,
+		DueAt:      timestamppb.New(next),
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"due_at"}},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"id":      task.Id,
+		"skipped": recurrence.OccurrenceTime(task).AsTime().UTC().Format(time.RFC3339),
+		"due_at":  next.UTC().Format(time.RFC3339),
+	})
+}
+
+// stopRecurrence ends the series. The current occurrence is kept as a plain
+// task.
+func (h *TaskHandler) stopRecurrence(c *gin.Context) {
+	task, _, ok := h.lookupSeries(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.grpcClient.UpdateTask(requestContext(c), &proto.UpdateTaskRequest{
+		Id:         task.Id,
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"rrule", "recurrence_mode"}},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// lookupSeries fetches the task in the path and its rule, responding with
+// 404 or 409 when it does not carry one.
+func (h *TaskHandler) lookupSeries(c *gin.Context) (*proto.Task, *recurrence.Rule, bool) {
+	task, err := h.grpcClient.GetTask(context.Background(), &proto.GetTaskRequest{Id: c.Param("id")})
+	if err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusNotFound, gin.H{"error": s})
+		return nil, nil, false
+	}
+	rule, err := recurrence.RuleFor(task)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, nil, false
+	}
+	if rule == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": errNotRecurring.Error()})
+		return nil, nil, false
+	}
+	return task, rule, true
+}
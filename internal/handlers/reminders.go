@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/auth"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/reminder"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/taskinput"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxReminderOffset is the longest a reminder may fire before the due date.
+const maxReminderOffset = 366 * 24 * time.Hour
+
+// reminderRequest asks for a reminder at RemindAt, which is RFC 3339 or a
+// date in Timezone, or BeforeDue ahead of the due date. BeforeDue is a Go
+// duration such as "90m" or a number of days such as "2d".
+type reminderRequest struct {
+	RemindAt  string   `json:"remind_at"`
+	Timezone  string   `json:"timezone"`
+	BeforeDue string   `json:"before_due"`
+	Channels  []string `json:"channels"`
+	Recipient string   `json:"recipient"`
+}
+
+type reminderView struct {
+	ID        string   `json:"id"`
+	TaskID    string   `json:"task_id"`
+	RemindAt  string   `json:"remind_at,omitempty"`
+	BeforeDue string   `json:"before_due,omitempty"`
+	Channels  []string `json:"channels"`
+	Recipient string   `json:"recipient"`
+	CreatedBy string   `json:"created_by,omitempty"`
+	CreatedAt string   `json:"created_at,omitempty"`
+	FireAt    string   `json:"fire_at,omitempty"`
+	FiredAt   string   `json:"fired_at,omitempty"`
+}
+
+func newReminderView(r *proto.Reminder, loc *time.Location) *reminderView {
+	view := &reminderView{
+		ID:        r.Id,
+		TaskID:    r.TaskId,
+		RemindAt:  formatTimestamp(r.RemindAt, loc),
+		Channels:  r.Channels,
+		Recipient: r.Recipient,
+		CreatedBy: r.CreatedBy,
+		CreatedAt: formatTimestamp(r.CreatedAt, loc),
+		FireAt:    formatTimestamp(r.FireAt, loc),
+		FiredAt:   formatTimestamp(r.FiredAt, loc),
+	}
+	if r.RemindAt == nil {
+		view.BeforeDue = (time.Duration(r.OffsetSeconds) * time.Second).String()
+	}
+	return view
+}
+
+// createReminder adds a reminder to the task. It goes to the assignee, or to
+// the calling user when the task is unassigned, unless recipient is given.
+func (h *TaskHandler) createReminder(c *gin.Context) {
+	var req reminderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	task, err := h.grpcClient.GetTask(context.Background(), &proto.GetTaskRequest{Id: c.Param("id")})
+	if err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusNotFound, gin.H{"error": s})
+		return
+	}
+
+	user := c.GetHeader(auth.UserHeader)
+	r, err := h.reminderFromRequest(&req, task, user)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	created, err := h.grpcClient.CreateReminder(requestContext(c), &proto.CreateReminderRequest{Reminder: r})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.reminders.Wake()
+	c.JSON(http.StatusOK, newReminderView(created, time.UTC))
+}
+
+func (h *TaskHandler) reminderFromRequest(req *reminderRequest, task *proto.Task, user string) (*proto.Reminder, error) {
+	r := &proto.Reminder{
+		TaskId:    task.Id,
+		Recipient: req.Recipient,
+		CreatedBy: user,
+	}
+	switch {
+	case req.RemindAt != "" && req.BeforeDue != "":
+		return nil, errors.New("remind_at and before_due cannot be combined")
+	case req.RemindAt != "":
+		loc, err := taskinput.LoadLocation(req.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		t, err := taskinput.ParseTime(req.RemindAt, loc, false)
+		if err != nil {
+			return nil, fmt.Errorf("invalid remind_at: %w", err)
+		}
+		if !t.After(time.Now()) {
+			return nil, errors.New("remind_at must be in the future")
+		}
+		r.RemindAt = timestamppb.New(t)
+	case req.BeforeDue != "":
+		if task.DueAt == nil {
+			return nil, errors.New("before_due needs a task with a due date")
+		}
+		offset, err := parseReminderOffset(req.BeforeDue)
+		if err != nil {
+			return nil, err
+		}
+		r.OffsetSeconds = int64(offset / time.Second)
+	default:
+		return nil, errors.New("either remind_at or before_due is required")
+	}
+
+	if err := h.addressReminder(r, task, user, req.Channels); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// addressReminder defaults the recipient of r to the assignee of the task,
+// then to user, and sets its channels, checking that they are available.
+func (h *TaskHandler) addressReminder(r *proto.Reminder, task *proto.Task, user string, channels []string) error {
+	if r.Recipient == "" {
+		r.Recipient = task.Assignee
+	}
+	if r.Recipient == "" {
+		r.Recipient = user
+	}
+	if r.Recipient == "" {
+		return errors.New("recipient is required for unassigned tasks")
+	}
+
+	if len(channels) == 0 && h.reminders.HasChannel(reminder.ChannelWebSocket) {
+		channels = []string{reminder.ChannelWebSocket}
+	}
+	if len(channels) == 0 {
+		return errors.New("channels is required")
+	}
+	r.Channels = nil
+	for _, channel := range channels {
+		if !h.reminders.HasChannel(channel) {
+			return fmt.Errorf("channel %q is not available, use one of %s", channel, strings.Join(h.reminders.Channels(), ", "))
+		}
+		if !slices.Contains(r.Channels, channel) {
+			r.Channels = append(r.Channels, channel)
+		}
+	}
+	return nil
+}
+
+// parseReminderOffset accepts Go durations and whole days ("2d").
+func parseReminderOffset(value string) (time.Duration, error) {
+	var offset time.Duration
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid before_due %q", value)
+		}
+		offset = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if offset, err = time.ParseDuration(value); err != nil {
+			return 0, fmt.Errorf("invalid before_due %q", value)
+		}
+	}
+	if offset < 0 || offset > maxReminderOffset {
+		return 0, fmt.Errorf("before_due must be between 0 and %s", maxReminderOffset)
+	}
+	return offset, nil
+}
+
+func (h *TaskHandler) listReminders(c *gin.Context) {
+	page, pageSize := parsePagination(c.Request.URL.Query())
+	res, err := h.grpcClient.ListReminders(context.Background(), &proto.ListRemindersRequest{
+		TaskId:   c.Param("id"),
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	})
+	if err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": s})
+		return
+	}
+	var resp struct {
+		Results interface{} `json:"results"`
+	}
+
+	views := make([]*reminderView, 0, len(res.Reminders))
+	for _, r := range res.Reminders {
+		views = append(views, newReminderView(r, time.UTC))
+	}
+	resp.Results = views
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *TaskHandler) deleteReminder(c *gin.Context) {
+	r, err := h.grpcClient.GetReminder(context.Background(), &proto.GetReminderRequest{Id: c.Param("reminderId")})
+	if err != nil || r.TaskId != c.Param("id") {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reminder not found"})
+		return
+	}
+	resp, err := h.grpcClient.DeleteReminder(requestContext(c), &proto.DeleteReminderRequest{Id: r.Id})
+	if err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": s})
+		return
+	}
+
+	h.reminders.Wake()
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"html"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/taskinput"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	maxSearchTerms = 16
+	// snippetLength is the number of bytes of description shown around the
+	// first match.
+	snippetLength  = 160
+	highlightOpen  = "<mark>"
+	highlightClose = "</mark>"
+)
+
+type searchResult struct {
+	Task       *taskView        `json:"task"`
+	Score      float64          `json:"score"`
+	Highlights searchHighlights `json:"highlights"`
+}
+
+type searchHighlights struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+func (h *TaskHandler) searchTasks(c *gin.Context) {
+	vars := c.Request.URL.Query()
+	loc, err := taskinput.LoadLocation(vars.Get("tz"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	query := vars.Get("q")
+	terms, err := parseSearchQuery(query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	page, pageSize := parsePagination(vars)
+
+	req := &proto.SearchTasksRequest{
+		Query:    query,
+		Terms:    terms,
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	}
+	res, err := h.grpcClient.SearchTasks(context.Background(), req)
+	if err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": s})
+		return
+	}
+
+	matcher := newTermMatcher(terms)
+	cache := newTaskCache(context.Background(), h.grpcClient)
+	results := make([]searchResult, 0, len(res.Hits))
+	for _, hit := range res.Hits {
+		cache.tasks[hit.Task.Id] = hit.Task
+		view := newTaskView(hit.Task, loc)
+		h.markBlocked(cache, []*taskView{view})
+		results = append(results, searchResult{
+			Task:  view,
+			Score: hit.Score,
+			Highlights: searchHighlights{
+				Title:       matcher.highlight(hit.Task.GetTitle(), 0, len(hit.Task.GetTitle())),
+				Description: matcher.snippet(hit.Task.GetDescription()),
+			},
+		})
+	}
+	var resp struct {
+		Results interface{} `json:"results"`
+	}
+
+	resp.Results = results
+	c.JSON(http.StatusOK, resp)
+}
+
+// parseSearchQuery splits q into lower-cased terms. Double-quoted text is a
+// phrase and a trailing '*' turns a word into a prefix.
+func parseSearchQuery(q string) ([]*proto.SearchTerm, error) {
+	var terms []*proto.SearchTerm
+	rest := strings.TrimSpace(q)
+	for rest != "" {
+		var term *proto.SearchTerm
+		if rest[0] == '"' {
+			end := strings.IndexByte(rest[1:], '"')
+			var phrase string
+			if end < 0 {
+				phrase, rest = rest[1:], ""
+			} else {
+				phrase, rest = rest[1:end+1], rest[end+2:]
+			}
+			words := strings.Fields(strings.ToLower(phrase))
+			if len(words) > 0 {
+				term = &proto.SearchTerm{Text: strings.Join(words, " "), Phrase: len(words) > 1}
+			}
+		} else {
+			end := strings.IndexFunc(rest, unicode.IsSpace)
+			if end < 0 {
+				end = len(rest)
+			}
+			word := strings.ToLower(rest[:end])
+			rest = rest[end:]
+			prefix := strings.HasSuffix(word, "*")
+			word = strings.TrimRight(word, "*")
+			if word != "" {
+				term = &proto.SearchTerm{Text: word, Prefix: prefix}
+			}
+		}
+		if term != nil {
+			terms = append(terms, term)
+		}
+		rest = strings.TrimSpace(rest)
+	}
+
+	if len(terms) == 0 {
+		return nil, errors.New("q must contain at least one search term")
+	}
+	if len(terms) > maxSearchTerms {
+		return nil, errors.New("q contains too many search terms")
+	}
+	return terms, nil
+}
+
+// termMatcher finds occurrences of the search terms to highlight them.
+type termMatcher struct {
+	re *regexp.Regexp
+}
+
+func newTermMatcher(terms []*proto.SearchTerm) *termMatcher {
+	alternatives := make([]string, 0, len(terms))
+	for _, term := range terms {
+		words := strings.Fields(term.Text)
+		for i, word := range words {
+			words[i] = regexp.QuoteMeta(word)
+		}
+		pattern := `\b` + strings.Join(words, `\s+`)
+		if term.Prefix {
+			pattern += `\w*`
+		} else {
+			pattern += `\b`
+		}
+		alternatives = append(alternatives, pattern)
+	}
+	return &termMatcher{re: regexp.MustCompile(`(?i)` + strings.Join(alternatives, "|"))}
+}
+
+// highlight HTML-escapes text[start:end] and wraps the matches in <mark>.
+func (m *termMatcher) highlight(text string, start, end int) string {
+	var b strings.Builder
+	pos := start
+	for _, loc := range m.re.FindAllStringIndex(text[start:end], -1) {
+		b.WriteString(html.EscapeString(text[pos : start+loc[0]]))
+		b.WriteString(highlightOpen)
+		b.WriteString(html.EscapeString(text[start+loc[0] : start+loc[1]]))
+		b.WriteString(highlightClose)
+		pos = start + loc[1]
+	}
+	b.WriteString(html.EscapeString(text[pos:end]))
+	return b.String()
+}
+
+// snippet returns the highlighted part of text around its first match, or
+// an empty string when text does not match.
+func (m *termMatcher) snippet(text string) string {
+	loc := m.re.FindStringIndex(text)
+	if loc == nil {
+		return ""
+	}
+	start := loc[0] - snippetLength/3
+	if start < 0 {
+		start = 0
+	}
+	end := start + snippetLength
+	if end > len(text) {
+		end = len(text)
+	}
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+
+	snippet := m.highlight(text, start, end)
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(text) {
+		snippet += "…"
+	}
+	return snippet
+}
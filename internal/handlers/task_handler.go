@@ -3,11 +3,26 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/auth"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/blob"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/events"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/models"
 	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/reminder"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/taskinput"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/webhook"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/workflow"
 	"github.com/gin-gonic/gin"
+	"github.com/graph-gophers/graphql-go"
 	"google.golang.org/grpc/status"
+	"io"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -21,17 +36,45 @@ type TaskHandler struct {
 	serviceName string
 	version     string
 
-	grpcClient proto.TaskServiceClient
-	websocket  *webSocketHandler
+	grpcClient  proto.TaskServiceClient
+	websocket   *webSocketHandler
+	graphql     *graphql.Schema
+	openapi     []byte
+	workflow    *workflow.StateMachine
+	blobs       blob.Store
+	attachments *models.Attachments
+	reminders   *reminder.Scheduler
+	webhooks    *webhook.Dispatcher
+	github      *models.GitHub
+	githubMu    sync.Mutex
+	admins      map[string]bool
 }
 
-func NewTaskHandler(grpcClient proto.TaskServiceClient) *TaskHandler {
-	return &TaskHandler{
+// NewTaskHandler serves the REST API from grpcClient, which is expected to
+// publish its task mutations on bus. Attachment content is kept in blobs and
+// reminders tells which notification channels exist. webhooks delivers the
+// webhook queue and github configures the GitHub receiver, which is disabled
+// when it is nil. Only admins may read the audit log and manage webhooks.
+func NewTaskHandler(grpcClient proto.TaskServiceClient, stateMachine *workflow.StateMachine, bus *events.Bus, blobs blob.Store, attachments *models.Attachments, reminders *reminder.Scheduler, webhooks *webhook.Dispatcher, github *models.GitHub, admins []string) *TaskHandler {
+	adminSet := make(map[string]bool, len(admins))
+	for _, admin := range admins {
+		adminSet[admin] = true
+	}
+	h := &TaskHandler{
 		grpcClient:  grpcClient,
 		serviceName: "nashville-task-service",
 		version:     version,
-		websocket:   newWebSocketHandler(grpcClient),
+		workflow:    stateMachine,
+		blobs:       blobs,
+		attachments: attachments,
+		reminders:   reminders,
+		webhooks:    webhooks,
+		github:      github,
+		admins:      adminSet,
 	}
+	h.graphql = newGraphQLSchema(h, bus)
+	h.websocket = newWebSocketHandler(bus, h.graphql, grpcClient)
+	return h
 }
 
 func (h *TaskHandler) getHealthz(c *gin.Context) {
@@ -44,33 +87,101 @@ func (h *TaskHandler) getHealthz(c *gin.Context) {
 	c.JSON(http.StatusOK, healthResponse)
 }
 
+// AddServiceRoutes registers the REST API on wsRouter through updateHandler
+// and documents it at /openapi.json. It panics when a route is missing from
+// routeDocs.
 func (h *TaskHandler) AddServiceRoutes(wsRouter *gin.RouterGroup, updateHandler func(wsRouter *gin.RouterGroup, method string, path string, handler func(c *gin.Context))) {
+	var routes []route
+	register := updateHandler
+	updateHandler = func(wsRouter *gin.RouterGroup, method string, path string, handler func(c *gin.Context)) {
+		routes = append(routes, route{method: method, path: path})
+		register(wsRouter, method, path, handler)
+	}
 	updateHandler(wsRouter, http.MethodGet, "/healthz", h.getHealthz)
 	updateHandler(wsRouter, http.MethodPost, "/task", h.createTask)
 	updateHandler(wsRouter, http.MethodDelete, "/task/:id", h.deleteTask)
 	updateHandler(wsRouter, http.MethodPut, "/task/:id", h.updateTask)
+	updateHandler(wsRouter, http.MethodPatch, "/task/:id", h.updateTask)
 	updateHandler(wsRouter, http.MethodGet, "/task/:id", h.getTask)
+	updateHandler(wsRouter, http.MethodPost, "/task/:id/transition", h.transitionTask)
+	updateHandler(wsRouter, http.MethodPost, "/task/:id/move", h.moveTask)
+	updateHandler(wsRouter, http.MethodGet, "/task/:id/history", h.taskHistory)
+	updateHandler(wsRouter, http.MethodGet, "/task/:id/markdown", h.exportMarkdown)
+	updateHandler(wsRouter, http.MethodPut, "/task/:id/assignee", h.assignTask)
+	updateHandler(wsRouter, http.MethodDelete, "/task/:id/assignee", h.unassignTask)
+	updateHandler(wsRouter, http.MethodPost, "/task/:id/labels", h.attachLabels)
+	updateHandler(wsRouter, http.MethodDelete, "/task/:id/labels/:label", h.detachLabel)
+	updateHandler(wsRouter, http.MethodPost, "/task/:id/comments", h.createComment)
+	updateHandler(wsRouter, http.MethodGet, "/task/:id/comments", h.listComments)
+	updateHandler(wsRouter, http.MethodGet, "/task/:id/comments/:commentId/replies", h.listReplies)
+	updateHandler(wsRouter, http.MethodPut, "/task/:id/comments/:commentId", h.updateComment)
+	updateHandler(wsRouter, http.MethodDelete, "/task/:id/comments/:commentId", h.deleteComment)
+	updateHandler(wsRouter, http.MethodGet, "/task/:id/recurrence", h.previewRecurrence)
+	updateHandler(wsRouter, http.MethodPost, "/task/:id/recurrence/skip", h.skipOccurrence)
+	updateHandler(wsRouter, http.MethodPost, "/task/:id/recurrence/stop", h.stopRecurrence)
+	updateHandler(wsRouter, http.MethodPost, "/task/:id/dependencies", h.addDependency)
+	updateHandler(wsRouter, http.MethodGet, "/task/:id/dependencies", h.listDependencies)
+	updateHandler(wsRouter, http.MethodDelete, "/task/:id/dependencies/:blockerId", h.removeDependency)
+	updateHandler(wsRouter, http.MethodPost, "/task/:id/reminders", h.createReminder)
+	updateHandler(wsRouter, http.MethodGet, "/task/:id/reminders", h.listReminders)
+	updateHandler(wsRouter, http.MethodDelete, "/task/:id/reminders/:reminderId", h.deleteReminder)
+	updateHandler(wsRouter, http.MethodPost, "/task/:id/attachments", h.uploadAttachment)
+	updateHandler(wsRouter, http.MethodGet, "/task/:id/attachments", h.listAttachments)
+	updateHandler(wsRouter, http.MethodGet, "/task/:id/attachments/:attachmentId", h.downloadAttachment)
+	updateHandler(wsRouter, http.MethodDelete, "/task/:id/attachments/:attachmentId", h.deleteAttachment)
 	updateHandler(wsRouter, http.MethodGet, "/tasks", h.listTasks)
-	wsRouter.GET("/task/ws", h.websocket.handleConnections)
+	updateHandler(wsRouter, http.MethodGet, "/tasks/search", h.searchTasks)
+	updateHandler(wsRouter, http.MethodGet, "/tasks/overdue", h.listOverdueTasks)
+	updateHandler(wsRouter, http.MethodGet, "/tasks/due", h.listDueTasks)
+	updateHandler(wsRouter, http.MethodGet, "/tasks/order", h.orderTasks)
+	updateHandler(wsRouter, http.MethodGet, "/tasks/export", h.exportTasks)
+	updateHandler(wsRouter, http.MethodPost, "/tasks/import", h.importTasks)
+	updateHandler(wsRouter, http.MethodPost, "/tasks/import/markdown", h.importMarkdown)
+	updateHandler(wsRouter, http.MethodGet, "/tasks.ics", h.taskFeed)
+	updateHandler(wsRouter, http.MethodGet, "/users/me/tasks", h.listMyTasks)
+	updateHandler(wsRouter, http.MethodGet, "/users/:id/tasks", h.listUserTasks)
+	updateHandler(wsRouter, http.MethodPost, "/label", h.createLabel)
+	updateHandler(wsRouter, http.MethodGet, "/label/:name", h.getLabel)
+	updateHandler(wsRouter, http.MethodPut, "/label/:name", h.updateLabel)
+	updateHandler(wsRouter, http.MethodDelete, "/label/:name", h.deleteLabel)
+	updateHandler(wsRouter, http.MethodGet, "/labels", h.listLabels)
+	updateHandler(wsRouter, http.MethodPost, "/feeds", h.createFeed)
+	updateHandler(wsRouter, http.MethodGet, "/feeds", h.listFeeds)
+	updateHandler(wsRouter, http.MethodDelete, "/feeds/:id", h.deleteFeed)
+	updateHandler(wsRouter, http.MethodPost, "/graphql", h.serveGraphQL)
+	updateHandler(wsRouter, http.MethodGet, "/graphql", h.serveGraphQL)
+	updateHandler(wsRouter, http.MethodGet, "/audit", h.auditLog)
+	updateHandler(wsRouter, http.MethodPost, "/webhooks", h.createWebhook)
+	updateHandler(wsRouter, http.MethodGet, "/webhooks", h.listWebhooks)
+	updateHandler(wsRouter, http.MethodGet, "/webhooks/dead-letters", h.listDeadLetters)
+	updateHandler(wsRouter, http.MethodGet, "/webhooks/:id", h.getWebhook)
+	updateHandler(wsRouter, http.MethodPut, "/webhooks/:id", h.updateWebhook)
+	updateHandler(wsRouter, http.MethodDelete, "/webhooks/:id", h.deleteWebhook)
+	updateHandler(wsRouter, http.MethodGet, "/webhooks/:id/deliveries", h.listDeliveries)
+	updateHandler(wsRouter, http.MethodPost, "/webhooks/:id/deliveries/:deliveryId/redeliver", h.redeliver)
+	updateHandler(wsRouter, http.MethodPost, "/integrations/github", h.receiveGitHub)
+	// gin treats the colon as the start of a wildcard, so every "/tasks:<verb>"
+	// custom method is routed through tasksAction.
+	updateHandler(wsRouter, http.MethodPost, "/tasks:action", h.tasksAction)
+	updateHandler(wsRouter, http.MethodGet, "/task/ws", h.websocket.handleConnections)
+	updateHandler(wsRouter, http.MethodGet, "/openapi.json", h.getOpenAPI)
+	updateHandler(wsRouter, http.MethodGet, "/docs", h.getDocs)
+	h.openapi = mustOpenAPI(wsRouter.BasePath(), routes)
 }
 
 func (h *TaskHandler) createTask(c *gin.Context) {
-	var req struct {
-		Title       string `json:"title"`
-		Description string `json:"description"`
-		ParentID    string `json:"parent_id"`
-	}
+	var req createTaskRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-
-	grpcReq := &proto.CreateTaskRequest{
-		Title:       req.Title,
-		Description: req.Description,
-		ParentId:    req.ParentID,
+	grpcReq, err := h.createRequest(c.GetHeader(auth.UserHeader), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-	resp, err := h.grpcClient.CreateTask(context.Background(), grpcReq)
+
+	resp, err := h.grpcClient.CreateTask(requestContext(c), grpcReq)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -79,6 +190,11 @@ func (h *TaskHandler) createTask(c *gin.Context) {
 }
 
 func (h *TaskHandler) getTask(c *gin.Context) {
+	loc, err := taskinput.LoadLocation(c.Query("tz"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	id := c.Param("id")
 	req := proto.GetTaskRequest{
 		Id: id,
@@ -90,21 +206,44 @@ func (h *TaskHandler) getTask(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, res)
+	view := newTaskView(res, loc)
+	h.markBlocked(newTaskCache(context.Background(), h.grpcClient, res), []*taskView{view})
+	c.JSON(http.StatusOK, view)
 }
 
 func (h *TaskHandler) updateTask(c *gin.Context) {
-	var req proto.UpdateTaskRequest
+	var in taskInput
+	var fields map[string]json.RawMessage
 	r := c.Request
 	id := c.Param("id")
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	body, err := io.ReadAll(r.Body)
+	if err != nil || json.Unmarshal(body, &fields) != nil || json.Unmarshal(body, &in) != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
 		return
 	}
 
-	req.Id = id
+	req, err := h.updateRequest(id, &in, fields)
+	if err == nil && c.Request.Method == http.MethodPatch {
+		err = patchMask(req, fields)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if hasPath(req.UpdateMask, "status") {
+		task, err := checkStatusChange(requestContext(c), h.grpcClient, h.workflow, id, req.Status)
+		if task == nil {
+			s, _ := status.FromError(err)
+			c.JSON(http.StatusNotFound, gin.H{"error": s})
+			return
+		}
+		if err != nil {
+			h.transitionError(c, task.Status, err)
+			return
+		}
+	}
 
-	res, err := h.grpcClient.UpdateTask(context.Background(), &req)
+	res, err := h.grpcClient.UpdateTask(requestContext(c), req)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
@@ -116,7 +255,7 @@ func (h *TaskHandler) updateTask(c *gin.Context) {
 func (h *TaskHandler) deleteTask(c *gin.Context) {
 	id := c.Param("id")
 	req := &proto.DeleteTaskRequest{Id: id}
-	resp, err := h.grpcClient.DeleteTask(context.Background(), req)
+	resp, err := h.grpcClient.DeleteTask(requestContext(c), req)
 	if err != nil {
 		s, _ := status.FromError(err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": s})
@@ -128,20 +267,44 @@ func (h *TaskHandler) deleteTask(c *gin.Context) {
 
 func (h *TaskHandler) listTasks(c *gin.Context) {
 	r := c.Request
-	vars := r.URL.Query()
-	pageVar := vars.Get("page")
-	if pageVar == "" {
-		pageVar = "0"
+	req, err := h.parseListTasksRequest(r.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-	page, _ := strconv.Atoi(pageVar)
-	get := vars.Get("pageSize")
-	if get == "" {
-		get = "50"
+
+	h.respondTasks(c, req)
+}
+
+// respondTasks lists the tasks matching req and renders them in the time zone
+// named by the tz query parameter.
+func (h *TaskHandler) respondTasks(c *gin.Context, req *proto.ListTasksRequest) {
+	loc, err := taskinput.LoadLocation(c.Query("tz"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-	pageSize, _ := strconv.Atoi(get)
-	if pageSize > 50 || pageSize < 0 {
-		pageSize = 50
+
+	res, err := h.grpcClient.ListTasks(context.Background(), req)
+	if err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": s})
+		return
+	}
+	var resp struct {
+		Results interface{} `json:"results"`
 	}
+
+	views := newTaskViews(res.Tasks, loc)
+	h.markBlocked(newTaskCache(context.Background(), h.grpcClient, res.Tasks...), views)
+	resp.Results = views
+	c.JSON(http.StatusOK, resp)
+}
+
+// parseListTasksRequest converts the /tasks query parameters into a
+// ListTasksRequest.
+func (h *TaskHandler) parseListTasksRequest(vars url.Values) (*proto.ListTasksRequest, error) {
+	page, pageSize := parsePagination(vars)
 	var startTime, endTime time.Time
 	if s := vars.Get("startTime"); s != "" {
 		startTime, _ = time.Parse(time.RFC3339, s)
@@ -155,8 +318,7 @@ func (h *TaskHandler) listTasks(c *gin.Context) {
 		endTime, _ = time.Parse(time.RFC3339, e)
 
 		if endTime.Before(startTime) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "End time cannot be lesser than start time"})
-			return
+			return nil, errors.New("End time cannot be lesser than start time")
 		}
 	}
 
@@ -170,19 +332,90 @@ func (h *TaskHandler) listTasks(c *gin.Context) {
 	if !endTime.IsZero() {
 		req.EndTime = endTime.Format(time.RFC3339)
 	}
+	for _, s := range splitList(vars.Get("status")) {
+		if !h.workflow.Valid(s) {
+			return nil, fmt.Errorf("%w %q", workflow.ErrUnknownStatus, s)
+		}
+		req.Statuses = append(req.Statuses, s)
+	}
 
-	res, err := h.grpcClient.ListTasks(context.Background(), req)
+	loc, err := taskinput.LoadLocation(vars.Get("tz"))
 	if err != nil {
-		s, _ := status.FromError(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": s})
-		return
+		return nil, err
 	}
-	var resp struct {
-		Results interface{} `json:"results"`
+	req.Assignee = vars.Get("assignee")
+	req.Reporter = vars.Get("reporter")
+	req.ParentId = vars.Get("parentId")
+	req.Labels = splitList(vars.Get("labels"))
+	switch mode := vars.Get("labelMode"); mode {
+	case "", labelModeAny, labelModeAll:
+		req.LabelMode = mode
+	default:
+		return nil, fmt.Errorf("labelMode must be %s or %s", labelModeAny, labelModeAll)
+	}
+	if req.DueAfter, err = parseTimeParam(vars, "dueAfter", loc, false); err != nil {
+		return nil, err
 	}
+	if req.DueBefore, err = parseTimeParam(vars, "dueBefore", loc, true); err != nil {
+		return nil, err
+	}
+	switch sortBy := vars.Get("sort"); sortBy {
+	case "", sortByPriority, sortByDue:
+		req.SortBy = sortBy
+	default:
+		return nil, fmt.Errorf("unknown sort %q", sortBy)
+	}
+	return req, nil
+}
 
-	resp.Results = res.Tasks
-	c.JSON(http.StatusOK, resp)
+// parseTimeParam parses the named query parameter with parseTime and returns
+// it as RFC 3339 in UTC, or an empty string when the parameter is missing.
+func parseTimeParam(vars url.Values, name string, loc *time.Location, endOfDay bool) (string, error) {
+	v := vars.Get(name)
+	if v == "" {
+		return "", nil
+	}
+	t, err := taskinput.ParseTime(v, loc, endOfDay)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return t.UTC().Format(time.RFC3339), nil
+}
+
+// splitList splits a comma separated query parameter, dropping empty items.
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// requestContext returns the context for backend calls made on behalf of the
+// user that sent c.
+func requestContext(c *gin.Context) context.Context {
+	return auth.WithUser(context.Background(), c.GetHeader(auth.UserHeader))
+}
+
+// parsePagination reads the page and pageSize query parameters, capping the
+// page size at 50.
+func parsePagination(vars url.Values) (int, int) {
+	pageVar := vars.Get("page")
+	if pageVar == "" {
+		pageVar = "0"
+	}
+	page, _ := strconv.Atoi(pageVar)
+	get := vars.Get("pageSize")
+	if get == "" {
+		get = "50"
+	}
+	pageSize, _ := strconv.Atoi(get)
+	if pageSize > 50 || pageSize < 0 {
+		pageSize = 50
+	}
+	return page, pageSize
 }
 
 func TwoDaysAgoTime() time.Time {
@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/recurrence"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/taskinput"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/workflow"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	sortByPriority = "priority"
+	sortByDue      = "due"
+)
+
+// maskableFields are the task fields an update only writes when the request
+// body names them. Title and description are always written.
+var maskableFields = []string{"status", "due_at", "priority", "assignee", "reporter", "rrule", "recurrence_mode"}
+
+// createRequest validates req and converts it into a CreateTaskRequest. New
+// tasks start in the initial workflow status and are reported by user unless
+// req says otherwise.
+func (h *TaskHandler) createRequest(user string, req *createTaskRequest) (*proto.CreateTaskRequest, error) {
+	if req.Reporter == "" {
+		req.Reporter = user
+	}
+	if req.Status == "" {
+		req.Status = h.workflow.Initial()
+	} else if !h.workflow.Valid(req.Status) {
+		return nil, fmt.Errorf("%w %q", workflow.ErrUnknownStatus, req.Status)
+	}
+	dueAt, err := taskinput.ParseDueAt(req.DueAt, req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	priority, err := taskinput.ParsePriority(req.Priority)
+	if err != nil {
+		return nil, err
+	}
+	series, err := parseRecurrence(&req.taskInput, dueAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.CreateTaskRequest{
+		Title:          req.Title,
+		Description:    req.Description,
+		ParentId:       req.ParentID,
+		Status:         req.Status,
+		DueAt:          series.dueAt,
+		Priority:       priority,
+		Assignee:       req.Assignee,
+		Reporter:       req.Reporter,
+		Rrule:          series.rrule,
+		Timezone:       series.timezone,
+		SeriesStart:    series.start,
+		RecurrenceMode: series.mode,
+		ExternalId:     req.ExternalID,
+	}, nil
+}
+
+// recurrenceInput is the validated recurrence of a create or update request.
+type recurrenceInput struct {
+	rrule    string
+	timezone string
+	mode     string
+	start    *timestamppb.Timestamp
+	dueAt    *timestamppb.Timestamp
+}
+
+// parseRecurrence validates the rrule and recurrence mode of in. A series
+// starts at dueAt; without one it starts now and the task falls due at the
+// first occurrence.
+func parseRecurrence(in *taskInput, dueAt *timestamppb.Timestamp) (*recurrenceInput, error) {
+	if !recurrence.ValidMode(in.RecurrenceMode) {
+		return nil, fmt.Errorf("%w %q", recurrence.ErrInvalidMode, in.RecurrenceMode)
+	}
+	series := &recurrenceInput{dueAt: dueAt}
+	if in.RRule == "" {
+		if in.RecurrenceMode != "" {
+			return nil, errors.New("recurrence_mode requires an rrule")
+		}
+		return series, nil
+	}
+	loc, err := taskinput.LoadLocation(in.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now().Truncate(time.Second)
+	if dueAt != nil {
+		start = dueAt.AsTime()
+	}
+	rule, err := recurrence.Parse(in.RRule, loc, start)
+	if err != nil {
+		return nil, err
+	}
+	if dueAt == nil {
+		first, ok := rule.First(start)
+		if !ok {
+			return nil, fmt.Errorf("%w: it has no upcoming occurrence", recurrence.ErrInvalidRule)
+		}
+		series.dueAt = timestamppb.New(first)
+	}
+
+	series.rrule = rule.String()
+	series.timezone = loc.String()
+	series.mode = in.RecurrenceMode
+	series.start = timestamppb.New(start)
+	return series, nil
+}
+
+// updateRequest converts an update body into an UpdateTaskRequest whose mask
+// names title, description and the maskable fields present in fields.
+func (h *TaskHandler) updateRequest(id string, in *taskInput, fields map[string]json.RawMessage) (*proto.UpdateTaskRequest, error) {
+	req := &proto.UpdateTaskRequest{
+		Id:          id,
+		Title:       in.Title,
+		Description: in.Description,
+		Status:      in.Status,
+		Assignee:    in.Assignee,
+		Reporter:    in.Reporter,
+		UpdateMask:  &fieldmaskpb.FieldMask{Paths: []string{"title", "description"}},
+	}
+	for _, name := range maskableFields {
+		if _, ok := fields[name]; ok {
+			req.UpdateMask.Paths = append(req.UpdateMask.Paths, name)
+		}
+	}
+
+	var err error
+	if req.DueAt, err = taskinput.ParseDueAt(in.DueAt, in.Timezone); err != nil {
+		return nil, err
+	}
+	if req.Priority, err = taskinput.ParsePriority(in.Priority); err != nil {
+		return nil, err
+	}
+	if err := applyRecurrence(req, in); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// applyRecurrence validates the rrule and recurrence mode of in when the mask
+// of req names them and copies them into req.
+func applyRecurrence(req *proto.UpdateTaskRequest, in *taskInput) error {
+	if hasPath(req.UpdateMask, "rrule") {
+		// A new rule restarts the series from the due date in the body, and
+		// from now when there is none.
+		if in.RRule != "" && !hasPath(req.UpdateMask, "due_at") {
+			req.UpdateMask.Paths = append(req.UpdateMask.Paths, "due_at")
+		}
+		series, err := parseRecurrence(in, req.DueAt)
+		if err != nil {
+			return err
+		}
+		req.Rrule, req.Timezone, req.RecurrenceMode = series.rrule, series.timezone, series.mode
+		req.SeriesStart, req.DueAt = series.start, series.dueAt
+		req.UpdateMask.Paths = append(req.UpdateMask.Paths, "timezone", "series_start")
+	} else if hasPath(req.UpdateMask, "recurrence_mode") {
+		if !recurrence.ValidMode(in.RecurrenceMode) {
+			return fmt.Errorf("%w %q", recurrence.ErrInvalidMode, in.RecurrenceMode)
+		}
+		req.RecurrenceMode = in.RecurrenceMode
+	}
+	return nil
+}
+
+// patchMask drops title and description from the mask of req unless fields
+// names them, so that a PATCH only writes the fields of its body.
+func patchMask(req *proto.UpdateTaskRequest, fields map[string]json.RawMessage) error {
+	req.UpdateMask.Paths = slices.DeleteFunc(req.UpdateMask.Paths, func(path string) bool {
+		_, ok := fields[path]
+		return (path == "title" || path == "description") && !ok
+	})
+	if len(req.UpdateMask.Paths) == 0 {
+		return errors.New("no fields to update")
+	}
+	return nil
+}
+
+func hasPath(mask *fieldmaskpb.FieldMask, path string) bool {
+	for _, p := range mask.GetPaths() {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
+
+func newTaskView(task *proto.Task, loc *time.Location) *taskView {
+	return &taskView{
+		ID:             task.Id,
+		ParentID:       task.ParentId,
+		Title:          task.Title,
+		Description:    task.Description,
+		Status:         task.Status,
+		Priority:       taskinput.PriorityName(task.Priority),
+		DueAt:          formatTimestamp(task.DueAt, loc),
+		Assignee:       task.Assignee,
+		Reporter:       task.Reporter,
+		Labels:         task.Labels,
+		Attachments:    newAttachmentViews(task.Attachments, loc),
+		BlockedBy:      task.BlockedBy,
+		RRule:          task.Rrule,
+		Timezone:       task.Timezone,
+		RecurrenceMode: task.RecurrenceMode,
+		SeriesID:       task.SeriesId,
+		ExternalID:     task.ExternalId,
+		CreatedAt:      formatTimestamp(task.CreatedAt, loc),
+		UpdatedAt:      formatTimestamp(task.UpdatedAt, loc),
+	}
+}
+
+func newTaskViews(tasks []*proto.Task, loc *time.Location) []*taskView {
+	views := make([]*taskView, 0, len(tasks))
+	for _, task := range tasks {
+		views = append(views, newTaskView(task, loc))
+	}
+	return views
+}
+
+func formatTimestamp(ts *timestamppb.Timestamp, loc *time.Location) string {
+	if ts == nil {
+		return ""
+	}
+	return ts.AsTime().In(loc).Format(time.RFC3339)
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/workflow"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+type transitionRequest struct {
+	Status string `json:"status" binding:"required"`
+}
+
+type transitionResponse struct {
+	ID             string `json:"id"`
+	Status         string `json:"status"`
+	PreviousStatus string `json:"previous_status"`
+}
+
+func (h *TaskHandler) transitionTask(c *gin.Context) {
+	var req transitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id := c.Param("id")
+	task, err := h.grpcClient.GetTask(context.Background(), &proto.GetTaskRequest{Id: id})
+	if err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusNotFound, gin.H{"error": s})
+		return
+	}
+	if err := h.workflow.Transition(task.Status, req.Status); err != nil {
+		h.transitionError(c, task.Status, err)
+		return
+	}
+
+	_, err = h.grpcClient.UpdateTask(requestContext(c), &proto.UpdateTaskRequest{
+		Id:         id,
+		Status:     req.Status,
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"status"}},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, transitionResponse{
+		ID:             id,
+		Status:         req.Status,
+		PreviousStatus: task.Status,
+	})
+}
+
+// transitionError reports a rejected status change, listing the statuses
+// that are reachable from the current one.
+func (h *TaskHandler) transitionError(c *gin.Context, from string, err error) {
+	if errors.Is(err, workflow.ErrIllegalTransition) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "allowed": h.workflow.Next(from)})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// checkStatusChange verifies that the task may move to the given status.
+// Keeping the current status is always allowed.
+func checkStatusChange(ctx context.Context, grpcClient proto.TaskServiceClient, stateMachine *workflow.StateMachine, id, to string) (*proto.Task, error) {
+	task, err := grpcClient.GetTask(ctx, &proto.GetTaskRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	if task.Status == to && to != "" {
+		return task, nil
+	}
+	return task, stateMachine.Transition(task.Status, to)
+}
@@ -0,0 +1,348 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/auth"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/taskinput"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/webhook"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	webhookSecretBytes = 32
+	webhooksDenied     = "webhooks are restricted to admins"
+)
+
+// webhookRequest creates or replaces a webhook. A secret is generated when
+// none is given on create and kept when none is given on update.
+type webhookRequest struct {
+	URL        string   `json:"url" binding:"required"`
+	EventTypes []string `json:"event_types" binding:"required"`
+	Secret     string   `json:"secret"`
+	Active     *bool    `json:"active"`
+}
+
+type webhookView struct {
+	ID         string   `json:"id"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Active     bool     `json:"active"`
+	CreatedBy  string   `json:"created_by,omitempty"`
+	CreatedAt  string   `json:"created_at,omitempty"`
+	UpdatedAt  string   `json:"updated_at,omitempty"`
+	// Secret is only returned when it was generated.
+	Secret string `json:"secret,omitempty"`
+}
+
+func newWebhookView(hook *proto.Webhook, loc *time.Location) *webhookView {
+	return &webhookView{
+		ID:         hook.Id,
+		URL:        hook.Url,
+		EventTypes: hook.EventTypes,
+		Active:     hook.Active,
+		CreatedBy:  hook.CreatedBy,
+		CreatedAt:  formatTimestamp(hook.CreatedAt, loc),
+		UpdatedAt:  formatTimestamp(hook.UpdatedAt, loc),
+	}
+}
+
+type deliveryView struct {
+	ID            string          `json:"id"`
+	WebhookID     string          `json:"webhook_id"`
+	EventType     string          `json:"event_type"`
+	TaskID        string          `json:"task_id,omitempty"`
+	Status        string          `json:"status"`
+	Attempts      []*attemptView  `json:"attempts"`
+	NextAttemptAt string          `json:"next_attempt_at,omitempty"`
+	CreatedAt     string          `json:"created_at,omitempty"`
+	DeliveredAt   string          `json:"delivered_at,omitempty"`
+	Payload       json.RawMessage `json:"payload,omitempty"`
+}
+
+type attemptView struct {
+	Time       string `json:"time"`
+	StatusCode int32  `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+func newDeliveryView(d *proto.WebhookDelivery, loc *time.Location) *deliveryView {
+	view := &deliveryView{
+		ID:            d.Id,
+		WebhookID:     d.WebhookId,
+		EventType:     d.EventType,
+		TaskID:        d.TaskId,
+		Status:        d.Status,
+		Attempts:      make([]*attemptView, 0, len(d.Attempts)),
+		NextAttemptAt: formatTimestamp(d.NextAttemptAt, loc),
+		CreatedAt:     formatTimestamp(d.CreatedAt, loc),
+		DeliveredAt:   formatTimestamp(d.DeliveredAt, loc),
+	}
+	if json.Valid(d.Payload) {
+		view.Payload = d.Payload
+	}
+	for _, a := range d.Attempts {
+		view.Attempts = append(view.Attempts, &attemptView{
+			Time:       formatTimestamp(a.Time, loc),
+			StatusCode: a.StatusCode,
+			Error:      a.Error,
+			DurationMs: a.DurationMs,
+		})
+	}
+	return view
+}
+
+// createWebhook subscribes a URL to task events. Webhooks are managed by
+// admins since they receive every task they are subscribed to.
+func (h *TaskHandler) createWebhook(c *gin.Context) {
+	if !h.requireAdmin(c, webhooksDenied) {
+		return
+	}
+	var req webhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateWebhook(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	generated := req.Secret == ""
+	if generated {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		req.Secret = secret
+	}
+
+	hook, err := h.grpcClient.CreateWebhook(requestContext(c), &proto.CreateWebhookRequest{
+		Webhook: &proto.Webhook{
+			Url:        req.URL,
+			EventTypes: req.EventTypes,
+			Secret:     req.Secret,
+			Active:     req.Active == nil || *req.Active,
+			CreatedBy:  c.GetHeader(auth.UserHeader),
+		},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	view := newWebhookView(hook, time.UTC)
+	if generated {
+		view.Secret = hook.Secret
+	}
+	c.JSON(http.StatusOK, view)
+}
+
+func (h *TaskHandler) listWebhooks(c *gin.Context) {
+	if !h.requireAdmin(c, webhooksDenied) {
+		return
+	}
+	loc, err := taskinput.LoadLocation(c.Query("tz"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	res, err := h.grpcClient.ListWebhooks(context.Background(), &proto.ListWebhooksRequest{})
+	if err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": s})
+		return
+	}
+	hooks := make([]*webhookView, 0, len(res.Webhooks))
+	for _, hook := range res.Webhooks {
+		hooks = append(hooks, newWebhookView(hook, loc))
+	}
+	var resp struct {
+		Results interface{} `json:"results"`
+	}
+
+	resp.Results = hooks
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *TaskHandler) getWebhook(c *gin.Context) {
+	if !h.requireAdmin(c, webhooksDenied) {
+		return
+	}
+	loc, err := taskinput.LoadLocation(c.Query("tz"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	hook, err := h.grpcClient.GetWebhook(context.Background(), &proto.GetWebhookRequest{Id: c.Param("id")})
+	if err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusNotFound, gin.H{"error": s})
+		return
+	}
+	c.JSON(http.StatusOK, newWebhookView(hook, loc))
+}
+
+func (h *TaskHandler) updateWebhook(c *gin.Context) {
+	if !h.requireAdmin(c, webhooksDenied) {
+		return
+	}
+	var req webhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateWebhook(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	hook, err := h.grpcClient.GetWebhook(context.Background(), &proto.GetWebhookRequest{Id: c.Param("id")})
+	if err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusNotFound, gin.H{"error": s})
+		return
+	}
+
+	hook.Url = req.URL
+	hook.EventTypes = req.EventTypes
+	if req.Secret != "" {
+		hook.Secret = req.Secret
+	}
+	if req.Active != nil {
+		hook.Active = *req.Active
+	}
+	hook, err = h.grpcClient.UpdateWebhook(requestContext(c), &proto.UpdateWebhookRequest{Webhook: hook})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, newWebhookView(hook, time.UTC))
+}
+
+// deleteWebhook removes a webhook. Its pending deliveries are given up when
+// they are next attempted.
+func (h *TaskHandler) deleteWebhook(c *gin.Context) {
+	if !h.requireAdmin(c, webhooksDenied) {
+		return
+	}
+	resp, err := h.grpcClient.DeleteWebhook(requestContext(c), &proto.DeleteWebhookRequest{Id: c.Param("id")})
+	if err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": s})
+		return
+	}
+	h.webhooks.Wake()
+	c.JSON(http.StatusOK, resp)
+}
+
+// listDeliveries lists the deliveries of a webhook newest first, optionally
+// only those with the statuses given by the status parameter.
+func (h *TaskHandler) listDeliveries(c *gin.Context) {
+	if !h.requireAdmin(c, webhooksDenied) {
+		return
+	}
+	if _, err := h.grpcClient.GetWebhook(context.Background(), &proto.GetWebhookRequest{Id: c.Param("id")}); err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusNotFound, gin.H{"error": s})
+		return
+	}
+	h.respondDeliveries(c, c.Param("id"), splitList(c.Query("status")))
+}
+
+// listDeadLetters lists the deliveries of all webhooks that were given up.
+func (h *TaskHandler) listDeadLetters(c *gin.Context) {
+	if !h.requireAdmin(c, webhooksDenied) {
+		return
+	}
+	h.respondDeliveries(c, "", []string{webhook.StatusDead})
+}
+
+func (h *TaskHandler) respondDeliveries(c *gin.Context, webhookID string, statuses []string) {
+	vars := c.Request.URL.Query()
+	loc, err := taskinput.LoadLocation(vars.Get("tz"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for _, s := range statuses {
+		if s != webhook.StatusPending && s != webhook.StatusDelivered && s != webhook.StatusDead {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("status must be %s, %s or %s", webhook.StatusPending, webhook.StatusDelivered, webhook.StatusDead)})
+			return
+		}
+	}
+	page, pageSize := parsePagination(vars)
+
+	res, err := h.grpcClient.ListWebhookDeliveries(context.Background(), &proto.ListWebhookDeliveriesRequest{
+		WebhookId: webhookID,
+		Statuses:  statuses,
+		Page:      int32(page),
+		PageSize:  int32(pageSize),
+	})
+	if err != nil {
+		s, _ := status.FromError(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": s})
+		return
+	}
+	deliveries := make([]*deliveryView, 0, len(res.Deliveries))
+	for _, d := range res.Deliveries {
+		deliveries = append(deliveries, newDeliveryView(d, loc))
+	}
+	var resp struct {
+		Results interface{} `json:"results"`
+	}
+
+	resp.Results = deliveries
+	c.JSON(http.StatusOK, resp)
+}
+
+// redeliver queues the payload of a past delivery again as a new delivery.
+func (h *TaskHandler) redeliver(c *gin.Context) {
+	if !h.requireAdmin(c, webhooksDenied) {
+		return
+	}
+	delivery, err := h.grpcClient.GetWebhookDelivery(context.Background(), &proto.GetWebhookDeliveryRequest{Id: c.Param("deliveryId")})
+	if err != nil || delivery.WebhookId != c.Param("id") {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Delivery not found"})
+		return
+	}
+	redelivery, err := h.webhooks.Redeliver(requestContext(c), delivery)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, newDeliveryView(redelivery, time.UTC))
+}
+
+func newWebhookSecret() (string, error) {
+	secret := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+func validateWebhook(req *webhookRequest) error {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+	if len(req.EventTypes) == 0 {
+		return errors.New("event_types must not be empty")
+	}
+	for _, t := range req.EventTypes {
+		if !webhook.ValidEventType(t) {
+			return fmt.Errorf("unknown event type %q", t)
+		}
+	}
+	return nil
+}
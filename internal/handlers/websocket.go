@@ -1,13 +1,34 @@
 package handlers
 
 import (
-	"fmt"
-	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
-	"github.com/gin-gonic/gin"
-	"github.com/gorilla/websocket"
+	"context"
 	"log"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/auth"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/events"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/graph-gophers/graphql-go"
+)
+
+const (
+	// myAssigneeTopic is resolved to the assignee topic of the connected user.
+	myAssigneeTopic = "assignee:me"
+	// myUserTopic is resolved to the user topic of the connected user.
+	myUserTopic = "user:me"
+
+	wsWriteWait    = 10 * time.Second
+	wsPongWait     = 60 * time.Second
+	wsPingInterval = wsPongWait * 9 / 10
+	wsEventBuffer  = 64
+
+	graphqlMessageType         = "graphql"
+	graphqlCompleteMessageType = "graphql_complete"
 )
 
 var upgrader = websocket.Upgrader{
@@ -19,54 +40,215 @@ var upgrader = websocket.Upgrader{
 }
 
 type webSocketHandler struct {
-	grpcClient proto.TaskServiceClient
-	clients    map[*websocket.Conn]struct{}
-	broadcast  chan []byte
+	bus    *events.Bus
+	schema *graphql.Schema
+	client proto.TaskServiceClient
 }
 
-func newWebSocketHandler(grpcClient proto.TaskServiceClient) *webSocketHandler {
+func newWebSocketHandler(bus *events.Bus, schema *graphql.Schema, client proto.TaskServiceClient) *webSocketHandler {
 	return &webSocketHandler{
-		grpcClient: grpcClient,
-		clients:    make(map[*websocket.Conn]struct{}),
-		broadcast:  make(chan []byte),
+		bus:    bus,
+		schema: schema,
+		client: client,
 	}
 }
 
+// handleConnections streams task events to the client. The topics query
+// parameter selects the initial topics, "tasks" by default; the client can
+// change them later with subscriptionMessages. GraphQL subscriptions started
+// by the client are served on the same connection.
 func (h *webSocketHandler) handleConnections(c *gin.Context) {
-	defer close(h.broadcast)
-	go h.HandleMessages()
 	ws, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		log.Printf("Failed to upgrade to websocket: %v", err)
 		return
 	}
+	defer ws.Close()
+
+	topics := newTopicSet(c.GetHeader(auth.UserHeader))
+	initial := splitList(c.Query("topics"))
+	if len(initial) == 0 {
+		initial = []string{events.TopicAll}
+	}
+	for _, topic := range initial {
+		topics.add(topic)
+	}
 
-	h.clients[ws] = struct {
-	}{}
+	ctx, cancel := context.WithCancel(requestContext(c))
+	defer cancel()
+	out := make(chan *graphqlMessage, wsEventBuffer)
 
-	for {
-		//_, message, err := ws.ReadMessage()
-		//if err != nil {
-		//	log.Printf("Error reading message: %v", err)
-		//	delete(h.clients, ws)
-		//	break
-		//}
+	evs, unsubscribe := h.bus.Subscribe(wsEventBuffer)
+	defer unsubscribe()
+	go h.readSubscriptions(ctx, ws, topics, out, unsubscribe)
 
-		h.broadcast <- []byte("message")
-		time.Sleep(1 * time.Second)
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case e, ok := <-evs:
+			if !ok {
+				return
+			}
+			if !topics.matches(&e) {
+				continue
+			}
+			_ = ws.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := ws.WriteJSON(newEventMessage(&e)); err != nil {
+				log.Printf("Error sending message: %v", err)
+				return
+			}
+		case msg := <-out:
+			_ = ws.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := ws.WriteJSON(msg); err != nil {
+				log.Printf("Error sending message: %v", err)
+				return
+			}
+		case <-ping.C:
+			_ = ws.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := ws.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
 	}
 }
 
-func (h *webSocketHandler) HandleMessages() {
-	for message := range h.broadcast {
-		fmt.Println("Received ", string(message))
-		for client := range h.clients {
-			err := client.WriteJSON(response{Message: "response"})
-			if err != nil {
-				log.Printf("Error sending message: %v", err)
-				client.Close()
-				delete(h.clients, client)
+// readSubscriptions applies the client's subscription changes until the
+// connection fails, then ends the event stream. Results of GraphQL
+// subscriptions are sent to out.
+func (h *webSocketHandler) readSubscriptions(ctx context.Context, ws *websocket.Conn, topics *topicSet, out chan<- *graphqlMessage, done func()) {
+	defer done()
+	subscriptions := map[string]context.CancelFunc{}
+	_ = ws.SetReadDeadline(time.Now().Add(wsPongWait))
+	ws.SetPongHandler(func(string) error {
+		return ws.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		var msg subscriptionMessage
+		if err := ws.ReadJSON(&msg); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				log.Printf("Error reading message: %v", err)
+			}
+			return
+		}
+		switch msg.Action {
+		case "subscribe":
+			topics.add(msg.Topic)
+		case "unsubscribe":
+			topics.remove(msg.Topic)
+		case "graphql":
+			if stop, ok := subscriptions[msg.ID]; ok {
+				stop()
+			}
+			subCtx, stop := context.WithCancel(ctx)
+			subscriptions[msg.ID] = stop
+			go h.serveGraphQL(subCtx, &msg, out)
+		case "stop":
+			if stop, ok := subscriptions[msg.ID]; ok {
+				stop()
+				delete(subscriptions, msg.ID)
 			}
 		}
 	}
 }
+
+// serveGraphQL runs the GraphQL operation in msg until ctx is done or the
+// operation ends. Queries and mutations end after their single result.
+func (h *webSocketHandler) serveGraphQL(ctx context.Context, msg *subscriptionMessage, out chan<- *graphqlMessage) {
+	send := func(m *graphqlMessage) bool {
+		select {
+		case out <- m:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	ctx = withTaskLoader(ctx, newTaskLoader(ctx, h.client))
+	results, err := h.schema.Subscribe(ctx, msg.Query, msg.OperationName, msg.Variables)
+	if err != nil {
+		send(&graphqlMessage{Type: graphqlMessageType, ID: msg.ID, Payload: gin.H{"errors": []gin.H{{"message": err.Error()}}}})
+		return
+	}
+	for result := range results {
+		if !send(&graphqlMessage{Type: graphqlMessageType, ID: msg.ID, Payload: result}) {
+			return
+		}
+	}
+	send(&graphqlMessage{Type: graphqlCompleteMessageType, ID: msg.ID})
+}
+
+// topicSet holds the topics a connection is subscribed to.
+type topicSet struct {
+	user string
+
+	mu     sync.RWMutex
+	topics map[string]struct{}
+}
+
+func newTopicSet(user string) *topicSet {
+	return &topicSet{user: user, topics: make(map[string]struct{})}
+}
+
+func (s *topicSet) resolve(topic string) string {
+	topic = strings.TrimSpace(topic)
+	switch {
+	case s.user == "" && (topic == myAssigneeTopic || topic == myUserTopic):
+		return ""
+	case topic == myAssigneeTopic:
+		return events.AssigneeTopic(s.user)
+	case topic == myUserTopic:
+		return events.UserTopic(s.user)
+	}
+	return topic
+}
+
+func (s *topicSet) add(topic string) {
+	if topic = s.resolve(topic); topic == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.topics[topic] = struct{}{}
+}
+
+func (s *topicSet) remove(topic string) {
+	topic = s.resolve(topic)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.topics, topic)
+}
+
+func (s *topicSet) matches(e *events.Event) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, topic := range e.Topics {
+		if _, ok := s.topics[topic]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func newEventMessage(e *events.Event) *eventMessage {
+	msg := &eventMessage{
+		Type:   e.Type,
+		TaskID: e.TaskID,
+		Actor:  e.Actor,
+		Time:   e.Time.Format(time.RFC3339),
+	}
+	if e.Task != nil {
+		msg.Task = newTaskView(e.Task, time.UTC)
+	}
+	if e.Previous != nil {
+		msg.Previous = newTaskView(e.Previous, time.UTC)
+	}
+	if e.Comment != nil {
+		msg.Comment = newCommentView(e.Comment, time.UTC)
+	}
+	if e.Reminder != nil {
+		msg.Reminder = newReminderView(e.Reminder, time.UTC)
+	}
+	return msg
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/events"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/workflow"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// dialWebSocket serves the WebSocket endpoint of a handler backed by tasks
+// and connects to it.
+func dialWebSocket(t *testing.T, tasks *fakeTaskService) *websocket.Conn {
+	t.Helper()
+	stateMachine, err := workflow.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus := events.NewBus()
+	h := NewTaskHandler(tasks, stateMachine, bus, nil, nil, nil, nil, nil, nil)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/task/ws", h.websocket.handleConnections)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/task/ws", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ws.Close() })
+	return ws
+}
+
+// graphqlResult sends query as the GraphQL operation id and returns its
+// first result.
+func graphqlResult(t *testing.T, ws *websocket.Conn, id, query string) map[string]interface{} {
+	t.Helper()
+	if err := ws.WriteJSON(subscriptionMessage{Action: "graphql", ID: id, Query: query}); err != nil {
+		t.Fatal(err)
+	}
+	_ = ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		var msg struct {
+			Type    string                 `json:"type"`
+			ID      string                 `json:"id"`
+			Payload map[string]interface{} `json:"payload"`
+		}
+		if err := ws.ReadJSON(&msg); err != nil {
+			t.Fatalf("reading the result of %s: %v", id, err)
+		}
+		if msg.Type == graphqlMessageType && msg.ID == id {
+			return msg.Payload
+		}
+	}
+}
+
+func TestWebSocketGraphQLQueriesAndMutations(t *testing.T) {
+	tasks := newFakeTaskService()
+	if _, err := tasks.CreateTask(context.Background(), &proto.CreateTaskRequest{Title: "Page the on-call", Status: "todo"}); err != nil {
+		t.Fatal(err)
+	}
+	ws := dialWebSocket(t, tasks)
+
+	result := graphqlResult(t, ws, "query", `{ task(id: "1") { id title } }`)
+	task, _ := result["data"].(map[string]interface{})["task"].(map[string]interface{})
+	if result["errors"] != nil || task["title"] != "Page the on-call" {
+		t.Errorf("query result = %v", result)
+	}
+
+	result = graphqlResult(t, ws, "mutation", `mutation { createTask(input: {title: "Write the postmortem"}) { id title } }`)
+	task, _ = result["data"].(map[string]interface{})["createTask"].(map[string]interface{})
+	if result["errors"] != nil || task["title"] != "Write the postmortem" {
+		t.Errorf("mutation result = %v", result)
+	}
+}
@@ -0,0 +1,157 @@
+// Package ical writes RFC 5545 calendars of to-dos.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is the media type of iCalendar files.
+const ContentType = "text/calendar; charset=utf-8"
+
+// maxLineOctets is the length at which content lines are folded.
+const maxLineOctets = 75
+
+// Statuses of a to-do.
+const (
+	StatusNeedsAction = "NEEDS-ACTION"
+	StatusInProcess   = "IN-PROCESS"
+	StatusCompleted   = "COMPLETED"
+	StatusCancelled   = "CANCELLED"
+)
+
+// Todo is a VTODO component. Zero fields are left out.
+type Todo struct {
+	UID          string
+	Summary      string
+	Description  string
+	Status       string
+	Priority     int
+	Due          time.Time
+	Created      time.Time
+	LastModified time.Time
+	Categories   []string
+	// RelatedTo is the UID of the parent to-do.
+	RelatedTo string
+}
+
+// Writer writes a VCALENDAR. Errors are kept and returned by Close.
+type Writer struct {
+	w     *bufio.Writer
+	stamp time.Time
+	err   error
+}
+
+// NewWriter starts a calendar named name on w. prodID identifies the
+// producing product.
+func NewWriter(w io.Writer, prodID, name string) *Writer {
+	cw := &Writer{w: bufio.NewWriter(w), stamp: time.Now()}
+	cw.line("BEGIN", "VCALENDAR")
+	cw.line("VERSION", "2.0")
+	cw.line("PRODID", prodID)
+	cw.line("CALSCALE", "GREGORIAN")
+	if name != "" {
+		cw.line("X-WR-CALNAME", Escape(name))
+	}
+	return cw
+}
+
+// WriteTodo writes t as a VTODO.
+func (cw *Writer) WriteTodo(t *Todo) error {
+	cw.line("BEGIN", "VTODO")
+	cw.line("UID", t.UID)
+	stamp := t.LastModified
+	if stamp.IsZero() {
+		stamp = cw.stamp
+	}
+	cw.line("DTSTAMP", formatTime(stamp))
+	cw.line("SUMMARY", Escape(t.Summary))
+	if t.Description != "" {
+		cw.line("DESCRIPTION", Escape(t.Description))
+	}
+	if t.Status != "" {
+		cw.line("STATUS", t.Status)
+	}
+	if t.Priority > 0 {
+		cw.line("PRIORITY", strconv.Itoa(t.Priority))
+	}
+	if !t.Due.IsZero() {
+		cw.line("DUE", formatTime(t.Due))
+	}
+	if !t.Created.IsZero() {
+		cw.line("CREATED", formatTime(t.Created))
+	}
+	if !t.LastModified.IsZero() {
+		cw.line("LAST-MODIFIED", formatTime(t.LastModified))
+	}
+	if len(t.Categories) > 0 {
+		categories := make([]string, len(t.Categories))
+		for i, c := range t.Categories {
+			categories[i] = Escape(c)
+		}
+		cw.line("CATEGORIES", strings.Join(categories, ","))
+	}
+	if t.RelatedTo != "" {
+		cw.line("RELATED-TO;RELTYPE=PARENT", t.RelatedTo)
+	}
+	cw.line("END", "VTODO")
+	return cw.err
+}
+
+// Flush writes buffered data to the underlying writer.
+func (cw *Writer) Flush() error {
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.err
+}
+
+// Close ends the calendar and flushes it.
+func (cw *Writer) Close() error {
+	cw.line("END", "VCALENDAR")
+	return cw.Flush()
+}
+
+// line writes a content line, folding it after 75 octets without splitting
+// UTF-8 sequences.
+func (cw *Writer) line(name, value string) {
+	if cw.err != nil {
+		return
+	}
+	s := name + ":" + value
+	var b strings.Builder
+	for width := maxLineOctets; len(s) > width; width = maxLineOctets - 1 {
+		cut := width
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+	_, cw.err = cw.w.WriteString(b.String())
+}
+
+// Escape escapes a TEXT value.
+func Escape(s string) string {
+	return textEscaper.Replace(s)
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
@@ -1,10 +1,127 @@
 package models
 
+import "time"
+
 type Config struct {
 	GrpcServer *GRPCServer `yaml:"grpcServer"`
+	// HttpServer is the address the REST API is served on, port 50059 when
+	// unset.
+	HttpServer *HTTPServer `yaml:"httpServer"`
+	// GatewayGrpc is the address the gateway serves TaskService on itself.
+	// It is not served when unset.
+	GatewayGrpc *GRPCServer `yaml:"gatewayGrpc"`
+	// Connect configures TaskService over Connect and gRPC-Web on the HTTP
+	// port.
+	Connect     *Connect     `yaml:"connect"`
+	RateLimit   *RateLimit   `yaml:"rateLimit"`
+	Workflow    *Workflow    `yaml:"workflow"`
+	Attachments *Attachments `yaml:"attachments"`
+	Reminders   *Reminders   `yaml:"reminders"`
+	Webhooks    *Webhooks    `yaml:"webhooks"`
+	GitHub      *GitHub      `yaml:"github"`
+	// Admins lists the users allowed to read the audit log and to manage
+	// webhooks.
+	Admins []string `yaml:"admins"`
 }
 
 type GRPCServer struct {
 	Host string `yaml:"host"`
 	Port string `yaml:"port"`
 }
+
+// DefaultHTTPPort serves the REST API when Config.HttpServer has no port.
+const DefaultHTTPPort = "50059"
+
+type HTTPServer struct {
+	Host string `yaml:"host"`
+	Port string `yaml:"port"`
+}
+
+// Connect lists the origins of the browser apps allowed to call
+// TaskService over Connect and gRPC-Web from another origin; "*" allows
+// any. Only same-origin calls are allowed when it is empty.
+type Connect struct {
+	AllowedOrigins []string `yaml:"allowedOrigins"`
+}
+
+// RateLimit limits the requests of every caller of the REST and gRPC APIs
+// to RequestsPerSecond on average, allowing bursts of Burst requests.
+// Callers are told apart by user, or by address when anonymous.
+type RateLimit struct {
+	RequestsPerSecond float64 `yaml:"requestsPerSecond"`
+	Burst             int     `yaml:"burst"`
+}
+
+// Workflow is the task status state machine. Transitions maps every status
+// to the statuses a task may move to from it. Final lists the statuses that
+// count as completed.
+type Workflow struct {
+	Initial     string              `yaml:"initial"`
+	Final       []string            `yaml:"final"`
+	Transitions map[string][]string `yaml:"transitions"`
+}
+
+// Attachments limits uploads and configures where their content is stored.
+// MaxSize is in bytes; AllowedTypes lists the accepted MIME types.
+type Attachments struct {
+	MaxSize      int64     `yaml:"maxSize"`
+	AllowedTypes []string  `yaml:"allowedTypes"`
+	Store        BlobStore `yaml:"store"`
+}
+
+// BlobStore selects the blob store driver. Path is the root directory of
+// the local driver.
+type BlobStore struct {
+	Driver string `yaml:"driver"`
+	Path   string `yaml:"path"`
+}
+
+// Reminders configures the reminder scheduler. PollInterval is the longest
+// the scheduler waits before looking for due reminders again. A channel can
+// only be used by reminders when its sink is configured.
+type Reminders struct {
+	PollInterval time.Duration `yaml:"pollInterval"`
+	Sinks        ReminderSinks `yaml:"sinks"`
+}
+
+type ReminderSinks struct {
+	WebSocket bool         `yaml:"websocket"`
+	Webhook   *WebhookSink `yaml:"webhook"`
+	SMTP      *SMTPSink    `yaml:"smtp"`
+}
+
+// WebhookSink posts reminders to URL. When Secret is set the body is signed
+// with HMAC-SHA256.
+type WebhookSink struct {
+	URL    string `yaml:"url"`
+	Secret string `yaml:"secret"`
+}
+
+// SMTPSink mails reminders. Recipients that are not email addresses are
+// mailed at RecipientDomain. Username and Password are optional.
+type SMTPSink struct {
+	Host            string `yaml:"host"`
+	Port            string `yaml:"port"`
+	From            string `yaml:"from"`
+	Username        string `yaml:"username"`
+	Password        string `yaml:"password"`
+	RecipientDomain string `yaml:"recipientDomain"`
+}
+
+// Webhooks configures the delivery of task events to webhooks. A failed
+// delivery is retried after InitialBackoff, doubling up to MaxBackoff, until
+// MaxAttempts attempts were made. Timeout bounds each attempt.
+type Webhooks struct {
+	PollInterval   time.Duration `yaml:"pollInterval"`
+	MaxAttempts    int           `yaml:"maxAttempts"`
+	InitialBackoff time.Duration `yaml:"initialBackoff"`
+	MaxBackoff     time.Duration `yaml:"maxBackoff"`
+	Timeout        time.Duration `yaml:"timeout"`
+}
+
+// GitHub configures the receiver of GitHub issue and pull request webhooks.
+// Secret is the secret of the webhook on GitHub; the receiver is disabled
+// without it.
+type GitHub struct {
+	Secret string `yaml:"secret"`
+}
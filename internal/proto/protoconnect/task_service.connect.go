@@ -0,0 +1,1391 @@
+// proto/task_service.proto
+
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: internal/proto/task_service.proto
+
+package protoconnect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	proto "github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// TaskServiceName is the fully-qualified name of the TaskService service.
+	TaskServiceName = "task.TaskService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// TaskServiceCreateTaskProcedure is the fully-qualified name of the TaskService's CreateTask RPC.
+	TaskServiceCreateTaskProcedure = "/task.TaskService/CreateTask"
+	// TaskServiceGetTaskProcedure is the fully-qualified name of the TaskService's GetTask RPC.
+	TaskServiceGetTaskProcedure = "/task.TaskService/GetTask"
+	// TaskServiceUpdateTaskProcedure is the fully-qualified name of the TaskService's UpdateTask RPC.
+	TaskServiceUpdateTaskProcedure = "/task.TaskService/UpdateTask"
+	// TaskServiceDeleteTaskProcedure is the fully-qualified name of the TaskService's DeleteTask RPC.
+	TaskServiceDeleteTaskProcedure = "/task.TaskService/DeleteTask"
+	// TaskServiceListTasksProcedure is the fully-qualified name of the TaskService's ListTasks RPC.
+	TaskServiceListTasksProcedure = "/task.TaskService/ListTasks"
+	// TaskServiceSearchTasksProcedure is the fully-qualified name of the TaskService's SearchTasks RPC.
+	TaskServiceSearchTasksProcedure = "/task.TaskService/SearchTasks"
+	// TaskServiceCreateLabelProcedure is the fully-qualified name of the TaskService's CreateLabel RPC.
+	TaskServiceCreateLabelProcedure = "/task.TaskService/CreateLabel"
+	// TaskServiceGetLabelProcedure is the fully-qualified name of the TaskService's GetLabel RPC.
+	TaskServiceGetLabelProcedure = "/task.TaskService/GetLabel"
+	// TaskServiceUpdateLabelProcedure is the fully-qualified name of the TaskService's UpdateLabel RPC.
+	TaskServiceUpdateLabelProcedure = "/task.TaskService/UpdateLabel"
+	// TaskServiceDeleteLabelProcedure is the fully-qualified name of the TaskService's DeleteLabel RPC.
+	TaskServiceDeleteLabelProcedure = "/task.TaskService/DeleteLabel"
+	// TaskServiceListLabelsProcedure is the fully-qualified name of the TaskService's ListLabels RPC.
+	TaskServiceListLabelsProcedure = "/task.TaskService/ListLabels"
+	// TaskServiceAttachLabelsProcedure is the fully-qualified name of the TaskService's AttachLabels
+	// RPC.
+	TaskServiceAttachLabelsProcedure = "/task.TaskService/AttachLabels"
+	// TaskServiceDetachLabelsProcedure is the fully-qualified name of the TaskService's DetachLabels
+	// RPC.
+	TaskServiceDetachLabelsProcedure = "/task.TaskService/DetachLabels"
+	// TaskServiceCreateCommentProcedure is the fully-qualified name of the TaskService's CreateComment
+	// RPC.
+	TaskServiceCreateCommentProcedure = "/task.TaskService/CreateComment"
+	// TaskServiceGetCommentProcedure is the fully-qualified name of the TaskService's GetComment RPC.
+	TaskServiceGetCommentProcedure = "/task.TaskService/GetComment"
+	// TaskServiceUpdateCommentProcedure is the fully-qualified name of the TaskService's UpdateComment
+	// RPC.
+	TaskServiceUpdateCommentProcedure = "/task.TaskService/UpdateComment"
+	// TaskServiceDeleteCommentProcedure is the fully-qualified name of the TaskService's DeleteComment
+	// RPC.
+	TaskServiceDeleteCommentProcedure = "/task.TaskService/DeleteComment"
+	// TaskServiceListCommentsProcedure is the fully-qualified name of the TaskService's ListComments
+	// RPC.
+	TaskServiceListCommentsProcedure = "/task.TaskService/ListComments"
+	// TaskServiceAddAttachmentProcedure is the fully-qualified name of the TaskService's AddAttachment
+	// RPC.
+	TaskServiceAddAttachmentProcedure = "/task.TaskService/AddAttachment"
+	// TaskServiceGetAttachmentProcedure is the fully-qualified name of the TaskService's GetAttachment
+	// RPC.
+	TaskServiceGetAttachmentProcedure = "/task.TaskService/GetAttachment"
+	// TaskServiceDeleteAttachmentProcedure is the fully-qualified name of the TaskService's
+	// DeleteAttachment RPC.
+	TaskServiceDeleteAttachmentProcedure = "/task.TaskService/DeleteAttachment"
+	// TaskServiceListAttachmentsProcedure is the fully-qualified name of the TaskService's
+	// ListAttachments RPC.
+	TaskServiceListAttachmentsProcedure = "/task.TaskService/ListAttachments"
+	// TaskServiceAddDependencyProcedure is the fully-qualified name of the TaskService's AddDependency
+	// RPC.
+	TaskServiceAddDependencyProcedure = "/task.TaskService/AddDependency"
+	// TaskServiceRemoveDependencyProcedure is the fully-qualified name of the TaskService's
+	// RemoveDependency RPC.
+	TaskServiceRemoveDependencyProcedure = "/task.TaskService/RemoveDependency"
+	// TaskServiceCreateReminderProcedure is the fully-qualified name of the TaskService's
+	// CreateReminder RPC.
+	TaskServiceCreateReminderProcedure = "/task.TaskService/CreateReminder"
+	// TaskServiceGetReminderProcedure is the fully-qualified name of the TaskService's GetReminder RPC.
+	TaskServiceGetReminderProcedure = "/task.TaskService/GetReminder"
+	// TaskServiceDeleteReminderProcedure is the fully-qualified name of the TaskService's
+	// DeleteReminder RPC.
+	TaskServiceDeleteReminderProcedure = "/task.TaskService/DeleteReminder"
+	// TaskServiceListRemindersProcedure is the fully-qualified name of the TaskService's ListReminders
+	// RPC.
+	TaskServiceListRemindersProcedure = "/task.TaskService/ListReminders"
+	// TaskServiceMarkReminderFiredProcedure is the fully-qualified name of the TaskService's
+	// MarkReminderFired RPC.
+	TaskServiceMarkReminderFiredProcedure = "/task.TaskService/MarkReminderFired"
+	// TaskServiceRecordActivityProcedure is the fully-qualified name of the TaskService's
+	// RecordActivity RPC.
+	TaskServiceRecordActivityProcedure = "/task.TaskService/RecordActivity"
+	// TaskServiceListActivityProcedure is the fully-qualified name of the TaskService's ListActivity
+	// RPC.
+	TaskServiceListActivityProcedure = "/task.TaskService/ListActivity"
+	// TaskServiceCreateFeedTokenProcedure is the fully-qualified name of the TaskService's
+	// CreateFeedToken RPC.
+	TaskServiceCreateFeedTokenProcedure = "/task.TaskService/CreateFeedToken"
+	// TaskServiceGetFeedTokenProcedure is the fully-qualified name of the TaskService's GetFeedToken
+	// RPC.
+	TaskServiceGetFeedTokenProcedure = "/task.TaskService/GetFeedToken"
+	// TaskServiceDeleteFeedTokenProcedure is the fully-qualified name of the TaskService's
+	// DeleteFeedToken RPC.
+	TaskServiceDeleteFeedTokenProcedure = "/task.TaskService/DeleteFeedToken"
+	// TaskServiceListFeedTokensProcedure is the fully-qualified name of the TaskService's
+	// ListFeedTokens RPC.
+	TaskServiceListFeedTokensProcedure = "/task.TaskService/ListFeedTokens"
+	// TaskServiceCreateWebhookProcedure is the fully-qualified name of the TaskService's CreateWebhook
+	// RPC.
+	TaskServiceCreateWebhookProcedure = "/task.TaskService/CreateWebhook"
+	// TaskServiceGetWebhookProcedure is the fully-qualified name of the TaskService's GetWebhook RPC.
+	TaskServiceGetWebhookProcedure = "/task.TaskService/GetWebhook"
+	// TaskServiceUpdateWebhookProcedure is the fully-qualified name of the TaskService's UpdateWebhook
+	// RPC.
+	TaskServiceUpdateWebhookProcedure = "/task.TaskService/UpdateWebhook"
+	// TaskServiceDeleteWebhookProcedure is the fully-qualified name of the TaskService's DeleteWebhook
+	// RPC.
+	TaskServiceDeleteWebhookProcedure = "/task.TaskService/DeleteWebhook"
+	// TaskServiceListWebhooksProcedure is the fully-qualified name of the TaskService's ListWebhooks
+	// RPC.
+	TaskServiceListWebhooksProcedure = "/task.TaskService/ListWebhooks"
+	// TaskServiceCreateWebhookDeliveryProcedure is the fully-qualified name of the TaskService's
+	// CreateWebhookDelivery RPC.
+	TaskServiceCreateWebhookDeliveryProcedure = "/task.TaskService/CreateWebhookDelivery"
+	// TaskServiceGetWebhookDeliveryProcedure is the fully-qualified name of the TaskService's
+	// GetWebhookDelivery RPC.
+	TaskServiceGetWebhookDeliveryProcedure = "/task.TaskService/GetWebhookDelivery"
+	// TaskServiceUpdateWebhookDeliveryProcedure is the fully-qualified name of the TaskService's
+	// UpdateWebhookDelivery RPC.
+	TaskServiceUpdateWebhookDeliveryProcedure = "/task.TaskService/UpdateWebhookDelivery"
+	// TaskServiceListWebhookDeliveriesProcedure is the fully-qualified name of the TaskService's
+	// ListWebhookDeliveries RPC.
+	TaskServiceListWebhookDeliveriesProcedure = "/task.TaskService/ListWebhookDeliveries"
+)
+
+// These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
+var (
+	taskServiceServiceDescriptor                     = proto.File_internal_proto_task_service_proto.Services().ByName("TaskService")
+	taskServiceCreateTaskMethodDescriptor            = taskServiceServiceDescriptor.Methods().ByName("CreateTask")
+	taskServiceGetTaskMethodDescriptor               = taskServiceServiceDescriptor.Methods().ByName("GetTask")
+	taskServiceUpdateTaskMethodDescriptor            = taskServiceServiceDescriptor.Methods().ByName("UpdateTask")
+	taskServiceDeleteTaskMethodDescriptor            = taskServiceServiceDescriptor.Methods().ByName("DeleteTask")
+	taskServiceListTasksMethodDescriptor             = taskServiceServiceDescriptor.Methods().ByName("ListTasks")
+	taskServiceSearchTasksMethodDescriptor           = taskServiceServiceDescriptor.Methods().ByName("SearchTasks")
+	taskServiceCreateLabelMethodDescriptor           = taskServiceServiceDescriptor.Methods().ByName("CreateLabel")
+	taskServiceGetLabelMethodDescriptor              = taskServiceServiceDescriptor.Methods().ByName("GetLabel")
+	taskServiceUpdateLabelMethodDescriptor           = taskServiceServiceDescriptor.Methods().ByName("UpdateLabel")
+	taskServiceDeleteLabelMethodDescriptor           = taskServiceServiceDescriptor.Methods().ByName("DeleteLabel")
+	taskServiceListLabelsMethodDescriptor            = taskServiceServiceDescriptor.Methods().ByName("ListLabels")
+	taskServiceAttachLabelsMethodDescriptor          = taskServiceServiceDescriptor.Methods().ByName("AttachLabels")
+	taskServiceDetachLabelsMethodDescriptor          = taskServiceServiceDescriptor.Methods().ByName("DetachLabels")
+	taskServiceCreateCommentMethodDescriptor         = taskServiceServiceDescriptor.Methods().ByName("CreateComment")
+	taskServiceGetCommentMethodDescriptor            = taskServiceServiceDescriptor.Methods().ByName("GetComment")
+	taskServiceUpdateCommentMethodDescriptor         = taskServiceServiceDescriptor.Methods().ByName("UpdateComment")
+	taskServiceDeleteCommentMethodDescriptor         = taskServiceServiceDescriptor.Methods().ByName("DeleteComment")
+	taskServiceListCommentsMethodDescriptor          = taskServiceServiceDescriptor.Methods().ByName("ListComments")
+	taskServiceAddAttachmentMethodDescriptor         = taskServiceServiceDescriptor.Methods().ByName("AddAttachment")
+	taskServiceGetAttachmentMethodDescriptor         = taskServiceServiceDescriptor.Methods().ByName("GetAttachment")
+	taskServiceDeleteAttachmentMethodDescriptor      = taskServiceServiceDescriptor.Methods().ByName("DeleteAttachment")
+	taskServiceListAttachmentsMethodDescriptor       = taskServiceServiceDescriptor.Methods().ByName("ListAttachments")
+	taskServiceAddDependencyMethodDescriptor         = taskServiceServiceDescriptor.Methods().ByName("AddDependency")
+	taskServiceRemoveDependencyMethodDescriptor      = taskServiceServiceDescriptor.Methods().ByName("RemoveDependency")
+	taskServiceCreateReminderMethodDescriptor        = taskServiceServiceDescriptor.Methods().ByName("CreateReminder")
+	taskServiceGetReminderMethodDescriptor           = taskServiceServiceDescriptor.Methods().ByName("GetReminder")
+	taskServiceDeleteReminderMethodDescriptor        = taskServiceServiceDescriptor.Methods().ByName("DeleteReminder")
+	taskServiceListRemindersMethodDescriptor         = taskServiceServiceDescriptor.Methods().ByName("ListReminders")
+	taskServiceMarkReminderFiredMethodDescriptor     = taskServiceServiceDescriptor.Methods().ByName("MarkReminderFired")
+	taskServiceRecordActivityMethodDescriptor        = taskServiceServiceDescriptor.Methods().ByName("RecordActivity")
+	taskServiceListActivityMethodDescriptor          = taskServiceServiceDescriptor.Methods().ByName("ListActivity")
+	taskServiceCreateFeedTokenMethodDescriptor       = taskServiceServiceDescriptor.Methods().ByName("CreateFeedToken")
+	taskServiceGetFeedTokenMethodDescriptor          = taskServiceServiceDescriptor.Methods().ByName("GetFeedToken")
+	taskServiceDeleteFeedTokenMethodDescriptor       = taskServiceServiceDescriptor.Methods().ByName("DeleteFeedToken")
+	taskServiceListFeedTokensMethodDescriptor        = taskServiceServiceDescriptor.Methods().ByName("ListFeedTokens")
+	taskServiceCreateWebhookMethodDescriptor         = taskServiceServiceDescriptor.Methods().ByName("CreateWebhook")
+	taskServiceGetWebhookMethodDescriptor            = taskServiceServiceDescriptor.Methods().ByName("GetWebhook")
+	taskServiceUpdateWebhookMethodDescriptor         = taskServiceServiceDescriptor.Methods().ByName("UpdateWebhook")
+	taskServiceDeleteWebhookMethodDescriptor         = taskServiceServiceDescriptor.Methods().ByName("DeleteWebhook")
+	taskServiceListWebhooksMethodDescriptor          = taskServiceServiceDescriptor.Methods().ByName("ListWebhooks")
+	taskServiceCreateWebhookDeliveryMethodDescriptor = taskServiceServiceDescriptor.Methods().ByName("CreateWebhookDelivery")
+	taskServiceGetWebhookDeliveryMethodDescriptor    = taskServiceServiceDescriptor.Methods().ByName("GetWebhookDelivery")
+	taskServiceUpdateWebhookDeliveryMethodDescriptor = taskServiceServiceDescriptor.Methods().ByName("UpdateWebhookDelivery")
+	taskServiceListWebhookDeliveriesMethodDescriptor = taskServiceServiceDescriptor.Methods().ByName("ListWebhookDeliveries")
+)
+
+// TaskServiceClient is a client for the task.TaskService service.
+type TaskServiceClient interface {
+	CreateTask(context.Context, *connect.Request[proto.CreateTaskRequest]) (*connect.Response[proto.CreateTaskResponse], error)
+	GetTask(context.Context, *connect.Request[proto.GetTaskRequest]) (*connect.Response[proto.Task], error)
+	UpdateTask(context.Context, *connect.Request[proto.UpdateTaskRequest]) (*connect.Response[proto.UpdateTaskResponse], error)
+	DeleteTask(context.Context, *connect.Request[proto.DeleteTaskRequest]) (*connect.Response[proto.DeleteTaskResponse], error)
+	ListTasks(context.Context, *connect.Request[proto.ListTasksRequest]) (*connect.Response[proto.ListTasksResponse], error)
+	SearchTasks(context.Context, *connect.Request[proto.SearchTasksRequest]) (*connect.Response[proto.SearchTasksResponse], error)
+	CreateLabel(context.Context, *connect.Request[proto.CreateLabelRequest]) (*connect.Response[proto.Label], error)
+	GetLabel(context.Context, *connect.Request[proto.GetLabelRequest]) (*connect.Response[proto.Label], error)
+	UpdateLabel(context.Context, *connect.Request[proto.UpdateLabelRequest]) (*connect.Response[proto.Label], error)
+	DeleteLabel(context.Context, *connect.Request[proto.DeleteLabelRequest]) (*connect.Response[proto.DeleteLabelResponse], error)
+	ListLabels(context.Context, *connect.Request[proto.ListLabelsRequest]) (*connect.Response[proto.ListLabelsResponse], error)
+	AttachLabels(context.Context, *connect.Request[proto.TaskLabelsRequest]) (*connect.Response[proto.TaskLabelsResponse], error)
+	DetachLabels(context.Context, *connect.Request[proto.TaskLabelsRequest]) (*connect.Response[proto.TaskLabelsResponse], error)
+	CreateComment(context.Context, *connect.Request[proto.CreateCommentRequest]) (*connect.Response[proto.Comment], error)
+	GetComment(context.Context, *connect.Request[proto.GetCommentRequest]) (*connect.Response[proto.Comment], error)
+	UpdateComment(context.Context, *connect.Request[proto.UpdateCommentRequest]) (*connect.Response[proto.Comment], error)
+	DeleteComment(context.Context, *connect.Request[proto.DeleteCommentRequest]) (*connect.Response[proto.DeleteCommentResponse], error)
+	ListComments(context.Context, *connect.Request[proto.ListCommentsRequest]) (*connect.Response[proto.ListCommentsResponse], error)
+	AddAttachment(context.Context, *connect.Request[proto.AddAttachmentRequest]) (*connect.Response[proto.Attachment], error)
+	GetAttachment(context.Context, *connect.Request[proto.GetAttachmentRequest]) (*connect.Response[proto.Attachment], error)
+	DeleteAttachment(context.Context, *connect.Request[proto.DeleteAttachmentRequest]) (*connect.Response[proto.DeleteAttachmentResponse], error)
+	ListAttachments(context.Context, *connect.Request[proto.ListAttachmentsRequest]) (*connect.Response[proto.ListAttachmentsResponse], error)
+	AddDependency(context.Context, *connect.Request[proto.TaskDependencyRequest]) (*connect.Response[proto.TaskDependenciesResponse], error)
+	RemoveDependency(context.Context, *connect.Request[proto.TaskDependencyRequest]) (*connect.Response[proto.TaskDependenciesResponse], error)
+	CreateReminder(context.Context, *connect.Request[proto.CreateReminderRequest]) (*connect.Response[proto.Reminder], error)
+	GetReminder(context.Context, *connect.Request[proto.GetReminderRequest]) (*connect.Response[proto.Reminder], error)
+	DeleteReminder(context.Context, *connect.Request[proto.DeleteReminderRequest]) (*connect.Response[proto.DeleteReminderResponse], error)
+	ListReminders(context.Context, *connect.Request[proto.ListRemindersRequest]) (*connect.Response[proto.ListRemindersResponse], error)
+	MarkReminderFired(context.Context, *connect.Request[proto.MarkReminderFiredRequest]) (*connect.Response[proto.Reminder], error)
+	RecordActivity(context.Context, *connect.Request[proto.RecordActivityRequest]) (*connect.Response[proto.Activity], error)
+	ListActivity(context.Context, *connect.Request[proto.ListActivityRequest]) (*connect.Response[proto.ListActivityResponse], error)
+	CreateFeedToken(context.Context, *connect.Request[proto.CreateFeedTokenRequest]) (*connect.Response[proto.FeedToken], error)
+	GetFeedToken(context.Context, *connect.Request[proto.GetFeedTokenRequest]) (*connect.Response[proto.FeedToken], error)
+	DeleteFeedToken(context.Context, *connect.Request[proto.DeleteFeedTokenRequest]) (*connect.Response[proto.DeleteFeedTokenResponse], error)
+	ListFeedTokens(context.Context, *connect.Request[proto.ListFeedTokensRequest]) (*connect.Response[proto.ListFeedTokensResponse], error)
+	CreateWebhook(context.Context, *connect.Request[proto.CreateWebhookRequest]) (*connect.Response[proto.Webhook], error)
+	GetWebhook(context.Context, *connect.Request[proto.GetWebhookRequest]) (*connect.Response[proto.Webhook], error)
+	UpdateWebhook(context.Context, *connect.Request[proto.UpdateWebhookRequest]) (*connect.Response[proto.Webhook], error)
+	DeleteWebhook(context.Context, *connect.Request[proto.DeleteWebhookRequest]) (*connect.Response[proto.DeleteWebhookResponse], error)
+	ListWebhooks(context.Context, *connect.Request[proto.ListWebhooksRequest]) (*connect.Response[proto.ListWebhooksResponse], error)
+	CreateWebhookDelivery(context.Context, *connect.Request[proto.CreateWebhookDeliveryRequest]) (*connect.Response[proto.WebhookDelivery], error)
+	GetWebhookDelivery(context.Context, *connect.Request[proto.GetWebhookDeliveryRequest]) (*connect.Response[proto.WebhookDelivery], error)
+	UpdateWebhookDelivery(context.Context, *connect.Request[proto.UpdateWebhookDeliveryRequest]) (*connect.Response[proto.WebhookDelivery], error)
+	ListWebhookDeliveries(context.Context, *connect.Request[proto.ListWebhookDeliveriesRequest]) (*connect.Response[proto.ListWebhookDeliveriesResponse], error)
+}
+
+// NewTaskServiceClient constructs a client for the task.TaskService service. By default, it uses
+// the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewTaskServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) TaskServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &taskServiceClient{
+		createTask: connect.NewClient[proto.CreateTaskRequest, proto.CreateTaskResponse](
+			httpClient,
+			baseURL+TaskServiceCreateTaskProcedure,
+			connect.WithSchema(taskServiceCreateTaskMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		getTask: connect.NewClient[proto.GetTaskRequest, proto.Task](
+			httpClient,
+			baseURL+TaskServiceGetTaskProcedure,
+			connect.WithSchema(taskServiceGetTaskMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		updateTask: connect.NewClient[proto.UpdateTaskRequest, proto.UpdateTaskResponse](
+			httpClient,
+			baseURL+TaskServiceUpdateTaskProcedure,
+			connect.WithSchema(taskServiceUpdateTaskMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		deleteTask: connect.NewClient[proto.DeleteTaskRequest, proto.DeleteTaskResponse](
+			httpClient,
+			baseURL+TaskServiceDeleteTaskProcedure,
+			connect.WithSchema(taskServiceDeleteTaskMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		listTasks: connect.NewClient[proto.ListTasksRequest, proto.ListTasksResponse](
+			httpClient,
+			baseURL+TaskServiceListTasksProcedure,
+			connect.WithSchema(taskServiceListTasksMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		searchTasks: connect.NewClient[proto.SearchTasksRequest, proto.SearchTasksResponse](
+			httpClient,
+			baseURL+TaskServiceSearchTasksProcedure,
+			connect.WithSchema(taskServiceSearchTasksMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		createLabel: connect.NewClient[proto.CreateLabelRequest, proto.Label](
+			httpClient,
+			baseURL+TaskServiceCreateLabelProcedure,
+			connect.WithSchema(taskServiceCreateLabelMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		getLabel: connect.NewClient[proto.GetLabelRequest, proto.Label](
+			httpClient,
+			baseURL+TaskServiceGetLabelProcedure,
+			connect.WithSchema(taskServiceGetLabelMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		updateLabel: connect.NewClient[proto.UpdateLabelRequest, proto.Label](
+			httpClient,
+			baseURL+TaskServiceUpdateLabelProcedure,
+			connect.WithSchema(taskServiceUpdateLabelMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		deleteLabel: connect.NewClient[proto.DeleteLabelRequest, proto.DeleteLabelResponse](
+			httpClient,
+			baseURL+TaskServiceDeleteLabelProcedure,
+			connect.WithSchema(taskServiceDeleteLabelMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		listLabels: connect.NewClient[proto.ListLabelsRequest, proto.ListLabelsResponse](
+			httpClient,
+			baseURL+TaskServiceListLabelsProcedure,
+			connect.WithSchema(taskServiceListLabelsMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		attachLabels: connect.NewClient[proto.TaskLabelsRequest, proto.TaskLabelsResponse](
+			httpClient,
+			baseURL+TaskServiceAttachLabelsProcedure,
+			connect.WithSchema(taskServiceAttachLabelsMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		detachLabels: connect.NewClient[proto.TaskLabelsRequest, proto.TaskLabelsResponse](
+			httpClient,
+			baseURL+TaskServiceDetachLabelsProcedure,
+			connect.WithSchema(taskServiceDetachLabelsMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		createComment: connect.NewClient[proto.CreateCommentRequest, proto.Comment](
+			httpClient,
+			baseURL+TaskServiceCreateCommentProcedure,
+			connect.WithSchema(taskServiceCreateCommentMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		getComment: connect.NewClient[proto.GetCommentRequest, proto.Comment](
+			httpClient,
+			baseURL+TaskServiceGetCommentProcedure,
+			connect.WithSchema(taskServiceGetCommentMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		updateComment: connect.NewClient[proto.UpdateCommentRequest, proto.Comment](
+			httpClient,
+			baseURL+TaskServiceUpdateCommentProcedure,
+			connect.WithSchema(taskServiceUpdateCommentMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		deleteComment: connect.NewClient[proto.DeleteCommentRequest, proto.DeleteCommentResponse](
+			httpClient,
+			baseURL+TaskServiceDeleteCommentProcedure,
+			connect.WithSchema(taskServiceDeleteCommentMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		listComments: connect.NewClient[proto.ListCommentsRequest, proto.ListCommentsResponse](
+			httpClient,
+			baseURL+TaskServiceListCommentsProcedure,
+			connect.WithSchema(taskServiceListCommentsMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		addAttachment: connect.NewClient[proto.AddAttachmentRequest, proto.Attachment](
+			httpClient,
+			baseURL+TaskServiceAddAttachmentProcedure,
+			connect.WithSchema(taskServiceAddAttachmentMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		getAttachment: connect.NewClient[proto.GetAttachmentRequest, proto.Attachment](
+			httpClient,
+			baseURL+TaskServiceGetAttachmentProcedure,
+			connect.WithSchema(taskServiceGetAttachmentMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		deleteAttachment: connect.NewClient[proto.DeleteAttachmentRequest, proto.DeleteAttachmentResponse](
+			httpClient,
+			baseURL+TaskServiceDeleteAttachmentProcedure,
+			connect.WithSchema(taskServiceDeleteAttachmentMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		listAttachments: connect.NewClient[proto.ListAttachmentsRequest, proto.ListAttachmentsResponse](
+			httpClient,
+			baseURL+TaskServiceListAttachmentsProcedure,
+			connect.WithSchema(taskServiceListAttachmentsMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		addDependency: connect.NewClient[proto.TaskDependencyRequest, proto.TaskDependenciesResponse](
+			httpClient,
+			baseURL+TaskServiceAddDependencyProcedure,
+			connect.WithSchema(taskServiceAddDependencyMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		removeDependency: connect.NewClient[proto.TaskDependencyRequest, proto.TaskDependenciesResponse](
+			httpClient,
+			baseURL+TaskServiceRemoveDependencyProcedure,
+			connect.WithSchema(taskServiceRemoveDependencyMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		createReminder: connect.NewClient[proto.CreateReminderRequest, proto.Reminder](
+			httpClient,
+			baseURL+TaskServiceCreateReminderProcedure,
+			connect.WithSchema(taskServiceCreateReminderMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		getReminder: connect.NewClient[proto.GetReminderRequest, proto.Reminder](
+			httpClient,
+			baseURL+TaskServiceGetReminderProcedure,
+			connect.WithSchema(taskServiceGetReminderMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		deleteReminder: connect.NewClient[proto.DeleteReminderRequest, proto.DeleteReminderResponse](
+			httpClient,
+			baseURL+TaskServiceDeleteReminderProcedure,
+			connect.WithSchema(taskServiceDeleteReminderMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		listReminders: connect.NewClient[proto.ListRemindersRequest, proto.ListRemindersResponse](
+			httpClient,
+			baseURL+TaskServiceListRemindersProcedure,
+			connect.WithSchema(taskServiceListRemindersMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		markReminderFired: connect.NewClient[proto.MarkReminderFiredRequest, proto.Reminder](
+			httpClient,
+			baseURL+TaskServiceMarkReminderFiredProcedure,
+			connect.WithSchema(taskServiceMarkReminderFiredMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		recordActivity: connect.NewClient[proto.RecordActivityRequest, proto.Activity](
+			httpClient,
+			baseURL+TaskServiceRecordActivityProcedure,
+			connect.WithSchema(taskServiceRecordActivityMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		listActivity: connect.NewClient[proto.ListActivityRequest, proto.ListActivityResponse](
+			httpClient,
+			baseURL+TaskServiceListActivityProcedure,
+			connect.WithSchema(taskServiceListActivityMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		createFeedToken: connect.NewClient[proto.CreateFeedTokenRequest, proto.FeedToken](
+			httpClient,
+			baseURL+TaskServiceCreateFeedTokenProcedure,
+			connect.WithSchema(taskServiceCreateFeedTokenMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		getFeedToken: connect.NewClient[proto.GetFeedTokenRequest, proto.FeedToken](
+			httpClient,
+			baseURL+TaskServiceGetFeedTokenProcedure,
+			connect.WithSchema(taskServiceGetFeedTokenMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		deleteFeedToken: connect.NewClient[proto.DeleteFeedTokenRequest, proto.DeleteFeedTokenResponse](
+			httpClient,
+			baseURL+TaskServiceDeleteFeedTokenProcedure,
+			connect.WithSchema(taskServiceDeleteFeedTokenMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		listFeedTokens: connect.NewClient[proto.ListFeedTokensRequest, proto.ListFeedTokensResponse](
+			httpClient,
+			baseURL+TaskServiceListFeedTokensProcedure,
+			connect.WithSchema(taskServiceListFeedTokensMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		createWebhook: connect.NewClient[proto.CreateWebhookRequest, proto.Webhook](
+			httpClient,
+			baseURL+TaskServiceCreateWebhookProcedure,
+			connect.WithSchema(taskServiceCreateWebhookMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		getWebhook: connect.NewClient[proto.GetWebhookRequest, proto.Webhook](
+			httpClient,
+			baseURL+TaskServiceGetWebhookProcedure,
+			connect.WithSchema(taskServiceGetWebhookMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		updateWebhook: connect.NewClient[proto.UpdateWebhookRequest, proto.Webhook](
+			httpClient,
+			baseURL+TaskServiceUpdateWebhookProcedure,
+			connect.WithSchema(taskServiceUpdateWebhookMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		deleteWebhook: connect.NewClient[proto.DeleteWebhookRequest, proto.DeleteWebhookResponse](
+			httpClient,
+			baseURL+TaskServiceDeleteWebhookProcedure,
+			connect.WithSchema(taskServiceDeleteWebhookMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		listWebhooks: connect.NewClient[proto.ListWebhooksRequest, proto.ListWebhooksResponse](
+			httpClient,
+			baseURL+TaskServiceListWebhooksProcedure,
+			connect.WithSchema(taskServiceListWebhooksMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		createWebhookDelivery: connect.NewClient[proto.CreateWebhookDeliveryRequest, proto.WebhookDelivery](
+			httpClient,
+			baseURL+TaskServiceCreateWebhookDeliveryProcedure,
+			connect.WithSchema(taskServiceCreateWebhookDeliveryMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		getWebhookDelivery: connect.NewClient[proto.GetWebhookDeliveryRequest, proto.WebhookDelivery](
+			httpClient,
+			baseURL+TaskServiceGetWebhookDeliveryProcedure,
+			connect.WithSchema(taskServiceGetWebhookDeliveryMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		updateWebhookDelivery: connect.NewClient[proto.UpdateWebhookDeliveryRequest, proto.WebhookDelivery](
+			httpClient,
+			baseURL+TaskServiceUpdateWebhookDeliveryProcedure,
+			connect.WithSchema(taskServiceUpdateWebhookDeliveryMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		listWebhookDeliveries: connect.NewClient[proto.ListWebhookDeliveriesRequest, proto.ListWebhookDeliveriesResponse](
+			httpClient,
+			baseURL+TaskServiceListWebhookDeliveriesProcedure,
+			connect.WithSchema(taskServiceListWebhookDeliveriesMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// taskServiceClient implements TaskServiceClient.
+type taskServiceClient struct {
+	createTask            *connect.Client[proto.CreateTaskRequest, proto.CreateTaskResponse]
+	getTask               *connect.Client[proto.GetTaskRequest, proto.Task]
+	updateTask            *connect.Client[proto.UpdateTaskRequest, proto.UpdateTaskResponse]
+	deleteTask            *connect.Client[proto.DeleteTaskRequest, proto.DeleteTaskResponse]
+	listTasks             *connect.Client[proto.ListTasksRequest, proto.ListTasksResponse]
+	searchTasks           *connect.Client[proto.SearchTasksRequest, proto.SearchTasksResponse]
+	createLabel           *connect.Client[proto.CreateLabelRequest, proto.Label]
+	getLabel              *connect.Client[proto.GetLabelRequest, proto.Label]
+	updateLabel           *connect.Client[proto.UpdateLabelRequest, proto.Label]
+	deleteLabel           *connect.Client[proto.DeleteLabelRequest, proto.DeleteLabelResponse]
+	listLabels            *connect.Client[proto.ListLabelsRequest, proto.ListLabelsResponse]
+	attachLabels          *connect.Client[proto.TaskLabelsRequest, proto.TaskLabelsResponse]
+	detachLabels          *connect.Client[proto.TaskLabelsRequest, proto.TaskLabelsResponse]
+	createComment         *connect.Client[proto.CreateCommentRequest, proto.Comment]
+	getComment            *connect.Client[proto.GetCommentRequest, proto.Comment]
+	updateComment         *connect.Client[proto.UpdateCommentRequest, proto.Comment]
+	deleteComment         *connect.Client[proto.DeleteCommentRequest, proto.DeleteCommentResponse]
+	listComments          *connect.Client[proto.ListCommentsRequest, proto.ListCommentsResponse]
+	addAttachment         *connect.Client[proto.AddAttachmentRequest, proto.Attachment]
+	getAttachment         *connect.Client[proto.GetAttachmentRequest, proto.Attachment]
+	deleteAttachment      *connect.Client[proto.DeleteAttachmentRequest, proto.DeleteAttachmentResponse]
+	listAttachments       *connect.Client[proto.ListAttachmentsRequest, proto.ListAttachmentsResponse]
+	addDependency         *connect.Client[proto.TaskDependencyRequest, proto.TaskDependenciesResponse]
+	removeDependency      *connect.Client[proto.TaskDependencyRequest, proto.TaskDependenciesResponse]
+	createReminder        *connect.Client[proto.CreateReminderRequest, proto.Reminder]
+	getReminder           *connect.Client[proto.GetReminderRequest, proto.Reminder]
+	deleteReminder        *connect.Client[proto.DeleteReminderRequest, proto.DeleteReminderResponse]
+	listReminders         *connect.Client[proto.ListRemindersRequest, proto.ListRemindersResponse]
+	markReminderFired     *connect.Client[proto.MarkReminderFiredRequest, proto.Reminder]
+	recordActivity        *connect.Client[proto.RecordActivityRequest, proto.Activity]
+	listActivity          *connect.Client[proto.ListActivityRequest, proto.ListActivityResponse]
+	createFeedToken       *connect.Client[proto.CreateFeedTokenRequest, proto.FeedToken]
+	getFeedToken          *connect.Client[proto.GetFeedTokenRequest, proto.FeedToken]
+	deleteFeedToken       *connect.Client[proto.DeleteFeedTokenRequest, proto.DeleteFeedTokenResponse]
+	listFeedTokens        *connect.Client[proto.ListFeedTokensRequest, proto.ListFeedTokensResponse]
+	createWebhook         *connect.Client[proto.CreateWebhookRequest, proto.Webhook]
+	getWebhook            *connect.Client[proto.GetWebhookRequest, proto.Webhook]
+	updateWebhook         *connect.Client[proto.UpdateWebhookRequest, proto.Webhook]
+	deleteWebhook         *connect.Client[proto.DeleteWebhookRequest, proto.DeleteWebhookResponse]
+	listWebhooks          *connect.Client[proto.ListWebhooksRequest, proto.ListWebhooksResponse]
+	createWebhookDelivery *connect.Client[proto.CreateWebhookDeliveryRequest, proto.WebhookDelivery]
+	getWebhookDelivery    *connect.Client[proto.GetWebhookDeliveryRequest, proto.WebhookDelivery]
+	updateWebhookDelivery *connect.Client[proto.UpdateWebhookDeliveryRequest, proto.WebhookDelivery]
+	listWebhookDeliveries *connect.Client[proto.ListWebhookDeliveriesRequest, proto.ListWebhookDeliveriesResponse]
+}
+
+// CreateTask calls task.TaskService.CreateTask.
+func (c *taskServiceClient) CreateTask(ctx context.Context, req *connect.Request[proto.CreateTaskRequest]) (*connect.Response[proto.CreateTaskResponse], error) {
+	return c.createTask.CallUnary(ctx, req)
+}
+
+// GetTask calls task.TaskService.GetTask.
+func (c *taskServiceClient) GetTask(ctx context.Context, req *connect.Request[proto.GetTaskRequest]) (*connect.Response[proto.Task], error) {
+	return c.getTask.CallUnary(ctx, req)
+}
+
+// UpdateTask calls task.TaskService.UpdateTask.
+func (c *taskServiceClient) UpdateTask(ctx context.Context, req *connect.Request[proto.UpdateTaskRequest]) (*connect.Response[proto.UpdateTaskResponse], error) {
+	return c.updateTask.CallUnary(ctx, req)
+}
+
+// DeleteTask calls task.TaskService.DeleteTask.
+func (c *taskServiceClient) DeleteTask(ctx context.Context, req *connect.Request[proto.DeleteTaskRequest]) (*connect.Response[proto.DeleteTaskResponse], error) {
+	return c.deleteTask.CallUnary(ctx, req)
+}
+
+// ListTasks calls task.TaskService.ListTasks.
+func (c *taskServiceClient) ListTasks(ctx context.Context, req *connect.Request[proto.ListTasksRequest]) (*connect.Response[proto.ListTasksResponse], error) {
+	return c.listTasks.CallUnary(ctx, req)
+}
+
+// SearchTasks calls task.TaskService.SearchTasks.
+func (c *taskServiceClient) SearchTasks(ctx context.Context, req *connect.Request[proto.SearchTasksRequest]) (*connect.Response[proto.SearchTasksResponse], error) {
+	return c.searchTasks.CallUnary(ctx, req)
+}
+
+// CreateLabel calls task.TaskService.CreateLabel.
+func (c *taskServiceClient) CreateLabel(ctx context.Context, req *connect.Request[proto.CreateLabelRequest]) (*connect.Response[proto.Label], error) {
+	return c.createLabel.CallUnary(ctx, req)
+}
+
+// GetLabel calls task.TaskService.GetLabel.
+func (c *taskServiceClient) GetLabel(ctx context.Context, req *connect.Request[proto.GetLabelRequest]) (*connect.Response[proto.Label], error) {
+	return c.getLabel.CallUnary(ctx, req)
+}
+
+// UpdateLabel calls task.TaskService.UpdateLabel.
+func (c *taskServiceClient) UpdateLabel(ctx context.Context, req *connect.Request[proto.UpdateLabelRequest]) (*connect.Response[proto.Label], error) {
+	return c.updateLabel.CallUnary(ctx, req)
+}
+
+// DeleteLabel calls task.TaskService.DeleteLabel.
+func (c *taskServiceClient) DeleteLabel(ctx context.Context, req *connect.Request[proto.DeleteLabelRequest]) (*connect.Response[proto.DeleteLabelResponse], error) {
+	return c.deleteLabel.CallUnary(ctx, req)
+}
+
+// ListLabels calls task.TaskService.ListLabels.
+func (c *taskServiceClient) ListLabels(ctx context.Context, req *connect.Request[proto.ListLabelsRequest]) (*connect.Response[proto.ListLabelsResponse], error) {
+	return c.listLabels.CallUnary(ctx, req)
+}
+
+// AttachLabels calls task.TaskService.AttachLabels.
+func (c *taskServiceClient) AttachLabels(ctx context.Context, req *connect.Request[proto.TaskLabelsRequest]) (*connect.Response[proto.TaskLabelsResponse], error) {
+	return c.attachLabels.CallUnary(ctx, req)
+}
+
+// DetachLabels calls task.TaskService.DetachLabels.
+func (c *taskServiceClient) DetachLabels(ctx context.Context, req *connect.Request[proto.TaskLabelsRequest]) (*connect.Response[proto.TaskLabelsResponse], error) {
+	return c.detachLabels.CallUnary(ctx, req)
+}
+
+// CreateComment calls task.TaskService.CreateComment.
+func (c *taskServiceClient) CreateComment(ctx context.Context, req *connect.Request[proto.CreateCommentRequest]) (*connect.Response[proto.Comment], error) {
+	return c.createComment.CallUnary(ctx, req)
+}
+
+// GetComment calls task.TaskService.GetComment.
+func (c *taskServiceClient) GetComment(ctx context.Context, req *connect.Request[proto.GetCommentRequest]) (*connect.Response[proto.Comment], error) {
+	return c.getComment.CallUnary(ctx, req)
+}
+
+// UpdateComment calls task.TaskService.UpdateComment.
+func (c *taskServiceClient) UpdateComment(ctx context.Context, req *connect.Request[proto.UpdateCommentRequest]) (*connect.Response[proto.Comment], error) {
+	return c.updateComment.CallUnary(ctx, req)
+}
+
+// DeleteComment calls task.TaskService.DeleteComment.
+func (c *taskServiceClient) DeleteComment(ctx context.Context, req *connect.Request[proto.DeleteCommentRequest]) (*connect.Response[proto.DeleteCommentResponse], error) {
+	return c.deleteComment.CallUnary(ctx, req)
+}
+
+// ListComments calls task.TaskService.ListComments.
+func (c *taskServiceClient) ListComments(ctx context.Context, req *connect.Request[proto.ListCommentsRequest]) (*connect.Response[proto.ListCommentsResponse], error) {
+	return c.listComments.CallUnary(ctx, req)
+}
+
+// AddAttachment calls task.TaskService.AddAttachment.
+func (c *taskServiceClient) AddAttachment(ctx context.Context, req *connect.Request[proto.AddAttachmentRequest]) (*connect.Response[proto.Attachment], error) {
+	return c.addAttachment.CallUnary(ctx, req)
+}
+
+// GetAttachment calls task.TaskService.GetAttachment.
+func (c *taskServiceClient) GetAttachment(ctx context.Context, req *connect.Request[proto.GetAttachmentRequest]) (*connect.Response[proto.Attachment], error) {
+	return c.getAttachment.CallUnary(ctx, req)
+}
+
+// DeleteAttachment calls task.TaskService.DeleteAttachment.
+func (c *taskServiceClient) DeleteAttachment(ctx context.Context, req *connect.Request[proto.DeleteAttachmentRequest]) (*connect.Response[proto.DeleteAttachmentResponse], error) {
+	return c.deleteAttachment.CallUnary(ctx, req)
+}
+
+// ListAttachments calls task.TaskService.ListAttachments.
+func (c *taskServiceClient) ListAttachments(ctx context.Context, req *connect.Request[proto.ListAttachmentsRequest]) (*connect.Response[proto.ListAttachmentsResponse], error) {
+	return c.listAttachments.CallUnary(ctx, req)
+}
+
+// AddDependency calls task.TaskService.AddDependency.
+func (c *taskServiceClient) AddDependency(ctx context.Context, req *connect.Request[proto.TaskDependencyRequest]) (*connect.Response[proto.TaskDependenciesResponse], error) {
+	return c.addDependency.CallUnary(ctx, req)
+}
+
+// RemoveDependency calls task.TaskService.RemoveDependency.
+func (c *taskServiceClient) RemoveDependency(ctx context.Context, req *connect.Request[proto.TaskDependencyRequest]) (*connect.Response[proto.TaskDependenciesResponse], error) {
+	return c.removeDependency.CallUnary(ctx, req)
+}
+
+// CreateReminder calls task.TaskService.CreateReminder.
+func (c *taskServiceClient) CreateReminder(ctx context.Context, req *connect.Request[proto.CreateReminderRequest]) (*connect.Response[proto.Reminder], error) {
+	return c.createReminder.CallUnary(ctx, req)
+}
+
+// GetReminder calls task.TaskService.GetReminder.
+func (c *taskServiceClient) GetReminder(ctx context.Context, req *connect.Request[proto.GetReminderRequest]) (*connect.Response[proto.Reminder], error) {
+	return c.getReminder.CallUnary(ctx, req)
+}
+
+// DeleteReminder calls task.TaskService.DeleteReminder.
+func (c *taskServiceClient) DeleteReminder(ctx context.Context, req *connect.Request[proto.DeleteReminderRequest]) (*connect.Response[proto.DeleteReminderResponse], error) {
+	return c.deleteReminder.CallUnary(ctx, req)
+}
+
+// ListReminders calls task.TaskService.ListReminders.
+func (c *taskServiceClient) ListReminders(ctx context.Context, req *connect.Request[proto.ListRemindersRequest]) (*connect.Response[proto.ListRemindersResponse], error) {
+	return c.listReminders.CallUnary(ctx, req)
+}
+
+// MarkReminderFired calls task.TaskService.MarkReminderFired.
+func (c *taskServiceClient) MarkReminderFired(ctx context.Context, req *connect.Request[proto.MarkReminderFiredRequest]) (*connect.Response[proto.Reminder], error) {
+	return c.markReminderFired.CallUnary(ctx, req)
+}
+
+// RecordActivity calls task.TaskService.RecordActivity.
+func (c *taskServiceClient) RecordActivity(ctx context.Context, req *connect.Request[proto.RecordActivityRequest]) (*connect.Response[proto.Activity], error) {
+	return c.recordActivity.CallUnary(ctx, req)
+}
+
+// ListActivity calls task.TaskService.ListActivity.
+func (c *taskServiceClient) ListActivity(ctx context.Context, req *connect.Request[proto.ListActivityRequest]) (*connect.Response[proto.ListActivityResponse], error) {
+	return c.listActivity.CallUnary(ctx, req)
+}
+
+// CreateFeedToken calls task.TaskService.CreateFeedToken.
+func (c *taskServiceClient) CreateFeedToken(ctx context.Context, req *connect.Request[proto.CreateFeedTokenRequest]) (*connect.Response[proto.FeedToken], error) {
+	return c.createFeedToken.CallUnary(ctx, req)
+}
+
+// GetFeedToken calls task.TaskService.GetFeedToken.
+func (c *taskServiceClient) GetFeedToken(ctx context.Context, req *connect.Request[proto.GetFeedTokenRequest]) (*connect.Response[proto.FeedToken], error) {
+	return c.getFeedToken.CallUnary(ctx, req)
+}
+
+// DeleteFeedToken calls task.TaskService.DeleteFeedToken.
+func (c *taskServiceClient) DeleteFeedToken(ctx context.Context, req *connect.Request[proto.DeleteFeedTokenRequest]) (*connect.Response[proto.DeleteFeedTokenResponse], error) {
+	return c.deleteFeedToken.CallUnary(ctx, req)
+}
+
+// ListFeedTokens calls task.TaskService.ListFeedTokens.
+func (c *taskServiceClient) ListFeedTokens(ctx context.Context, req *connect.Request[proto.ListFeedTokensRequest]) (*connect.Response[proto.ListFeedTokensResponse], error) {
+	return c.listFeedTokens.CallUnary(ctx, req)
+}
+
+// CreateWebhook calls task.TaskService.CreateWebhook.
+func (c *taskServiceClient) CreateWebhook(ctx context.Context, req *connect.Request[proto.CreateWebhookRequest]) (*connect.Response[proto.Webhook], error) {
+	return c.createWebhook.CallUnary(ctx, req)
+}
+
+// GetWebhook calls task.TaskService.GetWebhook.
+func (c *taskServiceClient) GetWebhook(ctx context.Context, req *connect.Request[proto.GetWebhookRequest]) (*connect.Response[proto.Webhook], error) {
+	return c.getWebhook.CallUnary(ctx, req)
+}
+
+// UpdateWebhook calls task.TaskService.UpdateWebhook.
+func (c *taskServiceClient) UpdateWebhook(ctx context.Context, req *connect.Request[proto.UpdateWebhookRequest]) (*connect.Response[proto.Webhook], error) {
+	return c.updateWebhook.CallUnary(ctx, req)
+}
+
+// DeleteWebhook calls task.TaskService.DeleteWebhook.
+func (c *taskServiceClient) DeleteWebhook(ctx context.Context, req *connect.Request[proto.DeleteWebhookRequest]) (*connect.Response[proto.DeleteWebhookResponse], error) {
+	return c.deleteWebhook.CallUnary(ctx, req)
+}
+
+// ListWebhooks calls task.TaskService.ListWebhooks.
+func (c *taskServiceClient) ListWebhooks(ctx context.Context, req *connect.Request[proto.ListWebhooksRequest]) (*connect.Response[proto.ListWebhooksResponse], error) {
+	return c.listWebhooks.CallUnary(ctx, req)
+}
+
+// CreateWebhookDelivery calls task.TaskService.CreateWebhookDelivery.
+func (c *taskServiceClient) CreateWebhookDelivery(ctx context.Context, req *connect.Request[proto.CreateWebhookDeliveryRequest]) (*connect.Response[proto.WebhookDelivery], error) {
+	return c.createWebhookDelivery.CallUnary(ctx, req)
+}
+
+// GetWebhookDelivery calls task.TaskService.GetWebhookDelivery.
+func (c *taskServiceClient) GetWebhookDelivery(ctx context.Context, req *connect.Request[proto.GetWebhookDeliveryRequest]) (*connect.Response[proto.WebhookDelivery], error) {
+	return c.getWebhookDelivery.CallUnary(ctx, req)
+}
+
+// UpdateWebhookDelivery calls task.TaskService.UpdateWebhookDelivery.
+func (c *taskServiceClient) UpdateWebhookDelivery(ctx context.Context, req *connect.Request[proto.UpdateWebhookDeliveryRequest]) (*connect.Response[proto.WebhookDelivery], error) {
+	return c.updateWebhookDelivery.CallUnary(ctx, req)
+}
+
+// ListWebhookDeliveries calls task.TaskService.ListWebhookDeliveries.
+func (c *taskServiceClient) ListWebhookDeliveries(ctx context.Context, req *connect.Request[proto.ListWebhookDeliveriesRequest]) (*connect.Response[proto.ListWebhookDeliveriesResponse], error) {
+	return c.listWebhookDeliveries.CallUnary(ctx, req)
+}
+
+// TaskServiceHandler is an implementation of the task.TaskService service.
+type TaskServiceHandler interface {
+	CreateTask(context.Context, *connect.Request[proto.CreateTaskRequest]) (*connect.Response[proto.CreateTaskResponse], error)
+	GetTask(context.Context, *connect.Request[proto.GetTaskRequest]) (*connect.Response[proto.Task], error)
+	UpdateTask(context.Context, *connect.Request[proto.UpdateTaskRequest]) (*connect.Response[proto.UpdateTaskResponse], error)
+	DeleteTask(context.Context, *connect.Request[proto.DeleteTaskRequest]) (*connect.Response[proto.DeleteTaskResponse], error)
+	ListTasks(context.Context, *connect.Request[proto.ListTasksRequest]) (*connect.Response[proto.ListTasksResponse], error)
+	SearchTasks(context.Context, *connect.Request[proto.SearchTasksRequest]) (*connect.Response[proto.SearchTasksResponse], error)
+	CreateLabel(context.Context, *connect.Request[proto.CreateLabelRequest]) (*connect.Response[proto.Label], error)
+	GetLabel(context.Context, *connect.Request[proto.GetLabelRequest]) (*connect.Response[proto.Label], error)
+	UpdateLabel(context.Context, *connect.Request[proto.UpdateLabelRequest]) (*connect.Response[proto.Label], error)
+	DeleteLabel(context.Context, *connect.Request[proto.DeleteLabelRequest]) (*connect.Response[proto.DeleteLabelResponse], error)
+	ListLabels(context.Context, *connect.Request[proto.ListLabelsRequest]) (*connect.Response[proto.ListLabelsResponse], error)
+	AttachLabels(context.Context, *connect.Request[proto.TaskLabelsRequest]) (*connect.Response[proto.TaskLabelsResponse], error)
+	DetachLabels(context.Context, *connect.Request[proto.TaskLabelsRequest]) (*connect.Response[proto.TaskLabelsResponse], error)
+	CreateComment(context.Context, *connect.Request[proto.CreateCommentRequest]) (*connect.Response[proto.Comment], error)
+	GetComment(context.Context, *connect.Request[proto.GetCommentRequest]) (*connect.Response[proto.Comment], error)
+	UpdateComment(context.Context, *connect.Request[proto.UpdateCommentRequest]) (*connect.Response[proto.Comment], error)
+	DeleteComment(context.Context, *connect.Request[proto.DeleteCommentRequest]) (*connect.Response[proto.DeleteCommentResponse], error)
+	ListComments(context.Context, *connect.Request[proto.ListCommentsRequest]) (*connect.Response[proto.ListCommentsResponse], error)
+	AddAttachment(context.Context, *connect.Request[proto.AddAttachmentRequest]) (*connect.Response[proto.Attachment], error)
+	GetAttachment(context.Context, *connect.Request[proto.GetAttachmentRequest]) (*connect.Response[proto.Attachment], error)
+	DeleteAttachment(context.Context, *connect.Request[proto.DeleteAttachmentRequest]) (*connect.Response[proto.DeleteAttachmentResponse], error)
+	ListAttachments(context.Context, *connect.Request[proto.ListAttachmentsRequest]) (*connect.Response[proto.ListAttachmentsResponse], error)
+	AddDependency(context.Context, *connect.Request[proto.TaskDependencyRequest]) (*connect.Response[proto.TaskDependenciesResponse], error)
+	RemoveDependency(context.Context, *connect.Request[proto.TaskDependencyRequest]) (*connect.Response[proto.TaskDependenciesResponse], error)
+	CreateReminder(context.Context, *connect.Request[proto.CreateReminderRequest]) (*connect.Response[proto.Reminder], error)
+	GetReminder(context.Context, *connect.Request[proto.GetReminderRequest]) (*connect.Response[proto.Reminder], error)
+	DeleteReminder(context.Context, *connect.Request[proto.DeleteReminderRequest]) (*connect.Response[proto.DeleteReminderResponse], error)
+	ListReminders(context.Context, *connect.Request[proto.ListRemindersRequest]) (*connect.Response[proto.ListRemindersResponse], error)
+	MarkReminderFired(context.Context, *connect.Request[proto.MarkReminderFiredRequest]) (*connect.Response[proto.Reminder], error)
+	RecordActivity(context.Context, *connect.Request[proto.RecordActivityRequest]) (*connect.Response[proto.Activity], error)
+	ListActivity(context.Context, *connect.Request[proto.ListActivityRequest]) (*connect.Response[proto.ListActivityResponse], error)
+	CreateFeedToken(context.Context, *connect.Request[proto.CreateFeedTokenRequest]) (*connect.Response[proto.FeedToken], error)
+	GetFeedToken(context.Context, *connect.Request[proto.GetFeedTokenRequest]) (*connect.Response[proto.FeedToken], error)
+	DeleteFeedToken(context.Context, *connect.Request[proto.DeleteFeedTokenRequest]) (*connect.Response[proto.DeleteFeedTokenResponse], error)
+	ListFeedTokens(context.Context, *connect.Request[proto.ListFeedTokensRequest]) (*connect.Response[proto.ListFeedTokensResponse], error)
+	CreateWebhook(context.Context, *connect.Request[proto.CreateWebhookRequest]) (*connect.Response[proto.Webhook], error)
+	GetWebhook(context.Context, *connect.Request[proto.GetWebhookRequest]) (*connect.Response[proto.Webhook], error)
+	UpdateWebhook(context.Context, *connect.Request[proto.UpdateWebhookRequest]) (*connect.Response[proto.Webhook], error)
+	DeleteWebhook(context.Context, *connect.Request[proto.DeleteWebhookRequest]) (*connect.Response[proto.DeleteWebhookResponse], error)
+	ListWebhooks(context.Context, *connect.Request[proto.ListWebhooksRequest]) (*connect.Response[proto.ListWebhooksResponse], error)
+	CreateWebhookDelivery(context.Context, *connect.Request[proto.CreateWebhookDeliveryRequest]) (*connect.Response[proto.WebhookDelivery], error)
+	GetWebhookDelivery(context.Context, *connect.Request[proto.GetWebhookDeliveryRequest]) (*connect.Response[proto.WebhookDelivery], error)
+	UpdateWebhookDelivery(context.Context, *connect.Request[proto.UpdateWebhookDeliveryRequest]) (*connect.Response[proto.WebhookDelivery], error)
+	ListWebhookDeliveries(context.Context, *connect.Request[proto.ListWebhookDeliveriesRequest]) (*connect.Response[proto.ListWebhookDeliveriesResponse], error)
+}
+
+// NewTaskServiceHandler builds an HTTP handler from the service implementation. It returns the path
+// on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewTaskServiceHandler(svc TaskServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	taskServiceCreateTaskHandler := connect.NewUnaryHandler(
+		TaskServiceCreateTaskProcedure,
+		svc.CreateTask,
+		connect.WithSchema(taskServiceCreateTaskMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceGetTaskHandler := connect.NewUnaryHandler(
+		TaskServiceGetTaskProcedure,
+		svc.GetTask,
+		connect.WithSchema(taskServiceGetTaskMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceUpdateTaskHandler := connect.NewUnaryHandler(
+		TaskServiceUpdateTaskProcedure,
+		svc.UpdateTask,
+		connect.WithSchema(taskServiceUpdateTaskMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceDeleteTaskHandler := connect.NewUnaryHandler(
+		TaskServiceDeleteTaskProcedure,
+		svc.DeleteTask,
+		connect.WithSchema(taskServiceDeleteTaskMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceListTasksHandler := connect.NewUnaryHandler(
+		TaskServiceListTasksProcedure,
+		svc.ListTasks,
+		connect.WithSchema(taskServiceListTasksMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceSearchTasksHandler := connect.NewUnaryHandler(
+		TaskServiceSearchTasksProcedure,
+		svc.SearchTasks,
+		connect.WithSchema(taskServiceSearchTasksMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceCreateLabelHandler := connect.NewUnaryHandler(
+		TaskServiceCreateLabelProcedure,
+		svc.CreateLabel,
+		connect.WithSchema(taskServiceCreateLabelMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceGetLabelHandler := connect.NewUnaryHandler(
+		TaskServiceGetLabelProcedure,
+		svc.GetLabel,
+		connect.WithSchema(taskServiceGetLabelMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceUpdateLabelHandler := connect.NewUnaryHandler(
+		TaskServiceUpdateLabelProcedure,
+		svc.UpdateLabel,
+		connect.WithSchema(taskServiceUpdateLabelMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceDeleteLabelHandler := connect.NewUnaryHandler(
+		TaskServiceDeleteLabelProcedure,
+		svc.DeleteLabel,
+		connect.WithSchema(taskServiceDeleteLabelMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceListLabelsHandler := connect.NewUnaryHandler(
+		TaskServiceListLabelsProcedure,
+		svc.ListLabels,
+		connect.WithSchema(taskServiceListLabelsMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceAttachLabelsHandler := connect.NewUnaryHandler(
+		TaskServiceAttachLabelsProcedure,
+		svc.AttachLabels,
+		connect.WithSchema(taskServiceAttachLabelsMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceDetachLabelsHandler := connect.NewUnaryHandler(
+		TaskServiceDetachLabelsProcedure,
+		svc.DetachLabels,
+		connect.WithSchema(taskServiceDetachLabelsMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceCreateCommentHandler := connect.NewUnaryHandler(
+		TaskServiceCreateCommentProcedure,
+		svc.CreateComment,
+		connect.WithSchema(taskServiceCreateCommentMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceGetCommentHandler := connect.NewUnaryHandler(
+		TaskServiceGetCommentProcedure,
+		svc.GetComment,
+		connect.WithSchema(taskServiceGetCommentMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceUpdateCommentHandler := connect.NewUnaryHandler(
+		TaskServiceUpdateCommentProcedure,
+		svc.UpdateComment,
+		connect.WithSchema(taskServiceUpdateCommentMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceDeleteCommentHandler := connect.NewUnaryHandler(
+		TaskServiceDeleteCommentProcedure,
+		svc.DeleteComment,
+		connect.WithSchema(taskServiceDeleteCommentMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceListCommentsHandler := connect.NewUnaryHandler(
+		TaskServiceListCommentsProcedure,
+		svc.ListComments,
+		connect.WithSchema(taskServiceListCommentsMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceAddAttachmentHandler := connect.NewUnaryHandler(
+		TaskServiceAddAttachmentProcedure,
+		svc.AddAttachment,
+		connect.WithSchema(taskServiceAddAttachmentMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceGetAttachmentHandler := connect.NewUnaryHandler(
+		TaskServiceGetAttachmentProcedure,
+		svc.GetAttachment,
+		connect.WithSchema(taskServiceGetAttachmentMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceDeleteAttachmentHandler := connect.NewUnaryHandler(
+		TaskServiceDeleteAttachmentProcedure,
+		svc.DeleteAttachment,
+		connect.WithSchema(taskServiceDeleteAttachmentMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceListAttachmentsHandler := connect.NewUnaryHandler(
+		TaskServiceListAttachmentsProcedure,
+		svc.ListAttachments,
+		connect.WithSchema(taskServiceListAttachmentsMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceAddDependencyHandler := connect.NewUnaryHandler(
+		TaskServiceAddDependencyProcedure,
+		svc.AddDependency,
+		connect.WithSchema(taskServiceAddDependencyMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceRemoveDependencyHandler := connect.NewUnaryHandler(
+		TaskServiceRemoveDependencyProcedure,
+		svc.RemoveDependency,
+		connect.WithSchema(taskServiceRemoveDependencyMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceCreateReminderHandler := connect.NewUnaryHandler(
+		TaskServiceCreateReminderProcedure,
+		svc.CreateReminder,
+		connect.WithSchema(taskServiceCreateReminderMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceGetReminderHandler := connect.NewUnaryHandler(
+		TaskServiceGetReminderProcedure,
+		svc.GetReminder,
+		connect.WithSchema(taskServiceGetReminderMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceDeleteReminderHandler := connect.NewUnaryHandler(
+		TaskServiceDeleteReminderProcedure,
+		svc.DeleteReminder,
+		connect.WithSchema(taskServiceDeleteReminderMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceListRemindersHandler := connect.NewUnaryHandler(
+		TaskServiceListRemindersProcedure,
+		svc.ListReminders,
+		connect.WithSchema(taskServiceListRemindersMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceMarkReminderFiredHandler := connect.NewUnaryHandler(
+		TaskServiceMarkReminderFiredProcedure,
+		svc.MarkReminderFired,
+		connect.WithSchema(taskServiceMarkReminderFiredMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceRecordActivityHandler := connect.NewUnaryHandler(
+		TaskServiceRecordActivityProcedure,
+		svc.RecordActivity,
+		connect.WithSchema(taskServiceRecordActivityMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceListActivityHandler := connect.NewUnaryHandler(
+		TaskServiceListActivityProcedure,
+		svc.ListActivity,
+		connect.WithSchema(taskServiceListActivityMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceCreateFeedTokenHandler := connect.NewUnaryHandler(
+		TaskServiceCreateFeedTokenProcedure,
+		svc.CreateFeedToken,
+		connect.WithSchema(taskServiceCreateFeedTokenMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceGetFeedTokenHandler := connect.NewUnaryHandler(
+		TaskServiceGetFeedTokenProcedure,
+		svc.GetFeedToken,
+		connect.WithSchema(taskServiceGetFeedTokenMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceDeleteFeedTokenHandler := connect.NewUnaryHandler(
+		TaskServiceDeleteFeedTokenProcedure,
+		svc.DeleteFeedToken,
+		connect.WithSchema(taskServiceDeleteFeedTokenMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceListFeedTokensHandler := connect.NewUnaryHandler(
+		TaskServiceListFeedTokensProcedure,
+		svc.ListFeedTokens,
+		connect.WithSchema(taskServiceListFeedTokensMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceCreateWebhookHandler := connect.NewUnaryHandler(
+		TaskServiceCreateWebhookProcedure,
+		svc.CreateWebhook,
+		connect.WithSchema(taskServiceCreateWebhookMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceGetWebhookHandler := connect.NewUnaryHandler(
+		TaskServiceGetWebhookProcedure,
+		svc.GetWebhook,
+		connect.WithSchema(taskServiceGetWebhookMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceUpdateWebhookHandler := connect.NewUnaryHandler(
+		TaskServiceUpdateWebhookProcedure,
+		svc.UpdateWebhook,
+		connect.WithSchema(taskServiceUpdateWebhookMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceDeleteWebhookHandler := connect.NewUnaryHandler(
+		TaskServiceDeleteWebhookProcedure,
+		svc.DeleteWebhook,
+		connect.WithSchema(taskServiceDeleteWebhookMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceListWebhooksHandler := connect.NewUnaryHandler(
+		TaskServiceListWebhooksProcedure,
+		svc.ListWebhooks,
+		connect.WithSchema(taskServiceListWebhooksMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceCreateWebhookDeliveryHandler := connect.NewUnaryHandler(
+		TaskServiceCreateWebhookDeliveryProcedure,
+		svc.CreateWebhookDelivery,
+		connect.WithSchema(taskServiceCreateWebhookDeliveryMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceGetWebhookDeliveryHandler := connect.NewUnaryHandler(
+		TaskServiceGetWebhookDeliveryProcedure,
+		svc.GetWebhookDelivery,
+		connect.WithSchema(taskServiceGetWebhookDeliveryMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceUpdateWebhookDeliveryHandler := connect.NewUnaryHandler(
+		TaskServiceUpdateWebhookDeliveryProcedure,
+		svc.UpdateWebhookDelivery,
+		connect.WithSchema(taskServiceUpdateWebhookDeliveryMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	taskServiceListWebhookDeliveriesHandler := connect.NewUnaryHandler(
+		TaskServiceListWebhookDeliveriesProcedure,
+		svc.ListWebhookDeliveries,
+		connect.WithSchema(taskServiceListWebhookDeliveriesMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/task.TaskService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case TaskServiceCreateTaskProcedure:
+			taskServiceCreateTaskHandler.ServeHTTP(w, r)
+		case TaskServiceGetTaskProcedure:
+			taskServiceGetTaskHandler.ServeHTTP(w, r)
+		case TaskServiceUpdateTaskProcedure:
+			taskServiceUpdateTaskHandler.ServeHTTP(w, r)
+		case TaskServiceDeleteTaskProcedure:
+			taskServiceDeleteTaskHandler.ServeHTTP(w, r)
+		case TaskServiceListTasksProcedure:
+			taskServiceListTasksHandler.ServeHTTP(w, r)
+		case TaskServiceSearchTasksProcedure:
+			taskServiceSearchTasksHandler.ServeHTTP(w, r)
+		case TaskServiceCreateLabelProcedure:
+			taskServiceCreateLabelHandler.ServeHTTP(w, r)
+		case TaskServiceGetLabelProcedure:
+			taskServiceGetLabelHandler.ServeHTTP(w, r)
+		case TaskServiceUpdateLabelProcedure:
+			taskServiceUpdateLabelHandler.ServeHTTP(w, r)
+		case TaskServiceDeleteLabelProcedure:
+			taskServiceDeleteLabelHandler.ServeHTTP(w, r)
+		case TaskServiceListLabelsProcedure:
+			taskServiceListLabelsHandler.ServeHTTP(w, r)
+		case TaskServiceAttachLabelsProcedure:
+			taskServiceAttachLabelsHandler.ServeHTTP(w, r)
+		case TaskServiceDetachLabelsProcedure:
+			taskServiceDetachLabelsHandler.ServeHTTP(w, r)
+		case TaskServiceCreateCommentProcedure:
+			taskServiceCreateCommentHandler.ServeHTTP(w, r)
+		case TaskServiceGetCommentProcedure:
+			taskServiceGetCommentHandler.ServeHTTP(w, r)
+		case TaskServiceUpdateCommentProcedure:
+			taskServiceUpdateCommentHandler.ServeHTTP(w, r)
+		case TaskServiceDeleteCommentProcedure:
+			taskServiceDeleteCommentHandler.ServeHTTP(w, r)
+		case TaskServiceListCommentsProcedure:
+			taskServiceListCommentsHandler.ServeHTTP(w, r)
+		case TaskServiceAddAttachmentProcedure:
+			taskServiceAddAttachmentHandler.ServeHTTP(w, r)
+		case TaskServiceGetAttachmentProcedure:
+			taskServiceGetAttachmentHandler.ServeHTTP(w, r)
+		case TaskServiceDeleteAttachmentProcedure:
+			taskServiceDeleteAttachmentHandler.ServeHTTP(w, r)
+		case TaskServiceListAttachmentsProcedure:
+			taskServiceListAttachmentsHandler.ServeHTTP(w, r)
+		case TaskServiceAddDependencyProcedure:
+			taskServiceAddDependencyHandler.ServeHTTP(w, r)
+		case TaskServiceRemoveDependencyProcedure:
+			taskServiceRemoveDependencyHandler.ServeHTTP(w, r)
+		case TaskServiceCreateReminderProcedure:
+			taskServiceCreateReminderHandler.ServeHTTP(w, r)
+		case TaskServiceGetReminderProcedure:
+			taskServiceGetReminderHandler.ServeHTTP(w, r)
+		case TaskServiceDeleteReminderProcedure:
+			taskServiceDeleteReminderHandler.ServeHTTP(w, r)
+		case TaskServiceListRemindersProcedure:
+			taskServiceListRemindersHandler.ServeHTTP(w, r)
+		case TaskServiceMarkReminderFiredProcedure:
+			taskServiceMarkReminderFiredHandler.ServeHTTP(w, r)
+		case TaskServiceRecordActivityProcedure:
+			taskServiceRecordActivityHandler.ServeHTTP(w, r)
+		case TaskServiceListActivityProcedure:
+			taskServiceListActivityHandler.ServeHTTP(w, r)
+		case TaskServiceCreateFeedTokenProcedure:
+			taskServiceCreateFeedTokenHandler.ServeHTTP(w, r)
+		case TaskServiceGetFeedTokenProcedure:
+			taskServiceGetFeedTokenHandler.ServeHTTP(w, r)
+		case TaskServiceDeleteFeedTokenProcedure:
+			taskServiceDeleteFeedTokenHandler.ServeHTTP(w, r)
+		case TaskServiceListFeedTokensProcedure:
+			taskServiceListFeedTokensHandler.ServeHTTP(w, r)
+		case TaskServiceCreateWebhookProcedure:
+			taskServiceCreateWebhookHandler.ServeHTTP(w, r)
+		case TaskServiceGetWebhookProcedure:
+			taskServiceGetWebhookHandler.ServeHTTP(w, r)
+		case TaskServiceUpdateWebhookProcedure:
+			taskServiceUpdateWebhookHandler.ServeHTTP(w, r)
+		case TaskServiceDeleteWebhookProcedure:
+			taskServiceDeleteWebhookHandler.ServeHTTP(w, r)
+		case TaskServiceListWebhooksProcedure:
+			taskServiceListWebhooksHandler.ServeHTTP(w, r)
+		case TaskServiceCreateWebhookDeliveryProcedure:
+			taskServiceCreateWebhookDeliveryHandler.ServeHTTP(w, r)
+		case TaskServiceGetWebhookDeliveryProcedure:
+			taskServiceGetWebhookDeliveryHandler.ServeHTTP(w, r)
+		case TaskServiceUpdateWebhookDeliveryProcedure:
+			taskServiceUpdateWebhookDeliveryHandler.ServeHTTP(w, r)
+		case TaskServiceListWebhookDeliveriesProcedure:
+			taskServiceListWebhookDeliveriesHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedTaskServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedTaskServiceHandler struct{}
+
+func (UnimplementedTaskServiceHandler) CreateTask(context.Context, *connect.Request[proto.CreateTaskRequest]) (*connect.Response[proto.CreateTaskResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.CreateTask is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) GetTask(context.Context, *connect.Request[proto.GetTaskRequest]) (*connect.Response[proto.Task], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.GetTask is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) UpdateTask(context.Context, *connect.Request[proto.UpdateTaskRequest]) (*connect.Response[proto.UpdateTaskResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.UpdateTask is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) DeleteTask(context.Context, *connect.Request[proto.DeleteTaskRequest]) (*connect.Response[proto.DeleteTaskResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.DeleteTask is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) ListTasks(context.Context, *connect.Request[proto.ListTasksRequest]) (*connect.Response[proto.ListTasksResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.ListTasks is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) SearchTasks(context.Context, *connect.Request[proto.SearchTasksRequest]) (*connect.Response[proto.SearchTasksResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.SearchTasks is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) CreateLabel(context.Context, *connect.Request[proto.CreateLabelRequest]) (*connect.Response[proto.Label], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.CreateLabel is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) GetLabel(context.Context, *connect.Request[proto.GetLabelRequest]) (*connect.Response[proto.Label], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.GetLabel is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) UpdateLabel(context.Context, *connect.Request[proto.UpdateLabelRequest]) (*connect.Response[proto.Label], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.UpdateLabel is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) DeleteLabel(context.Context, *connect.Request[proto.DeleteLabelRequest]) (*connect.Response[proto.DeleteLabelResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.DeleteLabel is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) ListLabels(context.Context, *connect.Request[proto.ListLabelsRequest]) (*connect.Response[proto.ListLabelsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.ListLabels is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) AttachLabels(context.Context, *connect.Request[proto.TaskLabelsRequest]) (*connect.Response[proto.TaskLabelsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.AttachLabels is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) DetachLabels(context.Context, *connect.Request[proto.TaskLabelsRequest]) (*connect.Response[proto.TaskLabelsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.DetachLabels is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) CreateComment(context.Context, *connect.Request[proto.CreateCommentRequest]) (*connect.Response[proto.Comment], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.CreateComment is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) GetComment(context.Context, *connect.Request[proto.GetCommentRequest]) (*connect.Response[proto.Comment], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.GetComment is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) UpdateComment(context.Context, *connect.Request[proto.UpdateCommentRequest]) (*connect.Response[proto.Comment], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.UpdateComment is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) DeleteComment(context.Context, *connect.Request[proto.DeleteCommentRequest]) (*connect.Response[proto.DeleteCommentResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.DeleteComment is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) ListComments(context.Context, *connect.Request[proto.ListCommentsRequest]) (*connect.Response[proto.ListCommentsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.ListComments is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) AddAttachment(context.Context, *connect.Request[proto.AddAttachmentRequest]) (*connect.Response[proto.Attachment], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.AddAttachment is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) GetAttachment(context.Context, *connect.Request[proto.GetAttachmentRequest]) (*connect.Response[proto.Attachment], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.GetAttachment is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) DeleteAttachment(context.Context, *connect.Request[proto.DeleteAttachmentRequest]) (*connect.Response[proto.DeleteAttachmentResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.DeleteAttachment is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) ListAttachments(context.Context, *connect.Request[proto.ListAttachmentsRequest]) (*connect.Response[proto.ListAttachmentsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.ListAttachments is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) AddDependency(context.Context, *connect.Request[proto.TaskDependencyRequest]) (*connect.Response[proto.TaskDependenciesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.AddDependency is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) RemoveDependency(context.Context, *connect.Request[proto.TaskDependencyRequest]) (*connect.Response[proto.TaskDependenciesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.RemoveDependency is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) CreateReminder(context.Context, *connect.Request[proto.CreateReminderRequest]) (*connect.Response[proto.Reminder], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.CreateReminder is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) GetReminder(context.Context, *connect.Request[proto.GetReminderRequest]) (*connect.Response[proto.Reminder], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.GetReminder is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) DeleteReminder(context.Context, *connect.Request[proto.DeleteReminderRequest]) (*connect.Response[proto.DeleteReminderResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.DeleteReminder is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) ListReminders(context.Context, *connect.Request[proto.ListRemindersRequest]) (*connect.Response[proto.ListRemindersResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.ListReminders is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) MarkReminderFired(context.Context, *connect.Request[proto.MarkReminderFiredRequest]) (*connect.Response[proto.Reminder], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.MarkReminderFired is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) RecordActivity(context.Context, *connect.Request[proto.RecordActivityRequest]) (*connect.Response[proto.Activity], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.RecordActivity is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) ListActivity(context.Context, *connect.Request[proto.ListActivityRequest]) (*connect.Response[proto.ListActivityResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.ListActivity is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) CreateFeedToken(context.Context, *connect.Request[proto.CreateFeedTokenRequest]) (*connect.Response[proto.FeedToken], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.CreateFeedToken is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) GetFeedToken(context.Context, *connect.Request[proto.GetFeedTokenRequest]) (*connect.Response[proto.FeedToken], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.GetFeedToken is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) DeleteFeedToken(context.Context, *connect.Request[proto.DeleteFeedTokenRequest]) (*connect.Response[proto.DeleteFeedTokenResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.DeleteFeedToken is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) ListFeedTokens(context.Context, *connect.Request[proto.ListFeedTokensRequest]) (*connect.Response[proto.ListFeedTokensResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.ListFeedTokens is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) CreateWebhook(context.Context, *connect.Request[proto.CreateWebhookRequest]) (*connect.Response[proto.Webhook], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.CreateWebhook is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) GetWebhook(context.Context, *connect.Request[proto.GetWebhookRequest]) (*connect.Response[proto.Webhook], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.GetWebhook is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) UpdateWebhook(context.Context, *connect.Request[proto.UpdateWebhookRequest]) (*connect.Response[proto.Webhook], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.UpdateWebhook is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) DeleteWebhook(context.Context, *connect.Request[proto.DeleteWebhookRequest]) (*connect.Response[proto.DeleteWebhookResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.DeleteWebhook is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) ListWebhooks(context.Context, *connect.Request[proto.ListWebhooksRequest]) (*connect.Response[proto.ListWebhooksResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.ListWebhooks is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) CreateWebhookDelivery(context.Context, *connect.Request[proto.CreateWebhookDeliveryRequest]) (*connect.Response[proto.WebhookDelivery], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.CreateWebhookDelivery is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) GetWebhookDelivery(context.Context, *connect.Request[proto.GetWebhookDeliveryRequest]) (*connect.Response[proto.WebhookDelivery], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.GetWebhookDelivery is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) UpdateWebhookDelivery(context.Context, *connect.Request[proto.UpdateWebhookDeliveryRequest]) (*connect.Response[proto.WebhookDelivery], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.UpdateWebhookDelivery is not implemented"))
+}
+
+func (UnimplementedTaskServiceHandler) ListWebhookDeliveries(context.Context, *connect.Request[proto.ListWebhookDeliveriesRequest]) (*connect.Response[proto.ListWebhookDeliveriesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("task.TaskService.ListWebhookDeliveries is not implemented"))
+}
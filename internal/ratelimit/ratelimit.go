@@ -0,0 +1,87 @@
+// Package ratelimit limits the request rate of every caller of the gateway.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/models"
+	"golang.org/x/time/rate"
+)
+
+// idleTimeout is how long the bucket of a caller is kept after its last
+// request.
+const idleTimeout = 10 * time.Minute
+
+// Limiter keeps a token bucket per caller. A nil Limiter allows every
+// request.
+type Limiter struct {
+	limit rate.Limit
+	burst int
+
+	mu      sync.Mutex
+	callers map[string]*caller
+	swept   time.Time
+}
+
+type caller struct {
+	limiter *rate.Limiter
+	seen    time.Time
+}
+
+// New returns a Limiter for conf, or nil when conf does not limit anything.
+func New(conf *models.RateLimit) *Limiter {
+	if conf == nil || conf.RequestsPerSecond <= 0 {
+		return nil
+	}
+	burst := conf.Burst
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{
+		limit:   rate.Limit(conf.RequestsPerSecond),
+		burst:   burst,
+		callers: map[string]*caller{},
+		swept:   time.Now(),
+	}
+}
+
+// Key identifies the caller of a request: the user when there is one and
+// the address it came from otherwise.
+func Key(user, addr string) string {
+	if user != "" {
+		return "user:" + user
+	}
+	return "addr:" + addr
+}
+
+// Allow reports whether the caller identified by key may make a request now.
+func (l *Limiter) Allow(key string) bool {
+	if l == nil {
+		return true
+	}
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now.Sub(l.swept) > idleTimeout {
+		l.sweep(now)
+	}
+	c, ok := l.callers[key]
+	if !ok {
+		c = &caller{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.callers[key] = c
+	}
+	c.seen = now
+	return c.limiter.AllowN(now, 1)
+}
+
+// sweep forgets the callers that have been idle for idleTimeout. They start
+// over with a full bucket.
+func (l *Limiter) sweep(now time.Time) {
+	for key, c := range l.callers {
+		if now.Sub(c.seen) > idleTimeout {
+			delete(l.callers, key)
+		}
+	}
+	l.swept = now
+}
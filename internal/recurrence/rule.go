@@ -0,0 +1,92 @@
+// Package recurrence expands RFC 5545 recurrence rules and creates the next
+// occurrence of recurring tasks.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+// Recurrence modes decide when the next occurrence of a series is created.
+const (
+	// ModeOnCompletion creates the next occurrence once the current one
+	// reaches a final status.
+	ModeOnCompletion = "on_completion"
+	// ModeOnSchedule creates the next occurrence when the current one falls
+	// due, whether it was completed or not.
+	ModeOnSchedule = "on_schedule"
+)
+
+var (
+	ErrInvalidRule = errors.New("invalid rrule")
+	ErrInvalidMode = errors.New("invalid recurrence_mode")
+)
+
+// Rule is a recurrence rule anchored at the start of its series.
+type Rule struct {
+	value string
+	rule  *rrule.RRule
+	loc   *time.Location
+}
+
+// Parse reads the RRULE value (with or without the "RRULE:" prefix) in the
+// time zone loc. Occurrences repeat the wall-clock time of start in loc.
+func Parse(value string, loc *time.Location, start time.Time) (*Rule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" || strings.ContainsAny(value, "\r\n") {
+		return nil, fmt.Errorf("%w: expected a single RRULE value", ErrInvalidRule)
+	}
+	option, err := rrule.StrToROptionInLocation(value, loc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+	}
+	option.Dtstart = start.In(loc)
+	rule, err := rrule.NewRRule(*option)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+	}
+	return &Rule{value: value, rule: rule, loc: loc}, nil
+}
+
+// String returns the RRULE value without the "RRULE:" prefix.
+func (r *Rule) String() string {
+	return r.value
+}
+
+// ValidMode reports whether mode names a recurrence mode. The empty mode is
+// ModeOnCompletion.
+func ValidMode(mode string) bool {
+	return mode == "" || mode == ModeOnCompletion || mode == ModeOnSchedule
+}
+
+// Next returns the first occurrence strictly after t, or false when the
+// series has ended.
+func (r *Rule) Next(t time.Time) (time.Time, bool) {
+	next := r.rule.After(t.In(r.loc), false)
+	return next, !next.IsZero()
+}
+
+// First returns the first occurrence at or after t, or false when the
+// series has ended.
+func (r *Rule) First(t time.Time) (time.Time, bool) {
+	next := r.rule.After(t.In(r.loc), true)
+	return next, !next.IsZero()
+}
+
+// Upcoming returns at most n occurrences strictly after t.
+func (r *Rule) Upcoming(t time.Time, n int) []time.Time {
+	var occurrences []time.Time
+	for len(occurrences) < n {
+		next, ok := r.Next(t)
+		if !ok {
+			break
+		}
+		occurrences = append(occurrences, next)
+		t = next
+	}
+	return occurrences
+}
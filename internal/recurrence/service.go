@@ -0,0 +1,233 @@
+package recurrence
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/auth"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/events"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/workflow"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Actor is recorded as the user creating new occurrences.
+const Actor = "system:recurrence"
+
+const (
+	sweepInterval = time.Minute
+	sweepPageSize = 100
+	eventBuffer   = 256
+)
+
+// Service creates the next occurrence of a series when the current one is
+// completed or, for ModeOnSchedule series, when it falls due.
+type Service struct {
+	client   proto.TaskServiceClient
+	workflow *workflow.StateMachine
+	// mu serialises Advance so that events and sweeps cannot create the same
+	// occurrence twice.
+	mu sync.Mutex
+}
+
+// NewService creates occurrences through client.
+func NewService(client proto.TaskServiceClient, stateMachine *workflow.StateMachine) *Service {
+	return &Service{client: client, workflow: stateMachine}
+}
+
+// RuleFor returns the recurrence rule of task, or nil when it does not recur.
+func RuleFor(task *proto.Task) (*Rule, error) {
+	if task.GetRrule() == "" {
+		return nil, nil
+	}
+	loc, err := time.LoadLocation(task.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", task.Timezone)
+	}
+	start := task.SeriesStart
+	if start == nil {
+		start = OccurrenceTime(task)
+	}
+	return Parse(task.Rrule, loc, start.AsTime())
+}
+
+// Run advances series as their tasks are completed on bus and sweeps for
+// series to advance every minute until ctx is done. The sweep also catches
+// completions whose events were dropped.
+func (s *Service) Run(ctx context.Context, bus *events.Bus) {
+	ch, unsubscribe := bus.Subscribe(eventBuffer)
+	defer unsubscribe()
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	s.sweep(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-ch:
+			if !ok {
+				return
+			}
+			if e.Type == events.TaskUpdated && s.completed(e.Task, e.Previous) {
+				if _, err := s.Advance(ctx, e.TaskID); err != nil {
+					log.Printf("Failed to advance recurring task %s: %v", e.TaskID, err)
+				}
+			}
+		case <-ticker.C:
+			s.sweep(ctx)
+		}
+	}
+}
+
+// completed reports whether an update completed an occurrence of an
+// on-completion series.
+func (s *Service) completed(task, previous *proto.Task) bool {
+	if task.GetRrule() == "" || task.RecurrenceMode == ModeOnSchedule {
+		return false
+	}
+	return s.workflow.IsFinal(task.Status) && (previous == nil || !s.workflow.IsFinal(previous.Status))
+}
+
+func (s *Service) sweep(ctx context.Context) {
+	var due []string
+	now := time.Now()
+	for page := int32(0); ; page++ {
+		res, err := s.client.ListTasks(ctx, &proto.ListTasksRequest{
+			Page:      page,
+			PageSize:  sweepPageSize,
+			Recurring: true,
+		})
+		if err != nil {
+			log.Printf("Failed to list recurring tasks: %v", err)
+			return
+		}
+		for _, task := range res.Tasks {
+			if task.RecurrenceMode == ModeOnSchedule {
+				if !OccurrenceTime(task).AsTime().After(now) {
+					due = append(due, task.Id)
+				}
+			} else if s.workflow.IsFinal(task.Status) {
+				due = append(due, task.Id)
+			}
+		}
+		if len(res.Tasks) < sweepPageSize {
+			break
+		}
+	}
+
+	for _, id := range due {
+		if _, err := s.Advance(ctx, id); err != nil {
+			log.Printf("Failed to advance recurring task %s: %v", id, err)
+		}
+	}
+}
+
+// Advance creates the occurrence following the task and moves the rule
+// over to it. Occurrences missed while the series was not advanced are
+// skipped. When the series has ended the task only loses its rule. It
+// returns the new occurrence, or nil when none was created.
+//
+// Occurrences are identified by OccurrenceID, so that advancing the task
+// again after the rule failed to be cleared finds the occurrence created
+// the first time instead of creating it twice.
+func (s *Service) Advance(ctx context.Context, id string) (*proto.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctx = auth.WithUser(ctx, Actor)
+	task, err := s.client.GetTask(ctx, &proto.GetTaskRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	rule, err := RuleFor(task)
+	if rule == nil || err != nil {
+		return nil, err
+	}
+
+	after := OccurrenceTime(task).AsTime()
+	if now := time.Now(); now.After(after) {
+		after = now
+	}
+	var next *proto.Task
+	if at, ok := rule.Next(after); ok {
+		if next, err = s.createOccurrence(ctx, task, at); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = s.client.UpdateTask(ctx, &proto.UpdateTaskRequest{
+		Id:         task.Id,
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"rrule", "recurrence_mode"}},
+	})
+	if err != nil {
+		return next, fmt.Errorf("clearing rrule: %w", err)
+	}
+	return next, nil
+}
+
+// OccurrenceID is the external id of the occurrence of a series at a time.
+func OccurrenceID(seriesID string, at time.Time) string {
+	return fmt.Sprintf("series:%s@%s", seriesID, at.UTC().Format(time.RFC3339))
+}
+
+func (s *Service) createOccurrence(ctx context.Context, task *proto.Task, at time.Time) (*proto.Task, error) {
+	seriesID := task.SeriesId
+	if seriesID == "" {
+		seriesID = task.Id
+	}
+	externalID := OccurrenceID(seriesID, at)
+	existing, err := s.client.ListTasks(ctx, &proto.ListTasksRequest{ExternalIds: []string{externalID}, PageSize: 1})
+	if err != nil {
+		return nil, fmt.Errorf("looking up occurrence %s: %w", externalID, err)
+	}
+	if len(existing.Tasks) > 0 {
+		return existing.Tasks[0], nil
+	}
+
+	seriesStart := task.SeriesStart
+	if seriesStart == nil {
+		seriesStart = OccurrenceTime(task)
+	}
+
+	res, err := s.client.CreateTask(ctx, &proto.CreateTaskRequest{
+		ParentId:       task.ParentId,
+		Title:          task.Title,
+		Description:    task.Description,
+		Status:         s.workflow.Initial(),
+		DueAt:          timestamppb.New(at),
+		Priority:       task.Priority,
+		Assignee:       task.Assignee,
+		Reporter:       task.Reporter,
+		Rrule:          task.Rrule,
+		Timezone:       task.Timezone,
+		SeriesId:       seriesID,
+		SeriesStart:    seriesStart,
+		RecurrenceMode: task.RecurrenceMode,
+		ExternalId:     externalID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(task.Labels) > 0 {
+		_, err = s.client.AttachLabels(ctx, &proto.TaskLabelsRequest{TaskId: res.Id, Labels: task.Labels})
+		if err != nil {
+			return nil, fmt.Errorf("labelling occurrence %s: %w", res.Id, err)
+		}
+	}
+	return s.client.GetTask(ctx, &proto.GetTaskRequest{Id: res.Id})
+}
+
+// OccurrenceTime is the time of the occurrence a task stands for: its due
+// date, or its creation time when it has none. OccurrenceID identifies the
+// occurrence by it.
+func OccurrenceTime(task *proto.Task) *timestamppb.Timestamp {
+	if task.DueAt != nil {
+		return task.DueAt
+	}
+	return task.CreatedAt
+}
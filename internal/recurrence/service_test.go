@@ -0,0 +1,127 @@
+package recurrence
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/workflow"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// fakeTasks keeps tasks in memory. Its first failUpdates updates fail.
+type fakeTasks struct {
+	proto.TaskServiceClient
+
+	tasks       map[string]*proto.Task
+	failUpdates int
+}
+
+func (f *fakeTasks) GetTask(_ context.Context, in *proto.GetTaskRequest, _ ...grpc.CallOption) (*proto.Task, error) {
+	task, ok := f.tasks[in.Id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return task, nil
+}
+
+func (f *fakeTasks) CreateTask(_ context.Context, in *proto.CreateTaskRequest, _ ...grpc.CallOption) (*proto.CreateTaskResponse, error) {
+	id := strconv.Itoa(len(f.tasks) + 1)
+	f.tasks[id] = &proto.Task{
+		Id:             id,
+		Title:          in.Title,
+		Status:         in.Status,
+		DueAt:          in.DueAt,
+		Rrule:          in.Rrule,
+		Timezone:       in.Timezone,
+		SeriesId:       in.SeriesId,
+		SeriesStart:    in.SeriesStart,
+		RecurrenceMode: in.RecurrenceMode,
+		ExternalId:     in.ExternalId,
+	}
+	return &proto.CreateTaskResponse{Id: id}, nil
+}
+
+func (f *fakeTasks) UpdateTask(_ context.Context, in *proto.UpdateTaskRequest, _ ...grpc.CallOption) (*proto.UpdateTaskResponse, error) {
+	if f.failUpdates > 0 {
+		f.failUpdates--
+		return nil, errors.New("unavailable")
+	}
+	task := f.tasks[in.Id]
+	if slices.Contains(in.UpdateMask.GetPaths(), "rrule") {
+		task.Rrule = in.Rrule
+	}
+	if slices.Contains(in.UpdateMask.GetPaths(), "recurrence_mode") {
+		task.RecurrenceMode = in.RecurrenceMode
+	}
+	return &proto.UpdateTaskResponse{}, nil
+}
+
+func (f *fakeTasks) ListTasks(_ context.Context, in *proto.ListTasksRequest, _ ...grpc.CallOption) (*proto.ListTasksResponse, error) {
+	res := &proto.ListTasksResponse{}
+	for _, task := range f.tasks {
+		if slices.Contains(in.ExternalIds, task.ExternalId) {
+			res.Tasks = append(res.Tasks, task)
+		}
+	}
+	return res, nil
+}
+
+func TestAdvanceCreatesOccurrenceOnce(t *testing.T) {
+	due := time.Now().Add(time.Hour).Truncate(time.Second)
+	tasks := &fakeTasks{
+		tasks: map[string]*proto.Task{"1": {
+			Id:       "1",
+			Title:    "Rotate the on-call",
+			Status:   "done",
+			DueAt:    timestamppb.New(due),
+			Rrule:    "FREQ=DAILY",
+			Timezone: "UTC",
+		}},
+		failUpdates: 1,
+	}
+	stateMachine, err := workflow.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewService(tasks, stateMachine)
+
+	first, err := s.Advance(context.Background(), "1")
+	if err == nil {
+		t.Fatal("Advance() succeeded although clearing the rule failed")
+	}
+	second, err := s.Advance(context.Background(), "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tasks.tasks) != 2 {
+		t.Fatalf("advancing twice left %d tasks, want 2", len(tasks.tasks))
+	}
+	if first.GetId() != second.GetId() {
+		t.Errorf("the retry returned occurrence %s, want %s", second.GetId(), first.GetId())
+	}
+	want := OccurrenceID("1", due.AddDate(0, 0, 1))
+	if second.ExternalId != want {
+		t.Errorf("occurrence external id = %q, want %q", second.ExternalId, want)
+	}
+	if rule := tasks.tasks["1"].Rrule; rule != "" {
+		t.Errorf("the completed task kept its rule %q", rule)
+	}
+}
+
+func TestOccurrenceTime(t *testing.T) {
+	due := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	created := due.Add(-48 * time.Hour)
+	if got := OccurrenceTime(&proto.Task{DueAt: timestamppb.New(due), CreatedAt: timestamppb.New(created)}).AsTime(); !got.Equal(due) {
+		t.Errorf("with a due date: %v, want %v", got, due)
+	}
+	if got := OccurrenceTime(&proto.Task{CreatedAt: timestamppb.New(created)}).AsTime(); !got.Equal(created) {
+		t.Errorf("without a due date: %v, want %v", got, created)
+	}
+}
@@ -0,0 +1,203 @@
+package reminder
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Actor is recorded as the sender of reminder notifications.
+const Actor = "system:reminders"
+
+const (
+	DefaultPollInterval = 30 * time.Second
+	listPageSize        = 100
+	// maxDeliveryAttempts is the number of times a failing channel is
+	// retried before the reminder is given up.
+	maxDeliveryAttempts = 5
+)
+
+// Scheduler fires reminders when they fall due. Reminders and whether they
+// fired are kept by the task service, so pending reminders survive
+// restarts and fire on the next start when they were missed.
+type Scheduler struct {
+	client   proto.TaskServiceClient
+	sinks    map[string]Sink
+	interval time.Duration
+	wake     chan struct{}
+
+	// attempts and delivered track failed deliveries by reminder id. They
+	// are only used by the Run goroutine.
+	attempts  map[string]int
+	delivered map[string]map[string]bool
+}
+
+// NewScheduler fires the reminders stored behind client through sinks,
+// looking for due reminders at least every interval.
+func NewScheduler(client proto.TaskServiceClient, sinks map[string]Sink, interval time.Duration) *Scheduler {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	return &Scheduler{
+		client:    client,
+		sinks:     sinks,
+		interval:  interval,
+		wake:      make(chan struct{}, 1),
+		attempts:  map[string]int{},
+		delivered: map[string]map[string]bool{},
+	}
+}
+
+// Channels returns the names of the configured sinks.
+func (s *Scheduler) Channels() []string {
+	channels := make([]string, 0, len(s.sinks))
+	for name := range s.sinks {
+		channels = append(channels, name)
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+// HasChannel reports whether a sink is configured for channel.
+func (s *Scheduler) HasChannel(channel string) bool {
+	_, ok := s.sinks[channel]
+	return ok
+}
+
+// Wake makes the scheduler look at the pending reminders again, for
+// instance after one was created or changed.
+func (s *Scheduler) Wake() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run fires due reminders until ctx is done. It sleeps until the next
+// pending reminder falls due, or for the poll interval at most.
+func (s *Scheduler) Run(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		case <-s.wake:
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+		}
+		s.fireDue(ctx)
+		timer.Reset(s.untilNext(ctx))
+	}
+}
+
+func (s *Scheduler) fireDue(ctx context.Context) {
+	now := time.Now()
+	var due []*proto.Reminder
+	for page := int32(0); ; page++ {
+		res, err := s.client.ListReminders(ctx, &proto.ListRemindersRequest{
+			Pending:    true,
+			FireBefore: timestamppb.New(now),
+			Page:       page,
+			PageSize:   listPageSize,
+		})
+		if err != nil {
+			log.Printf("Failed to list due reminders: %v", err)
+			return
+		}
+		due = append(due, res.Reminders...)
+		if len(res.Reminders) < listPageSize {
+			break
+		}
+	}
+
+	for _, reminder := range due {
+		s.fire(ctx, reminder, now)
+	}
+}
+
+// fire delivers the reminder through its channels and marks it fired once
+// every channel succeeded or the attempts ran out. Channels that succeeded
+// are not used again when the others are retried.
+func (s *Scheduler) fire(ctx context.Context, reminder *proto.Reminder, now time.Time) {
+	task, err := s.client.GetTask(ctx, &proto.GetTaskRequest{Id: reminder.TaskId})
+	if status.Code(err) == codes.NotFound {
+		s.markFired(ctx, reminder, now)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to read task %s for reminder %s: %v", reminder.TaskId, reminder.Id, err)
+		return
+	}
+
+	n := &Notification{Reminder: reminder, Task: task, Time: now}
+	delivered := s.delivered[reminder.Id]
+	if delivered == nil {
+		delivered = map[string]bool{}
+	}
+	failed := false
+	for _, channel := range reminder.Channels {
+		sink, ok := s.sinks[channel]
+		if !ok {
+			log.Printf("Reminder %s uses channel %q which is not configured", reminder.Id, channel)
+			continue
+		}
+		if delivered[channel] {
+			continue
+		}
+		if err := sink.Notify(ctx, n); err != nil {
+			log.Printf("Failed to deliver reminder %s through %s: %v", reminder.Id, channel, err)
+			failed = true
+			continue
+		}
+		delivered[channel] = true
+	}
+
+	if failed {
+		s.attempts[reminder.Id]++
+		if s.attempts[reminder.Id] < maxDeliveryAttempts {
+			s.delivered[reminder.Id] = delivered
+			return
+		}
+		log.Printf("Giving up on reminder %s after %d attempts", reminder.Id, maxDeliveryAttempts)
+	}
+	s.markFired(ctx, reminder, now)
+}
+
+func (s *Scheduler) markFired(ctx context.Context, reminder *proto.Reminder, now time.Time) {
+	delete(s.attempts, reminder.Id)
+	delete(s.delivered, reminder.Id)
+	_, err := s.client.MarkReminderFired(ctx, &proto.MarkReminderFiredRequest{
+		Id:      reminder.Id,
+		FiredAt: timestamppb.New(now),
+	})
+	if err != nil {
+		log.Printf("Failed to mark reminder %s as fired: %v", reminder.Id, err)
+	}
+}
+
+// untilNext returns how long to sleep before the next pending reminder is
+// due. Overdue reminders are waiting for a retry, which happens with the
+// next poll.
+func (s *Scheduler) untilNext(ctx context.Context) time.Duration {
+	res, err := s.client.ListReminders(ctx, &proto.ListRemindersRequest{
+		Pending:   true,
+		FireAfter: timestamppb.Now(),
+		PageSize:  1,
+	})
+	if err != nil || len(res.Reminders) == 0 || res.Reminders[0].FireAt == nil {
+		return s.interval
+	}
+	return min(time.Until(res.Reminders[0].FireAt.AsTime()), s.interval)
+}
@@ -0,0 +1,85 @@
+// Package reminder fires task reminders when they fall due and delivers
+// them through pluggable sinks.
+package reminder
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/events"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/models"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+)
+
+// Channels name the sinks a reminder is delivered through.
+const (
+	ChannelWebSocket = "websocket"
+	ChannelWebhook   = "webhook"
+	ChannelEmail     = "email"
+)
+
+// Notification is a reminder that fired, together with its task.
+type Notification struct {
+	Reminder *proto.Reminder
+	Task     *proto.Task
+	Time     time.Time
+}
+
+// Sink delivers notifications through one channel.
+type Sink interface {
+	Notify(ctx context.Context, n *Notification) error
+}
+
+// NewSinks creates the sinks enabled in conf, keyed by channel. WebSocket
+// notifications are published on bus.
+func NewSinks(conf *models.ReminderSinks, bus *events.Bus) (map[string]Sink, error) {
+	sinks := map[string]Sink{}
+	if conf == nil {
+		return sinks, nil
+	}
+	if conf.WebSocket {
+		sinks[ChannelWebSocket] = NewBusSink(bus)
+	}
+	if conf.Webhook != nil {
+		if conf.Webhook.URL == "" {
+			return nil, errors.New("reminders: the webhook sink needs a url")
+		}
+		sinks[ChannelWebhook] = NewWebhookSink(conf.Webhook.URL, conf.Webhook.Secret)
+	}
+	if conf.SMTP != nil {
+		if conf.SMTP.Host == "" || conf.SMTP.From == "" {
+			return nil, errors.New("reminders: the smtp sink needs a host and a from address")
+		}
+		sinks[ChannelEmail] = NewSMTPSink(conf.SMTP)
+	}
+	return sinks, nil
+}
+
+// busSink pushes notifications to WebSocket clients through the event bus.
+type busSink struct {
+	bus *events.Bus
+}
+
+// NewBusSink publishes notifications on the task and recipient topics of
+// bus.
+func NewBusSink(bus *events.Bus) Sink {
+	return &busSink{bus: bus}
+}
+
+func (s *busSink) Notify(_ context.Context, n *Notification) error {
+	topics := []string{events.TaskTopic(n.Task.Id)}
+	if n.Reminder.Recipient != "" {
+		topics = append(topics, events.UserTopic(n.Reminder.Recipient))
+	}
+	s.bus.Publish(events.Event{
+		Type:     events.ReminderFired,
+		TaskID:   n.Task.Id,
+		Actor:    Actor,
+		Time:     n.Time,
+		Task:     n.Task,
+		Reminder: n.Reminder,
+		Topics:   topics,
+	})
+	return nil
+}
@@ -0,0 +1,140 @@
+package reminder
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/models"
+)
+
+// smtpTimeout bounds the delivery of a message, from dialing the server to
+// the end of the session.
+const smtpTimeout = 30 * time.Second
+
+type smtpSink struct {
+	conf *models.SMTPSink
+	addr string
+}
+
+// NewSMTPSink mails notifications through the SMTP server in conf.
+func NewSMTPSink(conf *models.SMTPSink) Sink {
+	port := conf.Port
+	if port == "" {
+		port = "25"
+	}
+	return &smtpSink{conf: conf, addr: net.JoinHostPort(conf.Host, port)}
+}
+
+func (s *smtpSink) Notify(ctx context.Context, n *Notification) error {
+	to, err := s.address(n.Reminder.Recipient)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, smtpTimeout)
+	defer cancel()
+	return s.send(ctx, to, s.message(to, n))
+}
+
+// send mails msg to to like smtp.SendMail, but gives up when ctx is done so
+// that an unresponsive server cannot hold up the other reminders.
+func (s *smtpSink) send(ctx context.Context, to string, msg []byte) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	// Unblock the session when ctx is cancelled before its deadline.
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	c, err := smtp.NewClient(conn, s.conf.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.conf.Host}); err != nil {
+			return err
+		}
+	}
+	if s.conf.Username != "" {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("the SMTP server does not support authentication")
+		}
+		if err := c.Auth(smtp.PlainAuth("", s.conf.Username, s.conf.Password, s.conf.Host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(s.conf.From); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// address resolves the recipient to an email address.
+func (s *smtpSink) address(recipient string) (string, error) {
+	if recipient == "" {
+		return "", errors.New("reminder has no recipient")
+	}
+	if !strings.Contains(recipient, "@") {
+		if s.conf.RecipientDomain == "" {
+			return "", fmt.Errorf("no email address for %q", recipient)
+		}
+		recipient += "@" + s.conf.RecipientDomain
+	}
+	addr, err := mail.ParseAddress(recipient)
+	if err != nil {
+		return "", err
+	}
+	return addr.Address, nil
+}
+
+func (s *smtpSink) message(to string, n *Notification) []byte {
+	loc, err := time.LoadLocation(n.Task.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	title := strings.Join(strings.Fields(n.Task.Title), " ")
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", s.conf.From)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", "Reminder: "+title))
+	fmt.Fprintf(&b, "Date: %s\r\n", n.Time.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	fmt.Fprintf(&b, "This is a reminder about task %q (%s).\r\n", title, n.Task.Id)
+	if n.Task.DueAt != nil {
+		fmt.Fprintf(&b, "It is due at %s.\r\n", n.Task.DueAt.AsTime().In(loc).Format(time.RFC1123))
+	}
+	if n.Task.Status != "" {
+		fmt.Fprintf(&b, "Its status is %s.\r\n", n.Task.Status)
+	}
+	return b.Bytes()
+}
@@ -0,0 +1,109 @@
+package reminder
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/models"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+)
+
+func testNotification() *Notification {
+	return &Notification{
+		Reminder: &proto.Reminder{Id: "r1", Recipient: "alice"},
+		Task:     &proto.Task{Id: "1", Title: "Renew the certificate"},
+		Time:     time.Now(),
+	}
+}
+
+// listen starts a TCP server handling every connection with serve, and
+// returns a sink mailing through it.
+func listen(t *testing.T, serve func(net.Conn)) Sink {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go serve(conn)
+		}
+	}()
+	host, port, _ := net.SplitHostPort(l.Addr().String())
+	return NewSMTPSink(&models.SMTPSink{Host: host, Port: port, From: "nashville@example.com", RecipientDomain: "example.com"})
+}
+
+func TestSMTPSinkDelivers(t *testing.T) {
+	received := make(chan string, 1)
+	sink := listen(t, func(conn net.Conn) {
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ESMTP")
+		var data strings.Builder
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch cmd := strings.ToUpper(strings.Fields(line)[0]); cmd {
+			case "EHLO", "HELO", "MAIL", "RCPT":
+				reply("250 OK")
+			case "DATA":
+				reply("354 go ahead")
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				received <- data.String()
+				reply("250 queued")
+			case "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("502 unsupported")
+			}
+		}
+	})
+
+	if err := sink.Notify(context.Background(), testNotification()); err != nil {
+		t.Fatal(err)
+	}
+	msg := <-received
+	if !strings.Contains(msg, "To: alice@example.com") || !strings.Contains(msg, "Renew the certificate") {
+		t.Errorf("unexpected message:\n%s", msg)
+	}
+}
+
+func TestSMTPSinkGivesUpOnUnresponsiveServer(t *testing.T) {
+	// The server accepts connections but never greets the client.
+	sink := listen(t, func(conn net.Conn) {
+		time.Sleep(10 * time.Second)
+		conn.Close()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := sink.Notify(ctx, testNotification()); err == nil {
+		t.Fatal("Notify() succeeded without a greeting")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Notify() returned after %v, want it to stop at the deadline of its context", elapsed)
+	}
+}
@@ -0,0 +1,75 @@
+package reminder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/events"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/webhook"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const webhookTimeout = 10 * time.Second
+
+type webhookSink struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewWebhookSink posts notifications as JSON to url, signing the body with
+// secret when it is not empty.
+func NewWebhookSink(url, secret string) Sink {
+	return &webhookSink{url: url, secret: secret, client: &http.Client{Timeout: webhookTimeout}}
+}
+
+type webhookPayload struct {
+	Type     events.Type     `json:"type"`
+	Time     string          `json:"time"`
+	Reminder json.RawMessage `json:"reminder"`
+	Task     json.RawMessage `json:"task"`
+}
+
+func (s *webhookSink) Notify(ctx context.Context, n *Notification) error {
+	reminder, err := protojson.Marshal(n.Reminder)
+	if err != nil {
+		return err
+	}
+	task, err := protojson.Marshal(n.Task)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(webhookPayload{
+		Type:     events.ReminderFired,
+		Time:     n.Time.UTC().Format(time.RFC3339),
+		Reminder: reminder,
+		Task:     task,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.secret != "" {
+		req.Header.Set(webhook.SignatureHeader, webhook.Sign(s.secret, body))
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/auth"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/models"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto/protoconnect"
+	"github.com/rs/cors"
+	"google.golang.org/grpc/status"
+)
+
+// Headers of the Connect, gRPC-Web and gRPC protocols that cross-origin
+// callers send and read, as listed by connectrpc.com/cors.
+var (
+	connectAllowedMethods = []string{http.MethodPost}
+	connectAllowedHeaders = []string{
+		"Content-Type",
+		"Connect-Protocol-Version",
+		"Connect-Timeout-Ms",
+		"Grpc-Timeout",
+		"X-Grpc-Web",
+		"X-User-Agent",
+		auth.UserHeader,
+	}
+	connectExposedHeaders = []string{
+		"Grpc-Status",
+		"Grpc-Message",
+		"Grpc-Status-Details-Bin",
+	}
+)
+
+// withCORS lets the browser apps of conf call handler from other origins,
+// answering their preflight requests. handler is returned as is when no
+// origin is allowed.
+func withCORS(conf *models.Connect, handler http.Handler) http.Handler {
+	if conf == nil || len(conf.AllowedOrigins) == 0 {
+		return handler
+	}
+	return cors.New(cors.Options{
+		AllowedOrigins: conf.AllowedOrigins,
+		AllowedMethods: connectAllowedMethods,
+		AllowedHeaders: connectAllowedHeaders,
+		ExposedHeaders: connectExposedHeaders,
+		MaxAge:         int((2 * time.Hour).Seconds()),
+	}).Handler(handler)
+}
+
+// connectServer serves the gateway's TaskService over the Connect, gRPC-Web
+// and gRPC protocols on the HTTP port, for browsers that cannot reach the
+// gRPC port. Callers are identified by the X-User-ID header like REST
+// requests.
+type connectServer struct {
+	tasks proto.TaskServiceServer
+}
+
+var _ protoconnect.TaskServiceHandler = (*connectServer)(nil)
+
+// unary calls the gRPC implementation of a method on behalf of the user of
+// req, converting its status error into a Connect error.
+func unary[Req, Res any](ctx context.Context, req *connect.Request[Req], call func(context.Context, *Req) (*Res, error)) (*connect.Response[Res], error) {
+	res, err := call(auth.WithUser(ctx, req.Header().Get(auth.UserHeader)), req.Msg)
+	if err != nil {
+		s := status.Convert(err)
+		return nil, connect.NewError(connect.Code(s.Code()), errors.New(s.Message()))
+	}
+	return connect.NewResponse(res), nil
+}
+
+func (s *connectServer) CreateTask(ctx context.Context, req *connect.Request[proto.CreateTaskRequest]) (*connect.Response[proto.CreateTaskResponse], error) {
+	return unary(ctx, req, s.tasks.CreateTask)
+}
+
+func (s *connectServer) GetTask(ctx context.Context, req *connect.Request[proto.GetTaskRequest]) (*connect.Response[proto.Task], error) {
+	return unary(ctx, req, s.tasks.GetTask)
+}
+
+func (s *connectServer) UpdateTask(ctx context.Context, req *connect.Request[proto.UpdateTaskRequest]) (*connect.Response[proto.UpdateTaskResponse], error) {
+	return unary(ctx, req, s.tasks.UpdateTask)
+}
+
+func (s *connectServer) DeleteTask(ctx context.Context, req *connect.Request[proto.DeleteTaskRequest]) (*connect.Response[proto.DeleteTaskResponse], error) {
+	return unary(ctx, req, s.tasks.DeleteTask)
+}
+
+func (s *connectServer) ListTasks(ctx context.Context, req *connect.Request[proto.ListTasksRequest]) (*connect.Response[proto.ListTasksResponse], error) {
+	return unary(ctx, req, s.tasks.ListTasks)
+}
+
+func (s *connectServer) SearchTasks(ctx context.Context, req *connect.Request[proto.SearchTasksRequest]) (*connect.Response[proto.SearchTasksResponse], error) {
+	return unary(ctx, req, s.tasks.SearchTasks)
+}
+
+func (s *connectServer) CreateLabel(ctx context.Context, req *connect.Request[proto.CreateLabelRequest]) (*connect.Response[proto.Label], error) {
+	return unary(ctx, req, s.tasks.CreateLabel)
+}
+
+func (s *connectServer) GetLabel(ctx context.Context, req *connect.Request[proto.GetLabelRequest]) (*connect.Response[proto.Label], error) {
+	return unary(ctx, req, s.tasks.GetLabel)
+}
+
+func (s *connectServer) UpdateLabel(ctx context.Context, req *connect.Request[proto.UpdateLabelRequest]) (*connect.Response[proto.Label], error) {
+	return unary(ctx, req, s.tasks.UpdateLabel)
+}
+
+func (s *connectServer) DeleteLabel(ctx context.Context, req *connect.Request[proto.DeleteLabelRequest]) (*connect.Response[proto.DeleteLabelResponse], error) {
+	return unary(ctx, req, s.tasks.DeleteLabel)
+}
+
+func (s *connectServer) ListLabels(ctx context.Context, req *connect.Request[proto.ListLabelsRequest]) (*connect.Response[proto.ListLabelsResponse], error) {
+	return unary(ctx, req, s.tasks.ListLabels)
+}
+
+func (s *connectServer) AttachLabels(ctx context.Context, req *connect.Request[proto.TaskLabelsRequest]) (*connect.Response[proto.TaskLabelsResponse], error) {
+	return unary(ctx, req, s.tasks.AttachLabels)
+}
+
+func (s *connectServer) DetachLabels(ctx context.Context, req *connect.Request[proto.TaskLabelsRequest]) (*connect.Response[proto.TaskLabelsResponse], error) {
+	return unary(ctx, req, s.tasks.DetachLabels)
+}
+
+func (s *connectServer) CreateComment(ctx context.Context, req *connect.Request[proto.CreateCommentRequest]) (*connect.Response[proto.Comment], error) {
+	return unary(ctx, req, s.tasks.CreateComment)
+}
+
+func (s *connectServer) GetComment(ctx context.Context, req *connect.Request[proto.GetCommentRequest]) (*connect.Response[proto.Comment], error) {
+	return unary(ctx, req, s.tasks.GetComment)
+}
+
+func (s *connectServer) UpdateComment(ctx context.Context, req *connect.Request[proto.UpdateCommentRequest]) (*connect.Response[proto.Comment], error) {
+	return unary(ctx, req, s.tasks.UpdateComment)
+}
+
+func (s *connectServer) DeleteComment(ctx context.Context, req *connect.Request[proto.DeleteCommentRequest]) (*connect.Response[proto.DeleteCommentResponse], error) {
+	return unary(ctx, req, s.tasks.DeleteComment)
+}
+
+func (s *connectServer) ListComments(ctx context.Context, req *connect.Request[proto.ListCommentsRequest]) (*connect.Response[proto.ListCommentsResponse], error) {
+	return unary(ctx, req, s.tasks.ListComments)
+}
+
+func (s *connectServer) AddAttachment(ctx context.Context, req *connect.Request[proto.AddAttachmentRequest]) (*connect.Response[proto.Attachment], error) {
+	return unary(ctx, req, s.tasks.AddAttachment)
+}
+
+func (s *connectServer) GetAttachment(ctx context.Context, req *connect.Request[proto.GetAttachmentRequest]) (*connect.Response[proto.Attachment], error) {
+	return unary(ctx, req, s.tasks.GetAttachment)
+}
+
+func (s *connectServer) DeleteAttachment(ctx context.Context, req *connect.Request[proto.DeleteAttachmentRequest]) (*connect.Response[proto.DeleteAttachmentResponse], error) {
+	return unary(ctx, req, s.tasks.DeleteAttachment)
+}
+
+func (s *connectServer) ListAttachments(ctx context.Context, req *connect.Request[proto.ListAttachmentsRequest]) (*connect.Response[proto.ListAttachmentsResponse], error) {
+	return unary(ctx, req, s.tasks.ListAttachments)
+}
+
+func (s *connectServer) AddDependency(ctx context.Context, req *connect.Request[proto.TaskDependencyRequest]) (*connect.Response[proto.TaskDependenciesResponse], error) {
+	return unary(ctx, req, s.tasks.AddDependency)
+}
+
+func (s *connectServer) RemoveDependency(ctx context.Context, req *connect.Request[proto.TaskDependencyRequest]) (*connect.Response[proto.TaskDependenciesResponse], error) {
+	return unary(ctx, req, s.tasks.RemoveDependency)
+}
+
+func (s *connectServer) CreateReminder(ctx context.Context, req *connect.Request[proto.CreateReminderRequest]) (*connect.Response[proto.Reminder], error) {
+	return unary(ctx, req, s.tasks.CreateReminder)
+}
+
+func (s *connectServer) GetReminder(ctx context.Context, req *connect.Request[proto.GetReminderRequest]) (*connect.Response[proto.Reminder], error) {
+	return unary(ctx, req, s.tasks.GetReminder)
+}
+
+func (s *connectServer) DeleteReminder(ctx context.Context, req *connect.Request[proto.DeleteReminderRequest]) (*connect.Response[proto.DeleteReminderResponse], error) {
+	return unary(ctx, req, s.tasks.DeleteReminder)
+}
+
+func (s *connectServer) ListReminders(ctx context.Context, req *connect.Request[proto.ListRemindersRequest]) (*connect.Response[proto.ListRemindersResponse], error) {
+	return unary(ctx, req, s.tasks.ListReminders)
+}
+
+func (s *connectServer) MarkReminderFired(ctx context.Context, req *connect.Request[proto.MarkReminderFiredRequest]) (*connect.Response[proto.Reminder], error) {
+	return unary(ctx, req, s.tasks.MarkReminderFired)
+}
+
+func (s *connectServer) RecordActivity(ctx context.Context, req *connect.Request[proto.RecordActivityRequest]) (*connect.Response[proto.Activity], error) {
+	return unary(ctx, req, s.tasks.RecordActivity)
+}
+
+func (s *connectServer) ListActivity(ctx context.Context, req *connect.Request[proto.ListActivityRequest]) (*connect.Response[proto.ListActivityResponse], error) {
+	return unary(ctx, req, s.tasks.ListActivity)
+}
+
+func (s *connectServer) CreateFeedToken(ctx context.Context, req *connect.Request[proto.CreateFeedTokenRequest]) (*connect.Response[proto.FeedToken], error) {
+	return unary(ctx, req, s.tasks.CreateFeedToken)
+}
+
+func (s *connectServer) GetFeedToken(ctx context.Context, req *connect.Request[proto.GetFeedTokenRequest]) (*connect.Response[proto.FeedToken], error) {
+	return unary(ctx, req, s.tasks.GetFeedToken)
+}
+
+func (s *connectServer) DeleteFeedToken(ctx context.Context, req *connect.Request[proto.DeleteFeedTokenRequest]) (*connect.Response[proto.DeleteFeedTokenResponse], error) {
+	return unary(ctx, req, s.tasks.DeleteFeedToken)
+}
+
+func (s *connectServer) ListFeedTokens(ctx context.Context, req *connect.Request[proto.ListFeedTokensRequest]) (*connect.Response[proto.ListFeedTokensResponse], error) {
+	return unary(ctx, req, s.tasks.ListFeedTokens)
+}
+
+func (s *connectServer) CreateWebhook(ctx context.Context, req *connect.Request[proto.CreateWebhookRequest]) (*connect.Response[proto.Webhook], error) {
+	return unary(ctx, req, s.tasks.CreateWebhook)
+}
+
+func (s *connectServer) GetWebhook(ctx context.Context, req *connect.Request[proto.GetWebhookRequest]) (*connect.Response[proto.Webhook], error) {
+	return unary(ctx, req, s.tasks.GetWebhook)
+}
+
+func (s *connectServer) UpdateWebhook(ctx context.Context, req *connect.Request[proto.UpdateWebhookRequest]) (*connect.Response[proto.Webhook], error) {
+	return unary(ctx, req, s.tasks.UpdateWebhook)
+}
+
+func (s *connectServer) DeleteWebhook(ctx context.Context, req *connect.Request[proto.DeleteWebhookRequest]) (*connect.Response[proto.DeleteWebhookResponse], error) {
+	return unary(ctx, req, s.tasks.DeleteWebhook)
+}
+
+func (s *connectServer) ListWebhooks(ctx context.Context, req *connect.Request[proto.ListWebhooksRequest]) (*connect.Response[proto.ListWebhooksResponse], error) {
+	return unary(ctx, req, s.tasks.ListWebhooks)
+}
+
+func (s *connectServer) CreateWebhookDelivery(ctx context.Context, req *connect.Request[proto.CreateWebhookDeliveryRequest]) (*connect.Response[proto.WebhookDelivery], error) {
+	return unary(ctx, req, s.tasks.CreateWebhookDelivery)
+}
+
+func (s *connectServer) GetWebhookDelivery(ctx context.Context, req *connect.Request[proto.GetWebhookDeliveryRequest]) (*connect.Response[proto.WebhookDelivery], error) {
+	return unary(ctx, req, s.tasks.GetWebhookDelivery)
+}
+
+func (s *connectServer) UpdateWebhookDelivery(ctx context.Context, req *connect.Request[proto.UpdateWebhookDeliveryRequest]) (*connect.Response[proto.WebhookDelivery], error) {
+	return unary(ctx, req, s.tasks.UpdateWebhookDelivery)
+}
+
+func (s *connectServer) ListWebhookDeliveries(ctx context.Context, req *connect.Request[proto.ListWebhookDeliveriesRequest]) (*connect.Response[proto.ListWebhookDeliveriesResponse], error) {
+	return unary(ctx, req, s.tasks.ListWebhookDeliveries)
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/models"
+)
+
+const connectProcedure = "/task.TaskService/GetTask"
+
+// preflight sends the preflight request of a Connect call from origin.
+func preflight(handler http.Handler, origin string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodOptions, connectProcedure, nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	req.Header.Set("Access-Control-Request-Headers", "connect-protocol-version,content-type,x-user-id")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestConnectCORS(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Grpc-Status", "0")
+	})
+	handler := withCORS(&models.Connect{AllowedOrigins: []string{"https://tasks.example.com"}}, next)
+
+	w := preflight(handler, "https://tasks.example.com")
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://tasks.example.com" {
+		t.Errorf("preflight of an allowed origin: Access-Control-Allow-Origin = %q", got)
+	}
+	allowed := strings.ToLower(w.Header().Get("Access-Control-Allow-Headers"))
+	for _, header := range []string{"content-type", "connect-protocol-version", "x-user-id"} {
+		if !strings.Contains(allowed, header) {
+			t.Errorf("preflight does not allow the %s header: %q", header, allowed)
+		}
+	}
+
+	if got := preflight(handler, "https://evil.example.com").Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("preflight of another origin: Access-Control-Allow-Origin = %q", got)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, connectProcedure, nil)
+	req.Header.Set("Origin", "https://tasks.example.com")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if got := w.Header().Get("Access-Control-Expose-Headers"); !strings.Contains(got, "Grpc-Status") {
+		t.Errorf("Access-Control-Expose-Headers = %q, want the gRPC-Web status headers", got)
+	}
+}
+
+func TestConnectCORSDisabled(t *testing.T) {
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+	for _, conf := range []*models.Connect{nil, {}} {
+		if got := preflight(withCORS(conf, next), "https://tasks.example.com").Header().Get("Access-Control-Allow-Origin"); got != "" {
+			t.Errorf("withCORS(%v): Access-Control-Allow-Origin = %q, want none", conf, got)
+		}
+	}
+}
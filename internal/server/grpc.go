@@ -0,0 +1,63 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/auth"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/models"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/ratelimit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// newGRPCServer serves tasks. Callers are identified by the user in the
+// x-user-id metadata, which the authenticating proxy sets like the
+// X-User-ID header of REST requests, and share their rate limit with the
+// REST API.
+func newGRPCServer(tasks proto.TaskServiceServer, limiter *ratelimit.Limiter) *grpc.Server {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(authenticate, limitRate(limiter)))
+	proto.RegisterTaskServiceServer(server, tasks)
+	return server
+}
+
+func authenticate(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	user := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(auth.UserMetadata); len(values) > 0 {
+			user = values[0]
+		}
+	}
+	return handler(auth.WithUser(ctx, user), req)
+}
+
+func limitRate(limiter *ratelimit.Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		addr := ""
+		if p, ok := peer.FromContext(ctx); ok {
+			addr, _, _ = net.SplitHostPort(p.Addr.String())
+		}
+		if !limiter.Allow(ratelimit.Key(auth.UserFromContext(ctx), addr)) {
+			return nil, status.Error(codes.ResourceExhausted, rateLimitExceeded)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StartGRPC serves server on the address in conf.
+func StartGRPC(server *grpc.Server, conf *models.GRPCServer) {
+	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%s", conf.Host, conf.Port))
+	if err != nil {
+		log.Printf("Failed to listen for gRPC: %v", err)
+		return
+	}
+	if err := server.Serve(lis); err != nil {
+		log.Printf("gRPC server stopped: %v", err)
+	}
+}
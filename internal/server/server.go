@@ -1,19 +1,71 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"time"
 
+	"github.com/bhupeshpandey/task-manager-nashville/internal/activity"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/auth"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/blob"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/events"
 	"github.com/bhupeshpandey/task-manager-nashville/internal/handlers"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/models"
 	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto/protoconnect"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/ratelimit"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/recurrence"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/reminder"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/webhook"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/workflow"
+	"google.golang.org/grpc"
 	//"github.com/gorilla/mux"
 	//"path/to/internal/handler"
 )
 
-func NewHTTPServer(grpcClient proto.TaskServiceClient) *http.Server {
-	taskHandler := handlers.NewTaskHandler(grpcClient)
+const rateLimitExceeded = "rate limit exceeded"
+
+// NewServers creates the HTTP server, which serves the REST API and
+// TaskService over Connect and gRPC-Web, and, when conf.GatewayGrpc is set,
+// the gRPC server of the gateway. All of them forward to grpcClient.
+func NewServers(grpcClient proto.TaskServiceClient, conf *models.Config) (*http.Server, *grpc.Server, error) {
+	stateMachine, err := workflow.New(conf.Workflow)
+	if err != nil {
+		return nil, nil, err
+	}
+	attachments := conf.Attachments
+	if attachments == nil {
+		attachments = handlers.DefaultAttachments
+	}
+	blobs, err := blob.New(&attachments.Store)
+	if err != nil {
+		return nil, nil, err
+	}
+	bus := events.NewBus()
+	client := events.NewClient(grpcClient, bus)
+	go recurrence.NewService(client, stateMachine).Run(context.Background(), bus)
+
+	reminders := conf.Reminders
+	if reminders == nil {
+		reminders = &models.Reminders{Sinks: models.ReminderSinks{WebSocket: true}}
+	}
+	sinks, err := reminder.NewSinks(&reminders.Sinks, bus)
+	if err != nil {
+		return nil, nil, err
+	}
+	scheduler := reminder.NewScheduler(client, sinks, reminders.PollInterval)
+	go scheduler.Run(context.Background())
+	recorder := activity.NewRecorder(grpcClient)
+	recorder.Observe(bus)
+	go recorder.Run(context.Background())
+	dispatcher := webhook.NewDispatcher(grpcClient, conf.Webhooks)
+	dispatcher.Observe(bus)
+	go dispatcher.Run(context.Background())
+	taskHandler := handlers.NewTaskHandler(client, stateMachine, bus, blobs, attachments, scheduler, dispatcher, conf.GitHub, conf.Admins)
+	tasks := taskHandler.TaskServer()
+	limiter := ratelimit.New(conf.RateLimit)
 
 	// create the new Gin engine and setup middleware handler chain
 	ge := gin.New()
@@ -21,16 +73,47 @@ func NewHTTPServer(grpcClient proto.TaskServiceClient) *http.Server {
 	ge.UseRawPath = true
 
 	wsRouter := ge.Group(fmt.Sprintf("/service/%s", "v1"))
+	wsRouter.Use(limitRequests(limiter))
 
 	// route GET /healthz status getHealthz
 	taskHandler.AddServiceRoutes(wsRouter, AddServiceRoutes)
+
+	// serve TaskService to browsers over Connect and gRPC-Web, answering the
+	// preflight requests of the allowed origins
+	connectPath, connectHandler := protoconnect.NewTaskServiceHandler(&connectServer{tasks: tasks})
+	connectHandler = withCORS(conf.Connect, connectHandler)
+	ge.POST(connectPath+"*procedure", limitRequests(limiter), gin.WrapH(connectHandler))
+	ge.OPTIONS(connectPath+"*procedure", gin.WrapH(connectHandler))
 	server := &http.Server{
-		Addr:         fmt.Sprintf(":%s", "50059"),
+		Addr:         httpAddr(conf.HttpServer),
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		Handler:      ge,
 	}
-	return server
+	if conf.GatewayGrpc == nil {
+		return server, nil, nil
+	}
+	return server, newGRPCServer(tasks, limiter), nil
+}
+
+// limitRequests answers 429 to callers over their rate limit.
+func limitRequests(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !limiter.Allow(ratelimit.Key(c.GetHeader(auth.UserHeader), c.ClientIP())) {
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": rateLimitExceeded})
+			return
+		}
+		c.Next()
+	}
+}
+
+// httpAddr is the listen address of the REST API, port 50059 on every
+// interface unless configured.
+func httpAddr(conf *models.HTTPServer) string {
+	if conf == nil || conf.Port == "" {
+		return fmt.Sprintf(":%s", models.DefaultHTTPPort)
+	}
+	return fmt.Sprintf("%s:%s", conf.Host, conf.Port)
 }
 
 func AddServiceRoutes(wsRouter *gin.RouterGroup, method string, path string, handler func(c *gin.Context)) {
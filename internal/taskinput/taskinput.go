@@ -0,0 +1,99 @@
+// Package taskinput parses the task fields clients give as text, so that
+// the gateway and the command line read them alike.
+package taskinput
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DateLayout is the layout of date-only values.
+const DateLayout = "2006-01-02"
+
+const priorityPrefix = "PRIORITY_"
+
+// ParseDueAt parses a due date given as RFC 3339 or as a date in the named
+// time zone, UTC when timezone is empty. A date-only due date falls due at
+// the end of that day.
+func ParseDueAt(value, timezone string) (*timestamppb.Timestamp, error) {
+	if value == "" {
+		return nil, nil
+	}
+	loc, err := LoadLocation(timezone)
+	if err != nil {
+		return nil, err
+	}
+	t, err := ParseTime(value, loc, true)
+	if err != nil {
+		return nil, fmt.Errorf("invalid due_at: %w", err)
+	}
+	return timestamppb.New(t), nil
+}
+
+// ParseTime accepts RFC 3339 timestamps and date-only values. A date-only
+// value is read in loc and resolves to the start of the day, or to its last
+// second when endOfDay is set.
+func ParseTime(value string, loc *time.Location, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation(DateLayout, value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither RFC 3339 nor YYYY-MM-DD", value)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1).Add(-time.Second)
+	}
+	return t, nil
+}
+
+// LoadLocation resolves an IANA time zone name. The empty name is UTC.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	return loc, nil
+}
+
+// ParsePriority maps "low", "medium", "high" and "urgent" to a Priority. The
+// empty string leaves the priority unspecified.
+func ParsePriority(s string) (proto.Priority, error) {
+	if s == "" {
+		return proto.Priority_PRIORITY_UNSPECIFIED, nil
+	}
+	p, ok := proto.Priority_value[priorityPrefix+strings.ToUpper(s)]
+	if !ok || p == int32(proto.Priority_PRIORITY_UNSPECIFIED) {
+		return 0, fmt.Errorf("unknown priority %q", s)
+	}
+	return proto.Priority(p), nil
+}
+
+// PriorityName is the name ParsePriority reads p from, and the empty string
+// for an unspecified priority.
+func PriorityName(p proto.Priority) string {
+	if p == proto.Priority_PRIORITY_UNSPECIFIED {
+		return ""
+	}
+	return strings.ToLower(strings.TrimPrefix(p.String(), priorityPrefix))
+}
+
+// PriorityNames lists the names of the priorities in alphabetical order.
+func PriorityNames() []string {
+	var names []string
+	for _, value := range proto.Priority_value {
+		if name := PriorityName(proto.Priority(value)); name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
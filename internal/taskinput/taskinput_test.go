@@ -0,0 +1,57 @@
+package taskinput
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDueAt(t *testing.T) {
+	for _, tc := range []struct {
+		value, timezone string
+		want            time.Time
+	}{
+		{"2026-10-20", "", time.Date(2026, 10, 20, 23, 59, 59, 0, time.UTC)},
+		{"2026-10-20", "America/Chicago", time.Date(2026, 10, 21, 4, 59, 59, 0, time.UTC)},
+		{"2026-10-20T09:00:00+02:00", "America/Chicago", time.Date(2026, 10, 20, 7, 0, 0, 0, time.UTC)},
+	} {
+		got, err := ParseDueAt(tc.value, tc.timezone)
+		if err != nil {
+			t.Fatalf("ParseDueAt(%q, %q): %v", tc.value, tc.timezone, err)
+		}
+		if !got.AsTime().Equal(tc.want) {
+			t.Errorf("ParseDueAt(%q, %q) = %v, want %v", tc.value, tc.timezone, got.AsTime(), tc.want)
+		}
+	}
+	for _, value := range []string{"tomorrow", "20/10/2026"} {
+		if _, err := ParseDueAt(value, ""); err == nil {
+			t.Errorf("ParseDueAt(%q) succeeded", value)
+		}
+	}
+	if _, err := ParseDueAt("2026-10-20", "Mars/Olympus"); err == nil {
+		t.Error("ParseDueAt accepted an unknown time zone")
+	}
+}
+
+func TestPriorityNames(t *testing.T) {
+	names := PriorityNames()
+	if len(names) != 4 {
+		t.Errorf("PriorityNames() = %q, want the four priorities", names)
+	}
+	for _, name := range names {
+		p, err := ParsePriority(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := PriorityName(p); got != name {
+			t.Errorf("PriorityName(ParsePriority(%q)) = %q", name, got)
+		}
+	}
+	for _, name := range []string{"unspecified", "critical"} {
+		if _, err := ParsePriority(name); err == nil {
+			t.Errorf("ParsePriority(%q) succeeded", name)
+		}
+	}
+	if p, err := ParsePriority(""); err != nil || PriorityName(p) != "" {
+		t.Errorf("ParsePriority(\"\") = %v, %v, want the unspecified priority", p, err)
+	}
+}
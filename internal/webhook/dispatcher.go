@@ -0,0 +1,302 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/events"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/models"
+	"github.com/bhupeshpandey/task-manager-nashville/internal/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	gproto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	DefaultPollInterval   = 30 * time.Second
+	DefaultMaxAttempts    = 10
+	DefaultInitialBackoff = 30 * time.Second
+	DefaultMaxBackoff     = 6 * time.Hour
+	DefaultTimeout        = 10 * time.Second
+
+	listPageSize   = 100
+	enqueueTimeout = 5 * time.Second
+	// eventQueueSize bounds the published events waiting to be queued for
+	// the webhooks.
+	eventQueueSize = 1024
+	// deliveryConcurrency bounds the deliveries attempted at once.
+	deliveryConcurrency = 4
+)
+
+// Dispatcher queues the events published on a bus for the webhooks
+// subscribed to them and delivers the queue.
+type Dispatcher struct {
+	client proto.TaskServiceClient
+	conf   models.Webhooks
+	http   *http.Client
+	wake   chan struct{}
+	events chan events.Event
+}
+
+// NewDispatcher keeps its queue behind client. Zero settings of conf take
+// their defaults.
+func NewDispatcher(client proto.TaskServiceClient, conf *models.Webhooks) *Dispatcher {
+	d := &Dispatcher{client: client, wake: make(chan struct{}, 1), events: make(chan events.Event, eventQueueSize)}
+	if conf != nil {
+		d.conf = *conf
+	}
+	if d.conf.PollInterval <= 0 {
+		d.conf.PollInterval = DefaultPollInterval
+	}
+	if d.conf.MaxAttempts <= 0 {
+		d.conf.MaxAttempts = DefaultMaxAttempts
+	}
+	if d.conf.InitialBackoff <= 0 {
+		d.conf.InitialBackoff = DefaultInitialBackoff
+	}
+	if d.conf.MaxBackoff <= 0 {
+		d.conf.MaxBackoff = DefaultMaxBackoff
+	}
+	if d.conf.Timeout <= 0 {
+		d.conf.Timeout = DefaultTimeout
+	}
+	d.http = &http.Client{Timeout: d.conf.Timeout}
+	return d
+}
+
+// Observe hands the events published on bus from now on to Run, which
+// queues them for the webhooks subscribed to them. Events are dropped, and
+// logged, while Run is too far behind.
+func (d *Dispatcher) Observe(bus *events.Bus) {
+	bus.Observe(func(e events.Event) {
+		select {
+		case d.events <- e:
+		default:
+			log.Printf("Dropping %s event of task %s: the webhook event queue is full", e.Type, e.TaskID)
+		}
+	})
+}
+
+func (d *Dispatcher) enqueue(ctx context.Context, e events.Event) {
+	ctx, cancel := context.WithTimeout(ctx, enqueueTimeout)
+	defer cancel()
+	res, err := d.client.ListWebhooks(ctx, &proto.ListWebhooksRequest{EventType: string(e.Type)})
+	if err != nil {
+		log.Printf("Failed to list the webhooks of %s events: %v", e.Type, err)
+		return
+	}
+	if len(res.Webhooks) == 0 {
+		return
+	}
+	body, err := Payload(&e)
+	if err != nil {
+		log.Printf("Failed to encode %s event of task %s: %v", e.Type, e.TaskID, err)
+		return
+	}
+	for _, hook := range res.Webhooks {
+		_, err := d.client.CreateWebhookDelivery(ctx, &proto.CreateWebhookDeliveryRequest{
+			Delivery: &proto.WebhookDelivery{
+				WebhookId:     hook.Id,
+				EventType:     string(e.Type),
+				TaskId:        e.TaskID,
+				Payload:       body,
+				Status:        StatusPending,
+				NextAttemptAt: timestamppb.New(e.Time),
+			},
+		})
+		if err != nil {
+			log.Printf("Failed to queue %s event of task %s for webhook %s: %v", e.Type, e.TaskID, hook.Id, err)
+		}
+	}
+	d.Wake()
+}
+
+// Redeliver queues the payload of delivery again as a new delivery, which
+// gets a fresh set of attempts.
+func (d *Dispatcher) Redeliver(ctx context.Context, delivery *proto.WebhookDelivery) (*proto.WebhookDelivery, error) {
+	redelivery, err := d.client.CreateWebhookDelivery(ctx, &proto.CreateWebhookDeliveryRequest{
+		Delivery: &proto.WebhookDelivery{
+			WebhookId:     delivery.WebhookId,
+			EventType:     delivery.EventType,
+			TaskId:        delivery.TaskId,
+			Payload:       delivery.Payload,
+			Status:        StatusPending,
+			NextAttemptAt: timestamppb.Now(),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	d.Wake()
+	return redelivery, nil
+}
+
+// Wake makes the dispatcher look at the queue again.
+func (d *Dispatcher) Wake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run queues the observed events and delivers the queue until ctx is done.
+// It sleeps until the next pending delivery is due, or for the poll
+// interval at most.
+func (d *Dispatcher) Run(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-d.events:
+			// enqueue wakes the dispatcher when it queued deliveries.
+			d.enqueue(ctx, e)
+			continue
+		case <-timer.C:
+		case <-d.wake:
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+		}
+		d.deliverDue(ctx)
+		timer.Reset(d.untilNext(ctx))
+	}
+}
+
+func (d *Dispatcher) deliverDue(ctx context.Context) {
+	now := time.Now()
+	var due []*proto.WebhookDelivery
+	for page := int32(0); ; page++ {
+		res, err := d.client.ListWebhookDeliveries(ctx, &proto.ListWebhookDeliveriesRequest{
+			Statuses:          []string{StatusPending},
+			NextAttemptBefore: timestamppb.New(now),
+			Page:              page,
+			PageSize:          listPageSize,
+		})
+		if err != nil {
+			log.Printf("Failed to list due webhook deliveries: %v", err)
+			return
+		}
+		due = append(due, res.Deliveries...)
+		if len(res.Deliveries) < listPageSize {
+			break
+		}
+	}
+
+	hooks := map[string]*proto.Webhook{}
+	sem := make(chan struct{}, deliveryConcurrency)
+	var wg sync.WaitGroup
+	for _, delivery := range due {
+		hook, ok := hooks[delivery.WebhookId]
+		if !ok {
+			var err error
+			hook, err = d.client.GetWebhook(ctx, &proto.GetWebhookRequest{Id: delivery.WebhookId})
+			if err != nil && status.Code(err) != codes.NotFound {
+				log.Printf("Failed to read webhook %s: %v", delivery.WebhookId, err)
+				continue
+			}
+			hooks[delivery.WebhookId] = hook
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(delivery *proto.WebhookDelivery) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			d.attempt(ctx, hook, delivery)
+		}(delivery)
+	}
+	wg.Wait()
+}
+
+// attempt posts the delivery to hook and records the outcome. Deliveries of
+// deleted or inactive webhooks are given up at once.
+func (d *Dispatcher) attempt(ctx context.Context, hook *proto.Webhook, delivery *proto.WebhookDelivery) {
+	delivery = gproto.Clone(delivery).(*proto.WebhookDelivery)
+	start := time.Now()
+	attempt := &proto.WebhookAttempt{Time: timestamppb.New(start)}
+	switch {
+	case hook == nil:
+		attempt.Error = "the webhook was deleted"
+	case !hook.Active:
+		attempt.Error = "the webhook is inactive"
+	default:
+		attempt.StatusCode, attempt.Error = d.post(ctx, hook, delivery)
+		attempt.DurationMs = time.Since(start).Milliseconds()
+	}
+	delivery.Attempts = append(delivery.Attempts, attempt)
+
+	switch {
+	case attempt.Error == "":
+		delivery.Status = StatusDelivered
+		delivery.DeliveredAt = attempt.Time
+		delivery.NextAttemptAt = nil
+	case hook == nil || !hook.Active || len(delivery.Attempts) >= d.conf.MaxAttempts:
+		log.Printf("Giving up on webhook delivery %s after %d attempts: %s", delivery.Id, len(delivery.Attempts), attempt.Error)
+		delivery.Status = StatusDead
+		delivery.NextAttemptAt = nil
+	default:
+		delivery.NextAttemptAt = timestamppb.New(start.Add(d.backoff(len(delivery.Attempts))))
+	}
+	if _, err := d.client.UpdateWebhookDelivery(ctx, &proto.UpdateWebhookDeliveryRequest{Delivery: delivery}); err != nil {
+		log.Printf("Failed to record webhook delivery %s: %v", delivery.Id, err)
+	}
+}
+
+// post sends the payload and returns the response status code and the
+// error of a failed attempt.
+func (d *Dispatcher) post(ctx context.Context, hook *proto.Webhook, delivery *proto.WebhookDelivery) (int32, string) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err.Error()
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, delivery.Id)
+	if hook.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(hook.Secret, delivery.Payload))
+	}
+	resp, err := d.http.Do(req)
+	if err != nil {
+		return 0, err.Error()
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return int32(resp.StatusCode), fmt.Sprintf("webhook responded %s", resp.Status)
+	}
+	return int32(resp.StatusCode), ""
+}
+
+// backoff returns the wait after the given number of failed attempts.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	wait := d.conf.InitialBackoff
+	for i := 1; i < attempts && wait < d.conf.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, d.conf.MaxBackoff)
+}
+
+// untilNext returns how long to sleep before the next pending delivery is
+// due.
+func (d *Dispatcher) untilNext(ctx context.Context) time.Duration {
+	res, err := d.client.ListWebhookDeliveries(ctx, &proto.ListWebhookDeliveriesRequest{
+		Statuses:         []string{StatusPending},
+		NextAttemptAfter: timestamppb.Now(),
+		PageSize:         1,
+	})
+	if err != nil || len(res.Deliveries) == 0 || res.Deliveries[0].NextAttemptAt == nil {
+		return d.conf.PollInterval
+	}
+	return min(time.Until(res.Deliveries[0].NextAttemptAt.AsTime()), d.conf.PollInterval)
+}
@@ -0,0 +1,94 @@
+// Package webhook delivers task events to subscribed URLs. Deliveries are
+// queued in the task service before they are attempted, so retries survive
+// restarts.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/events"
+	"google.golang.org/protobuf/encoding/protojson"
+	gproto "google.golang.org/protobuf/proto"
+)
+
+// Headers of webhook requests.
+const (
+	// SignatureHeader carries "sha256=" and the hex HMAC-SHA256 of the body.
+	SignatureHeader = "X-Nashville-Signature"
+	EventHeader     = "X-Nashville-Event"
+	DeliveryHeader  = "X-Nashville-Delivery"
+)
+
+// AllEvents subscribes a webhook to every event type.
+const AllEvents = "*"
+
+// Statuses of deliveries.
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusDead      = "dead"
+)
+
+// Sign returns the signature header value of body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidEventType reports whether a webhook can subscribe to t.
+func ValidEventType(t string) bool {
+	if t == AllEvents {
+		return true
+	}
+	for _, known := range events.Types {
+		if string(known) == t {
+			return true
+		}
+	}
+	return false
+}
+
+type payload struct {
+	Type     events.Type     `json:"type"`
+	TaskID   string          `json:"task_id"`
+	Actor    string          `json:"actor,omitempty"`
+	Time     string          `json:"time"`
+	Task     json.RawMessage `json:"task,omitempty"`
+	Previous json.RawMessage `json:"previous,omitempty"`
+	Comment  json.RawMessage `json:"comment,omitempty"`
+	Reminder json.RawMessage `json:"reminder,omitempty"`
+}
+
+// Payload renders e as the JSON body of a delivery.
+func Payload(e *events.Event) ([]byte, error) {
+	p := payload{
+		Type:   e.Type,
+		TaskID: e.TaskID,
+		Actor:  e.Actor,
+		Time:   e.Time.UTC().Format(time.RFC3339),
+	}
+	for _, field := range []struct {
+		dst *json.RawMessage
+		msg gproto.Message
+	}{
+		{&p.Task, e.Task},
+		{&p.Previous, e.Previous},
+		{&p.Comment, e.Comment},
+		{&p.Reminder, e.Reminder},
+	} {
+		if field.msg == nil || !field.msg.ProtoReflect().IsValid() {
+			continue
+		}
+		b, err := protojson.Marshal(field.msg)
+		if err != nil {
+			return nil, err
+		}
+		*field.dst = b
+	}
+	return json.Marshal(p)
+}
@@ -0,0 +1,155 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/bhupeshpandey/task-manager-nashville/internal/models"
+)
+
+var (
+	// ErrUnknownStatus is returned for statuses outside of the workflow.
+	ErrUnknownStatus = errors.New("unknown status")
+	// ErrIllegalTransition is returned when the workflow has no transition
+	// between two statuses.
+	ErrIllegalTransition = errors.New("illegal status transition")
+)
+
+// DefaultWorkflow is used when the configuration does not define one.
+var DefaultWorkflow = &models.Workflow{
+	Initial: "todo",
+	Final:   []string{"done"},
+	Transitions: map[string][]string{
+		"todo":        {"in_progress", "done"},
+		"in_progress": {"todo", "blocked", "done"},
+		"blocked":     {"in_progress"},
+		"done":        {"todo"},
+	},
+}
+
+// StateMachine validates task statuses and the transitions between them.
+type StateMachine struct {
+	initial     string
+	completed   string
+	final       map[string]struct{}
+	transitions map[string]map[string]struct{}
+}
+
+// New builds a StateMachine from conf, falling back to DefaultWorkflow when
+// conf is nil.
+func New(conf *models.Workflow) (*StateMachine, error) {
+	if conf == nil {
+		conf = DefaultWorkflow
+	}
+
+	m := &StateMachine{
+		initial:     conf.Initial,
+		final:       make(map[string]struct{}),
+		transitions: make(map[string]map[string]struct{}),
+	}
+	for from, targets := range conf.Transitions {
+		if from == "" {
+			return nil, errors.New("workflow: empty status")
+		}
+		if m.transitions[from] == nil {
+			m.transitions[from] = make(map[string]struct{})
+		}
+		for _, to := range targets {
+			if to == "" {
+				return nil, fmt.Errorf("workflow: empty transition target from %q", from)
+			}
+			m.transitions[from][to] = struct{}{}
+			if m.transitions[to] == nil {
+				m.transitions[to] = make(map[string]struct{})
+			}
+		}
+	}
+	if !m.Valid(m.initial) {
+		return nil, fmt.Errorf("workflow: initial status %q is not part of the transitions", m.initial)
+	}
+	for _, status := range conf.Final {
+		if !m.Valid(status) {
+			return nil, fmt.Errorf("workflow: final status %q is not part of the transitions", status)
+		}
+		m.final[status] = struct{}{}
+	}
+	if len(conf.Final) > 0 {
+		m.completed = conf.Final[0]
+	}
+	return m, nil
+}
+
+// Initial returns the status of newly created tasks.
+func (m *StateMachine) Initial() string {
+	return m.initial
+}
+
+// Valid reports whether status belongs to the workflow.
+func (m *StateMachine) Valid(status string) bool {
+	_, ok := m.transitions[status]
+	return ok
+}
+
+// Statuses returns every status of the workflow in lexical order.
+func (m *StateMachine) Statuses() []string {
+	statuses := make([]string, 0, len(m.transitions))
+	for status := range m.transitions {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	return statuses
+}
+
+// IsFinal reports whether status counts as completed.
+func (m *StateMachine) IsFinal(status string) bool {
+	_, ok := m.final[status]
+	return ok
+}
+
+// FinalStatuses returns the completed statuses in lexical order.
+func (m *StateMachine) FinalStatuses() []string {
+	statuses := make([]string, 0, len(m.final))
+	for status := range m.final {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	return statuses
+}
+
+// CompletedStatus returns the first configured final status, which tasks
+// are given when they are completed outside the workflow, such as checked
+// outline items. ok is false when the workflow has no final status.
+func (m *StateMachine) CompletedStatus() (status string, ok bool) {
+	return m.completed, m.completed != ""
+}
+
+// Next returns the statuses reachable from status in lexical order.
+func (m *StateMachine) Next(status string) []string {
+	next := make([]string, 0, len(m.transitions[m.normalize(status)]))
+	for to := range m.transitions[m.normalize(status)] {
+		next = append(next, to)
+	}
+	sort.Strings(next)
+	return next
+}
+
+// Transition checks that a task may move from one status to another. Tasks
+// created before statuses existed have no status and count as Initial.
+func (m *StateMachine) Transition(from, to string) error {
+	from = m.normalize(from)
+	if !m.Valid(to) {
+		return fmt.Errorf("%w %q", ErrUnknownStatus, to)
+	}
+	if _, ok := m.transitions[from][to]; !ok {
+		return fmt.Errorf("%w from %q to %q", ErrIllegalTransition, from, to)
+	}
+	return nil
+}
+
+func (m *StateMachine) normalize(status string) string {
+	if status == "" {
+		return m.initial
+	}
+	return status
+}